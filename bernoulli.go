@@ -0,0 +1,73 @@
+package bigfloat
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+)
+
+var bernoulliCache atomic.Value // []*big.Rat
+var bernoulliMu sync.Mutex      // writers only
+
+// bernoulli returns a slice b such that b[k] is the Bernoulli number B_2k for
+// every k ≤ n. The returned slice and its elements must not be modified. It is
+// safe to call this concurrently.
+func bernoulli(n int) []*big.Rat {
+	b, _ := bernoulliCache.Load().([]*big.Rat)
+	if len(b) > n {
+		return b
+	}
+	bernoulliMu.Lock()
+	defer bernoulliMu.Unlock()
+	b, _ = bernoulliCache.Load().([]*big.Rat)
+	if len(b) > n {
+		return b
+	}
+	// Grow geometrically so that repeated small extensions don't each cost a
+	// full recomputation.
+	if n < 2*len(b) {
+		n = 2 * len(b)
+	}
+	if n < 16 {
+		n = 16
+	}
+	b = bernoulliCalc(n)
+	bernoulliCache.Store(b)
+	return b
+}
+
+// bernoulliCalc computes B_0, B_2, ..., B_2n from the tangent numbers, using
+// the algorithm of R. P. Brent and D. Harvey, Fast computation of Bernoulli,
+// Tangent and Secant numbers, 2011, Section 6:
+//
+//	B_2k = (-1)**(k-1) 2k T_k / (2**2k (2**2k - 1)).
+func bernoulliCalc(n int) []*big.Rat {
+	// t[k] becomes the tangent number T_k.
+	t := make([]*big.Int, n+1)
+	t[1] = big.NewInt(1)
+	for k := 2; k <= n; k++ {
+		t[k] = new(big.Int).Mul(t[k-1], big.NewInt(int64(k-1)))
+	}
+	var u big.Int
+	for k := 2; k <= n; k++ {
+		for j := k; j <= n; j++ {
+			// T_j = (j-k) T_(j-1) + (j-k+2) T_j
+			u.Mul(t[j-1], big.NewInt(int64(j-k)))
+			t[j].Mul(t[j], big.NewInt(int64(j-k+2)))
+			t[j].Add(t[j], &u)
+		}
+	}
+
+	b := make([]*big.Rat, n+1)
+	b[0] = big.NewRat(1, 1)
+	for k := 1; k <= n; k++ {
+		num := new(big.Int).Mul(t[k], big.NewInt(int64(2*k)))
+		if k%2 == 0 {
+			num.Neg(num)
+		}
+		den := new(big.Int).Lsh(big.NewInt(1), uint(2*k))
+		den.Mul(den, u.Sub(den, big.NewInt(1)))
+		b[k] = new(big.Rat).SetFrac(num, den)
+	}
+	return b
+}
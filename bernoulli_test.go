@@ -0,0 +1,24 @@
+package bigfloat
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBernoulli(t *testing.T) {
+	want := []string{"1", "1/6", "-1/30", "1/42", "-1/30", "5/66", "-691/2730", "7/6", "-3617/510", "43867/798", "-174611/330"}
+	b := bernoulli(len(want) - 1)
+	for k, s := range want {
+		w, _ := new(big.Rat).SetString(s)
+		if b[k].Cmp(w) != 0 {
+			t.Errorf("B_%d = %v, want %v", 2*k, b[k], w)
+		}
+	}
+	// Extending the cache must not change existing values.
+	c := bernoulli(4 * len(b))
+	for k := range b {
+		if b[k].Cmp(c[k]) != 0 {
+			t.Errorf("B_%d changed after extending cache: was %v, now %v", 2*k, b[k], c[k])
+		}
+	}
+}
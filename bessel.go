@@ -0,0 +1,566 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// BesselJ sets o to the Bessel function of the first kind J_ν(x) of order nu
+// to o's precision and returns o. If o's precision is zero, then it is given
+// the greater of the precisions of nu and x.
+//
+// The result has full relative precision throughout the domain, including
+// near the zeros of J_ν. For small x, it is computed from the power series,
+// and for x large compared to both the precision and the order, from Hankel's
+// asymptotic expansion. In between, the series loses about 1.44x bits to
+// cancellation, which is made up with extra working precision.
+//
+// BesselJ(ν, ±Inf) = 0. At x = 0, the result is 1 for ν = 0, zero for ν > 0
+// or integer ν, and infinite for other ν < 0. Panics with ErrNaN if nu is
+// infinite or if x is negative and nu is not an integer.
+func BesselJ(o, nu, x *big.Float) *big.Float {
+	return bessel(o, nu, x, besselKindJ)
+}
+
+// BesselY sets o to the Bessel function of the second kind Y_ν(x) of order nu
+// to o's precision and returns o. If o's precision is zero, then it is given
+// the greater of the precisions of nu and x.
+//
+// As with BesselJ, the result has full relative precision, including near
+// the zeros of Y_ν. For integer orders, the power series involves the digamma
+// function; for other orders, Y_ν is computed from J_ν and J₋ᵥ, with extra
+// precision when ν is close to an integer. Orders which are integers too large
+// to fit in an int64 are not supported and cause a panic unless x is large
+// enough for the asymptotic expansion.
+//
+// BesselY(ν, +Inf) = 0, and BesselY(ν, 0) is infinite, except that it is zero
+// when ν is a negative half-integer. Panics with ErrNaN if nu is infinite or
+// if x is negative.
+func BesselY(o, nu, x *big.Float) *big.Float {
+	return bessel(o, nu, x, besselKindY)
+}
+
+// BesselI sets o to the modified Bessel function of the first kind I_ν(x) of
+// order nu to o's precision and returns o. If o's precision is zero, then it
+// is given the greater of the precisions of nu and x.
+//
+// For ν ≥ 0 and x > 0, the terms of the power series are all positive, so it
+// has no cancellation; the asymptotic expansion is used instead for x large
+// compared to the precision and the order.
+//
+// BesselI(ν, +Inf) = +Inf, and BesselI(n, -Inf) = ±Inf for integer n. At
+// x = 0, the result is the same as for BesselJ. Panics with ErrNaN if nu is
+// infinite or if x is negative and nu is not an integer.
+func BesselI(o, nu, x *big.Float) *big.Float {
+	return bessel(o, nu, x, besselKindI)
+}
+
+// BesselK sets o to the modified Bessel function of the second kind K_ν(x) of
+// order nu to o's precision and returns o. If o's precision is zero, then it
+// is given the greater of the precisions of nu and x.
+//
+// K_ν(x) decreases like exp(-x), while the power series in terms of I_ν
+// grows like exp(x), so for moderately large x where the asymptotic
+// expansion does not apply, the series needs about 2.89x extra bits. Orders
+// are subject to the same restriction as for BesselY.
+//
+// BesselK(ν, +Inf) = 0 and BesselK(ν, 0) = +Inf. Panics with ErrNaN if nu is
+// infinite or if x is negative.
+func BesselK(o, nu, x *big.Float) *big.Float {
+	return bessel(o, nu, x, besselKindK)
+}
+
+// besselKind selects one of the four Bessel functions.
+type besselKind int
+
+const (
+	besselKindJ besselKind = iota
+	besselKindY
+	besselKindI
+	besselKindK
+)
+
+var besselNames = [...]string{
+	besselKindJ: "BesselJ",
+	besselKindY: "BesselY",
+	besselKindI: "BesselI",
+	besselKindK: "BesselK",
+}
+
+// bessel sets o to the Bessel function of the given kind, handling special
+// cases and symmetries before computing with besselPos.
+func bessel(o, nu, x *big.Float, kind besselKind) *big.Float {
+	if o.Prec() == 0 {
+		if nu.Prec() >= x.Prec() {
+			o.SetPrec(nu.Prec())
+		} else {
+			o.SetPrec(x.Prec())
+		}
+	}
+	name := besselNames[kind]
+	if nu.IsInf() {
+		panic(ErrNaN{msg: name + ": infinite order"})
+	}
+	integer := nu.IsInt()
+	second := kind == besselKindY || kind == besselKindK
+
+	// Negative arguments are allowed only for J and I of integer order, for
+	// which J_n(-x) = (-1)**n J_n(x) and likewise for I.
+	neg := false
+	if x.Signbit() && x.Sign() != 0 {
+		if second || !integer {
+			panic(ErrNaN{msg: name + ": negative argument"})
+		}
+		neg = !besselEven(nu)
+		x = new(big.Float).Neg(x)
+	}
+	// For integer n, J₋ₙ = (-1)**n Jₙ and likewise for Y, while I₋ₙ = Iₙ;
+	// K₋ᵥ = Kᵥ for all ν.
+	if nu.Signbit() && (integer || kind == besselKindK) {
+		if integer && (kind == besselKindJ || kind == besselKindY) && !besselEven(nu) {
+			neg = !neg
+		}
+		nu = new(big.Float).Neg(nu)
+	}
+
+	switch {
+	case x.IsInf():
+		if kind == besselKindI {
+			return o.SetInf(neg)
+		}
+		return o.SetInt64(0)
+	case x.Sign() == 0:
+		return besselZero(o, nu, kind, neg)
+	}
+
+	prec := o.Prec()
+	// The result can be much smaller than the quantities it is computed from,
+	// near zeros of J and Y and wherever a series cancels. As in Polygamma,
+	// retry with enough extra precision to cover the loss.
+	r := ziv(prec, func(wp uint) (*big.Float, int) {
+		return besselPos(new(big.Float).SetPrec(wp), nu, x, kind)
+	})
+	if neg {
+		r.Neg(r)
+	}
+	return o.Set(r)
+}
+
+// besselEven returns whether the integer n is even.
+func besselEven(n *big.Float) bool {
+	return quicksh(new(big.Float), n, -1).IsInt()
+}
+
+// besselZero sets o to the Bessel function of the given kind at x = 0, for
+// ν ≥ 0 if ν is an integer and for all ν ≥ 0 for K, negated if neg is true.
+func besselZero(o, nu *big.Float, kind besselKind, neg bool) *big.Float {
+	switch kind {
+	case besselKindK:
+		return o.SetInf(false)
+	case besselKindY:
+		if !nu.Signbit() {
+			return o.SetInf(!neg)
+		}
+		// For ν > 0, Y₋ᵥ = sin(νπ) J_ν + cos(νπ) Y_ν, where J_ν(0) = 0 and
+		// Y_ν(0) = -Inf.
+		c := cosPi(new(big.Float).SetPrec(16), nu)
+		if c.Sign() == 0 {
+			return o.SetInt64(0)
+		}
+		return o.SetInf(c.Sign() > 0)
+	}
+	switch {
+	case nu.Sign() == 0:
+		o.SetInt64(1)
+	case !nu.Signbit() || nu.IsInt():
+		return o.SetInt64(0)
+	default:
+		// J_ν(x) ~ (x/2)**ν / Γ(ν+1) as x → 0.
+		t := new(big.Float).SetPrec(reflectPrec(nu, 16)).Add(nu, &gonep)
+		_, sign := LogGamma(new(big.Float).SetPrec(16), t)
+		o.SetInf(sign < 0)
+	}
+	if neg {
+		o.Neg(o)
+	}
+	return o
+}
+
+// besselPos sets o to the Bessel function of the given kind for finite x > 0
+// and returns o along with the binary exponent of the largest quantity in the
+// computation. ν must be nonnegative if it is an integer or if kind is K. The
+// absolute error in o is about 2**(mag - o.Prec()).
+func besselPos(o, nu, x *big.Float, kind besselKind) (*big.Float, int) {
+	wp := o.Prec()
+	if r, mag, ok := besselAsymp(new(big.Float).SetPrec(wp), nu, x, kind); ok {
+		return o.Set(r), mag
+	}
+	switch kind {
+	case besselKindJ:
+		return besselSeries(o, nu, x, false)
+	case besselKindI:
+		return besselSeries(o, nu, x, true)
+	}
+	if nu.IsInt() {
+		n, acc := nu.Int64()
+		if acc != big.Exact {
+			panic("bigfloat: " + besselNames[kind] + ": integer order is too large")
+		}
+		return besselSecondInt(o, n, x, kind == besselKindK)
+	}
+
+	// Y_ν = (J_ν cos(νπ) - J₋ᵥ) / sin(νπ)
+	// K_ν = π/2 (I₋ᵥ - I_ν) / sin(νπ)
+	modified := kind == besselKindK
+	a, magA := besselSeries(new(big.Float).SetPrec(wp), nu, x, modified)
+	b, magB := besselSeries(new(big.Float).SetPrec(wp), new(big.Float).Neg(nu), x, modified)
+	if !modified {
+		a.Mul(a, cosPi(new(big.Float).SetPrec(wp), nu))
+	}
+	mag := magA
+	if magB > mag {
+		mag = magB
+	}
+	s := sinPi(new(big.Float).SetPrec(wp), nu)
+	if modified {
+		o.Sub(b, a)
+		o.Mul(o, cachedPi(wp))
+		quicksh(o, o, -1)
+		mag++
+	} else {
+		o.Sub(a, b)
+	}
+	mag -= s.MantExp(nil)
+	return o.Quo(o, s), mag
+}
+
+// besselLead returns (x/2)**ν / Γ(ν+1) to precision wp for x > 0, where ν+1
+// is not a nonpositive integer.
+func besselLead(nu, x *big.Float, wp uint) *big.Float {
+	h := quicksh(new(big.Float), x, -1)
+	if n, acc := nu.Int64(); acc == big.Exact && n >= 0 && n <= gammaExactMax {
+		t := powInt(h.SetPrec(wp+32), int(n))
+		f := new(big.Float).SetPrec(wp + 32).SetInt(new(big.Int).MulRange(1, n))
+		return t.Quo(t, f).SetPrec(wp)
+	}
+	// exp(ν log(x/2) - log |Γ(ν+1)|). The absolute error in the exponent
+	// grows with the magnitudes of ν and log(x/2).
+	p := wp + 16
+	if e := nu.MantExp(nil); e > 0 {
+		p += uint(e) + uint(bits.Len(uint(e)))
+	}
+	if e := x.MantExp(nil); e != 0 {
+		if e < 0 {
+			e = -e
+		}
+		p += uint(bits.Len(uint(e)))
+	}
+	t := Log(new(big.Float).SetPrec(p), h.SetPrec(p))
+	t.Mul(t, nu)
+	g := new(big.Float).SetPrec(reflectPrec(nu, p)).Add(nu, &gonep)
+	g, sign := LogGamma(new(big.Float).SetPrec(p), g)
+	t.Sub(t, g)
+	Exp(t, t)
+	if sign < 0 {
+		t.Neg(t)
+	}
+	return t.SetPrec(wp)
+}
+
+// besselSeries sets o to J_ν(x), or I_ν(x) if modified is true, for x > 0
+// using the power series
+//
+//	(x/2)**ν Σ (∓x²/4)**k / (k! Γ(ν+k+1)), k ≥ 0,
+//
+// and returns o along with the binary exponent of the largest term. ν must
+// not be a negative integer.
+func besselSeries(o, nu, x *big.Float, modified bool) (*big.Float, int) {
+	wp := o.Prec() + 16
+	t := besselLead(nu, x, wp)
+	q := new(big.Float).SetPrec(wp).Mul(x, x)
+	quicksh(q, q, -2)
+	if !modified {
+		q.Neg(q)
+	}
+	qa := new(big.Float).Abs(q)
+	s := new(big.Float).SetPrec(wp).Set(t)
+	mag := t.MantExp(nil)
+	v := new(big.Float).SetPrec(wp)
+	d := new(big.Float).SetPrec(wp)
+	for k := int64(1); ; k++ {
+		// t *= q / (k (ν+k))
+		v.SetInt64(k)
+		d.Add(nu, v)
+		d.Mul(d, v)
+		t.Mul(t, q)
+		t.Quo(t, d)
+		s.Add(s, t)
+		if e := t.MantExp(nil); e > mag {
+			mag = e
+		}
+		// Once |q| < (k+1)(ν+k+1), the terms decrease.
+		if t.Sign() == 0 || t.MantExp(nil) < mag-int(wp) && d.Add(d, v.SetInt64(2*k+1)).Add(d, nu).Abs(d).Cmp(qa) > 0 {
+			break
+		}
+	}
+	return o.Set(s), mag
+}
+
+// besselSecondInt sets o to Y_n(x), or K_n(x) if modified is true, for
+// integer n ≥ 0 and x > 0, using the series
+//
+//	Y_n(x) = (2/π) J_n(x) log(x/2) - (1/π) Σ (n-k-1)!/k! (x/2)**(2k-n) - (1/π) G,
+//	K_n(x) = ½ Σ (n-k-1)!/k! (-x²/4)**k (x/2)**-n + (-1)**n (½ G - log(x/2) I_n(x)),
+//
+// where the finite sums are over 0 ≤ k < n and
+//
+//	G = (x/2)**n Σ (ψ(k+1) + ψ(n+k+1)) (∓x²/4)**k / (k! (n+k)!), k ≥ 0,
+//
+// and returns o along with the binary exponent of the largest quantity in the
+// computation.
+func besselSecondInt(o *big.Float, n int64, x *big.Float, modified bool) (*big.Float, int) {
+	wp := o.Prec() + 16
+	wp += uint(bits.Len64(uint64(n)))
+	h := quicksh(new(big.Float), x, -1).SetPrec(wp)
+	q := new(big.Float).SetPrec(wp).Mul(h, h)
+	h2 := new(big.Float).Set(q)
+	if !modified {
+		q.Neg(q)
+	}
+	v := new(big.Float).SetPrec(wp)
+	mag := math.MinInt32
+
+	// Finite sum. Its first term is (n-1)! (x/2)**-n.
+	f := new(big.Float).SetPrec(wp)
+	if n > 0 {
+		t := powInt(new(big.Float).SetPrec(wp).Set(h), int(n))
+		t.Quo(new(big.Float).SetPrec(wp).SetInt(new(big.Int).MulRange(1, n-1)), t)
+		mag = t.MantExp(nil)
+		f.Set(t)
+		for k := int64(1); k < n; k++ {
+			// t *= ∓(x/2)² / (k (n-k)), with the sign opposite to q's
+			t.Mul(t, q)
+			t.Neg(t)
+			t.Quo(t, v.SetInt64(k*(n-k)))
+			f.Add(f, t)
+			if e := t.MantExp(nil); e > mag {
+				mag = e
+			}
+		}
+	}
+
+	// The series for J_n or I_n and G together. ψ(k+1) = H_k - γ, where H_k
+	// is the kth harmonic number.
+	gamma := Digamma(new(big.Float).SetPrec(wp), &gonep)
+	p := new(big.Float).SetPrec(wp)
+	for k := int64(1); k <= n; k++ {
+		p.Add(p, v.Quo(&gonep, v.SetInt64(k)))
+	}
+	// p = ψ(1) + ψ(n+1)
+	quicksh(gamma, gamma, 1)
+	p.Add(p, gamma)
+	u := besselLead(v.SetInt64(n), x, wp)
+	j := new(big.Float).SetPrec(wp).Set(u)
+	g := new(big.Float).SetPrec(wp).Mul(u, p)
+	d := new(big.Float).SetPrec(wp)
+	for k := int64(1); ; k++ {
+		u.Mul(u, q)
+		u.Quo(u, v.SetInt64(k*(n+k)))
+		p.Add(p, d.Quo(&gonep, v.SetInt64(k)))
+		p.Add(p, d.Quo(&gonep, v.SetInt64(n+k)))
+		j.Add(j, u)
+		d.Mul(u, p)
+		g.Add(g, d)
+		e := d.MantExp(nil)
+		if e > mag {
+			mag = e
+		}
+		if u.Sign() == 0 || e < mag-int(wp) && u.MantExp(nil) < mag-int(wp) && v.SetInt64((k+1)*(n+k+1)).Cmp(h2) > 0 {
+			break
+		}
+	}
+	if e := g.MantExp(nil); e > mag {
+		mag = e
+	}
+	lh := Log(new(big.Float).SetPrec(wp), h)
+	j.Mul(j, lh)
+	if e := j.MantExp(nil); e > mag {
+		mag = e
+	}
+
+	if modified {
+		// K_n = ½ F + (-1)**n (½ G - log(x/2) I_n)
+		quicksh(g, g, -1)
+		g.Sub(g, j)
+		if n%2 != 0 {
+			g.Neg(g)
+		}
+		quicksh(f, f, -1)
+		return o.Add(f, g), mag
+	}
+	// Y_n = (2 log(x/2) J_n - F - G) / π
+	quicksh(j, j, 1)
+	j.Sub(j, f)
+	j.Sub(j, g)
+	pi := cachedPi(wp)
+	return o.Quo(j, pi), mag - 1
+}
+
+// besselAsymp computes the Bessel function of the given kind for x > 0 using
+// Hankel's asymptotic expansion if x is large enough for it to converge to
+// o's precision, and returns o, the binary exponent of the largest quantity
+// in the computation, and true. If x is too small, it returns false. With
+// μ = 4ν² and
+//
+//	a_k = (μ - 1)(μ - 9)...(μ - (2k-1)²) / (k! (8x)**k),
+//
+// the expansions are
+//
+//	J_ν(x) = √(2/(πx)) (P cos χ - Q sin χ),
+//	Y_ν(x) = √(2/(πx)) (P sin χ + Q cos χ),
+//	I_ν(x) = exp(x) / √(2πx) Σ (-1)**k a_k,
+//	K_ν(x) = √(π/(2x)) exp(-x) Σ a_k,
+//
+// where χ = x - (ν/2 + 1/4)π, P = a_0 - a_2 + a_4 - ..., and
+// Q = a_1 - a_3 + a_5 - ....
+func besselAsymp(o, nu, x *big.Float, kind besselKind) (*big.Float, int, bool) {
+	wp := o.Prec() + 16
+	n, ok := besselAsympTerms(nu, x, wp)
+	if !ok {
+		return nil, 0, false
+	}
+	// I_ν also has a contribution of relative size exp(-2x) which the
+	// expansion omits.
+	if kind == besselKindI && x.MantExp(nil) <= bits.Len(wp) {
+		if xf, _ := x.Float64(); 2*xf*math.Log2E < float64(wp) {
+			return nil, 0, false
+		}
+	}
+
+	mu := new(big.Float).SetPrec(wp).Mul(nu, nu)
+	quicksh(mu, mu, 2)
+	x8 := quicksh(new(big.Float), x, 3).SetPrec(wp)
+	t := new(big.Float).SetPrec(wp).SetInt64(1)
+	sums := [2]*big.Float{
+		new(big.Float).SetPrec(wp).SetInt64(1),
+		new(big.Float).SetPrec(wp),
+	}
+	mag := 0
+	v := new(big.Float).SetPrec(wp)
+	for k := int64(1); k <= n && t.Sign() != 0; k++ {
+		// t *= (μ - (2k-1)²) / (8kx)
+		v.SetInt64((2*k - 1) * (2*k - 1))
+		v.Sub(mu, v)
+		t.Mul(t, v)
+		t.Quo(t, v.SetInt64(k))
+		t.Quo(t, x8)
+		if e := t.MantExp(nil); t.Sign() != 0 && e > mag {
+			mag = e
+		}
+		switch kind {
+		case besselKindJ, besselKindY:
+			// P gets the even terms and Q the odd, with alternating signs.
+			if k%4 < 2 {
+				sums[k%2].Add(sums[k%2], t)
+			} else {
+				sums[k%2].Sub(sums[k%2], t)
+			}
+		case besselKindI:
+			if k%2 == 0 {
+				sums[0].Add(sums[0], t)
+			} else {
+				sums[0].Sub(sums[0], t)
+			}
+		case besselKindK:
+			sums[0].Add(sums[0], t)
+		}
+	}
+
+	switch kind {
+	case besselKindJ, besselKindY:
+		// cos χ = cos x cos απ + sin x sin απ
+		// sin χ = sin x cos απ - cos x sin απ
+		// with α = ν/2 + 1/4.
+		a := quicksh(new(big.Float), nu, -1).SetPrec(wp)
+		a.Add(a, big.NewFloat(0.25))
+		ca := cosPi(new(big.Float).SetPrec(wp), a)
+		sa := sinPi(new(big.Float).SetPrec(wp), a)
+		sx, cx := sinCos(x, wp)
+		cc := new(big.Float).SetPrec(wp).Mul(cx, ca)
+		cc.Add(cc, v.Mul(sx, sa))
+		sc := new(big.Float).SetPrec(wp).Mul(sx, ca)
+		sc.Sub(sc, v.Mul(cx, sa))
+		if kind == besselKindJ {
+			o.Mul(sums[0], cc)
+			o.Sub(o, v.Mul(sums[1], sc))
+		} else {
+			o.Mul(sums[0], sc)
+			o.Add(o, v.Mul(sums[1], cc))
+		}
+		// √(2/(πx))
+		v.Mul(cachedPi(wp), x)
+		v.Quo(&gtwop, v)
+		v.Sqrt(v)
+		mag += v.MantExp(nil) + 1
+		return o.Mul(o, v), mag, true
+	case besselKindI:
+		// exp(x) / √(2πx)
+		v.Mul(cachedPi(wp), x)
+		quicksh(v, v, 1)
+		v.Sqrt(v)
+		o.Quo(sums[0], v)
+	case besselKindK:
+		// √(π/(2x)) exp(-x)
+		v.Quo(cachedPi(wp), x)
+		quicksh(v, v, -1)
+		v.Sqrt(v)
+		o.Mul(sums[0], v)
+	}
+	// The absolute error in exp(±x) grows with x.
+	p := wp
+	if ex := x.MantExp(nil); ex > 0 {
+		p += uint(ex)
+	}
+	e := new(big.Float).SetPrec(p).Set(x)
+	if kind == besselKindK {
+		e.Neg(e)
+	}
+	Exp(e, e)
+	o.Mul(o, e)
+	return o, mag + o.MantExp(nil) + 1, true
+}
+
+// besselAsympTerms returns the number of terms of the asymptotic expansion in
+// besselAsymp needed for wp bits, and whether the expansion reaches that
+// accuracy at all.
+func besselAsympTerms(nu, x *big.Float, wp uint) (int64, bool) {
+	// Estimate the terms in float64 using logarithms, which works for any x.
+	var mant big.Float
+	lx := float64(x.MantExp(&mant))
+	m, _ := mant.Float64()
+	lx += math.Log2(m)
+	nf, _ := nu.Float64()
+	mu := 4 * nf * nf
+	if math.IsInf(mu, 0) {
+		return 0, false
+	}
+	lt := 0.0
+	lim := 4*int64(wp) + 64
+	for k := int64(1); ; k++ {
+		d := mu - float64((2*k-1)*(2*k-1))
+		if d == 0 {
+			// ν is a half-integer, and the expansion terminates.
+			return k, true
+		}
+		step := math.Log2(math.Abs(d)) - math.Log2(float64(8*k)) - lx
+		lt += step
+		if lt < -float64(wp)-2 {
+			return k, true
+		}
+		if step >= 0 && d < 0 || k > lim {
+			// The terms have started to diverge.
+			return 0, false
+		}
+	}
+}
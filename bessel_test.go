@@ -0,0 +1,302 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+type besselFunc func(o, nu, x *big.Float) *big.Float
+
+func TestBesselFloat64(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 5, -3} {
+		nu := big.NewFloat(float64(n))
+		for _, x := range []float64{0.001, 0.5, 1, 3, 10, 45, 100, 1000, -2} {
+			got, _ := bigfloat.BesselJ(new(big.Float).SetPrec(53), nu, big.NewFloat(x)).Float64()
+			if want := math.Jn(n, x); math.Abs(got-want) > 1e-14*math.Abs(want) {
+				t.Errorf("BesselJ(%d, %g) = %g, want %g", n, x, got, want)
+			}
+			if x < 0 {
+				continue
+			}
+			got, _ = bigfloat.BesselY(new(big.Float).SetPrec(53), nu, big.NewFloat(x)).Float64()
+			if want := math.Yn(n, x); math.Abs(got-want) > 1e-14*math.Abs(want) {
+				t.Errorf("BesselY(%d, %g) = %g, want %g", n, x, got, want)
+			}
+		}
+	}
+	for _, test := range []struct {
+		name string
+		f    besselFunc
+		nu   float64
+		x    float64
+		want float64
+	}{
+		{"BesselI", bigfloat.BesselI, 0, 1, 1.2660658777520084},
+		{"BesselI", bigfloat.BesselI, 1, 1, 0.565159103992485},
+		{"BesselK", bigfloat.BesselK, 0, 1, 0.42102443824070834},
+		{"BesselK", bigfloat.BesselK, 1, 1, 0.6019072301972346},
+		{"BesselK", bigfloat.BesselK, 0, 10, 1.778006231616765e-05},
+	} {
+		got, _ := test.f(new(big.Float).SetPrec(53), big.NewFloat(test.nu), big.NewFloat(test.x)).Float64()
+		if math.Abs(got-test.want) > 1e-15*math.Abs(test.want) {
+			t.Errorf("%s(%g, %g) = %g, want %g", test.name, test.nu, test.x, got, test.want)
+		}
+	}
+}
+
+func TestBesselHalfInteger(t *testing.T) {
+	const prec = 200
+	wp := uint(prec + 64)
+	half, mhalf := big.NewFloat(0.5), big.NewFloat(-0.5)
+	for _, xf := range []float64{0.01, 1, 7, 50, 300} {
+		x := big.NewFloat(xf)
+		pi := bigfloat.Pi(new(big.Float).SetPrec(wp))
+		// J_½(x)² + Y_½(x)² = 2/(πx)
+		j := bigfloat.BesselJ(new(big.Float).SetPrec(prec), half, x)
+		y := bigfloat.BesselY(new(big.Float).SetPrec(prec), half, x)
+		got := new(big.Float).SetPrec(wp).Mul(j, j)
+		got.Add(got, new(big.Float).SetPrec(wp).Mul(y, y))
+		want := new(big.Float).SetPrec(wp).Mul(pi, x)
+		want.Quo(big.NewFloat(2), want)
+		if !closeTo(got, want, prec-4) {
+			t.Errorf("J_½(%g)² + Y_½(%g)² = %g, want %g", xf, xf, got, want)
+		}
+		// J₋½(x) = -Y_½(x) and Y₋½(x) = J_½(x)
+		if got := bigfloat.BesselJ(new(big.Float).SetPrec(prec), mhalf, x); !closeTo(got, new(big.Float).Neg(y), prec-2) {
+			t.Errorf("BesselJ(-½, %g) = %g, want %g", xf, got, new(big.Float).Neg(y))
+		}
+		if got := bigfloat.BesselY(new(big.Float).SetPrec(prec), mhalf, x); !closeTo(got, j, prec-2) {
+			t.Errorf("BesselY(-½, %g) = %g, want %g", xf, got, j)
+		}
+
+		// K_½(x) = √(π/(2x)) exp(-x)
+		r := new(big.Float).SetPrec(wp).Quo(pi, x)
+		r.Quo(r, big.NewFloat(2))
+		r.Sqrt(r)
+		e := bigfloat.Exp(new(big.Float), new(big.Float).SetPrec(wp).Neg(x))
+		want = new(big.Float).Mul(r, e)
+		for _, nu := range []*big.Float{half, mhalf} {
+			if got := bigfloat.BesselK(new(big.Float).SetPrec(prec), nu, x); !closeTo(got, want, prec-2) {
+				t.Errorf("BesselK(%g, %g) = %g, want %g", nu, xf, got, want)
+			}
+		}
+		// I_½(x) = (exp(x) - exp(-x)) / √(2πx), I₋½(x) = (exp(x) + exp(-x)) / √(2πx)
+		r.Mul(pi, x)
+		r.Mul(r, big.NewFloat(2))
+		r.Sqrt(r)
+		ep := bigfloat.Exp(new(big.Float), new(big.Float).SetPrec(wp).Set(x))
+		want = new(big.Float).Sub(ep, e)
+		want.Quo(want, r)
+		if got := bigfloat.BesselI(new(big.Float).SetPrec(prec), half, x); !closeTo(got, want, prec-2) {
+			t.Errorf("BesselI(½, %g) = %g, want %g", xf, got, want)
+		}
+		want = new(big.Float).Add(ep, e)
+		want.Quo(want, r)
+		if got := bigfloat.BesselI(new(big.Float).SetPrec(prec), mhalf, x); !closeTo(got, want, prec-2) {
+			t.Errorf("BesselI(-½, %g) = %g, want %g", xf, got, want)
+		}
+	}
+}
+
+func TestBesselWronskian(t *testing.T) {
+	// J_ν Y_ν+1 - J_ν+1 Y_ν = -2/(πx)
+	// I_ν K_ν+1 + I_ν+1 K_ν = 1/x
+	const prec = 200
+	wp := uint(prec + 64)
+	pi := bigfloat.Pi(new(big.Float).SetPrec(wp))
+	for _, nuf := range []float64{0, 1.25, -2.75, 7, 30.5, 100} {
+		nu, nu1 := big.NewFloat(nuf), big.NewFloat(nuf+1)
+		for _, xf := range []float64{0.001, 1, 10, 60, 250, 1e4} {
+			x := big.NewFloat(xf)
+			j := bigfloat.BesselJ(new(big.Float).SetPrec(prec), nu, x)
+			j1 := bigfloat.BesselJ(new(big.Float).SetPrec(prec), nu1, x)
+			y := bigfloat.BesselY(new(big.Float).SetPrec(prec), nu, x)
+			y1 := bigfloat.BesselY(new(big.Float).SetPrec(prec), nu1, x)
+			got := new(big.Float).SetPrec(wp).Mul(j, y1)
+			d := new(big.Float).SetPrec(wp).Mul(j1, y)
+			// For negative ν and small x, the products can be much larger
+			// than their difference.
+			want := new(big.Float).SetPrec(wp).Mul(pi, x)
+			want.Quo(big.NewFloat(-2), want)
+			lost := wronskianLoss(got, d, want)
+			got.Sub(got, d)
+			if !closeTo(got, want, prec-8-lost) {
+				t.Errorf("J/Y Wronskian for ν = %g, x = %g: got %g, want %g", nuf, xf, got, want)
+			}
+
+			i := bigfloat.BesselI(new(big.Float).SetPrec(prec), nu, x)
+			i1 := bigfloat.BesselI(new(big.Float).SetPrec(prec), nu1, x)
+			k := bigfloat.BesselK(new(big.Float).SetPrec(prec), nu, x)
+			k1 := bigfloat.BesselK(new(big.Float).SetPrec(prec), nu1, x)
+			got.Mul(i, k1)
+			d.Mul(i1, k)
+			want.Quo(big.NewFloat(1), x)
+			lost = wronskianLoss(got, d, want)
+			got.Add(got, d)
+			if !closeTo(got, want, prec-8-lost) {
+				t.Errorf("I/K Wronskian for ν = %g, x = %g: got %g, want %g", nuf, xf, got, want)
+			}
+		}
+	}
+}
+
+// wronskianLoss returns the number of bits by which the larger of a and b
+// exceeds their sum or difference want.
+func wronskianLoss(a, b, want *big.Float) uint {
+	e := a.MantExp(nil)
+	if f := b.MantExp(nil); f > e {
+		e = f
+	}
+	e -= want.MantExp(nil)
+	if e < 0 {
+		return 0
+	}
+	return uint(e)
+}
+
+func TestBesselPrecision(t *testing.T) {
+	nearTwo := new(big.Float).SetPrec(100).SetInt64(1)
+	nearTwo.SetMantExp(nearTwo, -40)
+	nearTwo.Add(nearTwo, big.NewFloat(2))
+	for _, test := range []struct {
+		name  string
+		f     besselFunc
+		nu, x *big.Float
+	}{
+		// near the first zeros of J₀ and Y₀
+		{"BesselJ", bigfloat.BesselJ, big.NewFloat(0), big.NewFloat(2.404825557695773)},
+		{"BesselY", bigfloat.BesselY, big.NewFloat(0), big.NewFloat(0.8935769662791675)},
+		{"BesselY", bigfloat.BesselY, nearTwo, big.NewFloat(3)},
+		{"BesselK", bigfloat.BesselK, nearTwo, big.NewFloat(3)},
+		{"BesselK", bigfloat.BesselK, big.NewFloat(0), big.NewFloat(100)},
+		{"BesselJ", bigfloat.BesselJ, big.NewFloat(0.25), big.NewFloat(500)},
+		{"BesselI", bigfloat.BesselI, big.NewFloat(3), big.NewFloat(200)},
+		{"BesselJ", bigfloat.BesselJ, big.NewFloat(1000), big.NewFloat(900)},
+		{"BesselY", bigfloat.BesselY, big.NewFloat(3), big.NewFloat(1e-30)},
+		{"BesselJ", bigfloat.BesselJ, big.NewFloat(-3.5), big.NewFloat(1e-30)},
+	} {
+		want := test.f(new(big.Float).SetPrec(1000), test.nu, test.x)
+		for _, prec := range []uint{24, 53, 100, 300} {
+			got := test.f(new(big.Float).SetPrec(prec), test.nu, test.x)
+			if !closeTo(got, want, prec-2) {
+				t.Errorf("prec = %d: %s(%g, %g) =\ngot  %g;\nwant %g", prec, test.name, test.nu, test.x, got, want)
+			}
+		}
+	}
+}
+
+func TestBesselSpecialValues(t *testing.T) {
+	inf, ninf, nzero := math.Inf(1), math.Inf(-1), math.Copysign(0, -1)
+	for _, test := range []struct {
+		name string
+		f    besselFunc
+		nu   float64
+		x    float64
+		want float64
+	}{
+		{"BesselJ", bigfloat.BesselJ, 0, 0, 1},
+		{"BesselJ", bigfloat.BesselJ, 2.5, 0, 0},
+		{"BesselJ", bigfloat.BesselJ, -3, 0, 0},
+		{"BesselJ", bigfloat.BesselJ, -0.5, 0, inf},
+		{"BesselJ", bigfloat.BesselJ, -1.5, 0, ninf},
+		{"BesselJ", bigfloat.BesselJ, 1.5, inf, 0},
+		{"BesselJ", bigfloat.BesselJ, 3, ninf, 0},
+		{"BesselY", bigfloat.BesselY, 0, 0, ninf},
+		{"BesselY", bigfloat.BesselY, -3, 0, inf},
+		{"BesselY", bigfloat.BesselY, -0.5, 0, 0},
+		{"BesselY", bigfloat.BesselY, -0.25, 0, ninf},
+		{"BesselY", bigfloat.BesselY, 1, inf, 0},
+		{"BesselI", bigfloat.BesselI, 0, nzero, 1},
+		{"BesselI", bigfloat.BesselI, 1, 0, 0},
+		{"BesselI", bigfloat.BesselI, 2.5, inf, inf},
+		{"BesselI", bigfloat.BesselI, 3, ninf, ninf},
+		{"BesselI", bigfloat.BesselI, -4, ninf, inf},
+		{"BesselK", bigfloat.BesselK, 0, 0, inf},
+		{"BesselK", bigfloat.BesselK, -1.5, 0, inf},
+		{"BesselK", bigfloat.BesselK, 2, inf, 0},
+	} {
+		x64, acc := test.f(new(big.Float).SetPrec(53), big.NewFloat(test.nu), big.NewFloat(test.x)).Float64()
+		if x64 != test.want || math.Signbit(x64) != math.Signbit(test.want) || acc != big.Exact {
+			t.Errorf("%s(%g, %g) =\n got %g (%s);\nwant %g (Exact)", test.name, test.nu, test.x, x64, acc, test.want)
+		}
+	}
+	// Symmetries for integer order.
+	for _, test := range []struct {
+		name string
+		f    besselFunc
+		nu   float64
+		x    float64
+		sign float64
+	}{
+		{"BesselJ", bigfloat.BesselJ, 3, -2, -1},
+		{"BesselJ", bigfloat.BesselJ, -3, 2, -1},
+		{"BesselJ", bigfloat.BesselJ, -2, -2, 1},
+		{"BesselY", bigfloat.BesselY, -3, 2, -1},
+		{"BesselI", bigfloat.BesselI, -3, 2, 1},
+		{"BesselI", bigfloat.BesselI, 3, -2, -1},
+		{"BesselK", bigfloat.BesselK, -2.5, 2, 1},
+	} {
+		got := test.f(new(big.Float).SetPrec(53), big.NewFloat(test.nu), big.NewFloat(test.x))
+		want := test.f(new(big.Float).SetPrec(53), big.NewFloat(math.Abs(test.nu)), big.NewFloat(math.Abs(test.x)))
+		if test.sign < 0 {
+			want.Neg(want)
+		}
+		if got.Cmp(want) != 0 {
+			t.Errorf("%s(%g, %g) = %g, want %g", test.name, test.nu, test.x, got, want)
+		}
+	}
+	for _, test := range []struct {
+		name string
+		f    besselFunc
+		nu   float64
+		x    float64
+	}{
+		{"BesselJ", bigfloat.BesselJ, 0.5, -1},
+		{"BesselJ", bigfloat.BesselJ, inf, 1},
+		{"BesselY", bigfloat.BesselY, 1, -1},
+		{"BesselY", bigfloat.BesselY, 1, ninf},
+		{"BesselI", bigfloat.BesselI, -0.5, -1},
+		{"BesselK", bigfloat.BesselK, 0, -1},
+		{"BesselK", bigfloat.BesselK, ninf, 1},
+	} {
+		expectNaN(t, fmt.Sprintf("%s(%g, %g)", test.name, test.nu, test.x), func() {
+			test.f(new(big.Float), big.NewFloat(test.nu), big.NewFloat(test.x))
+		})
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkBesselJ(b *testing.B) {
+	for _, x := range []float64{1, 50} {
+		nu, z := big.NewFloat(0.75), big.NewFloat(x)
+		for _, prec := range []uint{1e2, 1e3} {
+			o := new(big.Float).SetPrec(prec)
+			b.Run(fmt.Sprintf("%v/%v", x, prec), func(b *testing.B) {
+				b.ReportAllocs()
+				for n := 0; n < b.N; n++ {
+					bigfloat.BesselJ(o, nu, z)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkBesselK(b *testing.B) {
+	for _, nuf := range []float64{1, 0.75} {
+		nu, z := big.NewFloat(nuf), big.NewFloat(2)
+		for _, prec := range []uint{1e2, 1e3} {
+			o := new(big.Float).SetPrec(prec)
+			b.Run(fmt.Sprintf("%v/%v", nuf, prec), func(b *testing.B) {
+				b.ReportAllocs()
+				for n := 0; n < b.N; n++ {
+					bigfloat.BesselK(o, nu, z)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestBetaIncIdentities(t *testing.T) {
+	for _, prec := range []uint{53, 100, 300, 1000} {
+		for _, xf := range []float64{1e-6, 0.1, 0.5, 0.7, 0.999} {
+			x := big.NewFloat(xf)
+			y := new(big.Float).SetPrec(prec+64).Sub(big.NewFloat(1), x)
+			for _, ab := range []float64{0.25, 1, 3.5, 40} {
+				c := big.NewFloat(ab)
+				// I_x(a, 1) = x**a
+				want := bigfloat.Pow(new(big.Float).SetPrec(prec+64), new(big.Float).SetPrec(prec+64).Set(x), c)
+				got := bigfloat.BetaInc(new(big.Float).SetPrec(prec), c, big.NewFloat(1), x)
+				if !closeTo(got, want, prec-4) {
+					t.Errorf("I_%g(%g, 1) at prec %d:\ngot  %g\nwant %g", xf, ab, prec, got, want)
+				}
+				// I_x(1, b) = 1 - (1-x)**b
+				want = bigfloat.Pow(new(big.Float).SetPrec(prec+64), y, c)
+				want.Sub(big.NewFloat(1), want)
+				got = bigfloat.BetaInc(new(big.Float).SetPrec(prec), big.NewFloat(1), c, x)
+				if !closeTo(got, want, prec-4) {
+					t.Errorf("I_%g(1, %g) at prec %d:\ngot  %g\nwant %g", xf, ab, prec, got, want)
+				}
+			}
+			// I_x(a, a) = 1 - I_(1-x)(a, a)
+			half := big.NewFloat(0.5)
+			got := bigfloat.BetaInc(new(big.Float).SetPrec(prec), half, half, x)
+			other := bigfloat.BetaInc(new(big.Float).SetPrec(prec+64), half, half, y)
+			other.Sub(big.NewFloat(1), other)
+			if !closeTo(got, other, prec-4) {
+				t.Errorf("I_%g(1/2, 1/2) at prec %d:\ngot  %g\nwant %g", xf, prec, got, other)
+			}
+		}
+	}
+}
+
+func TestBetaIncBinomial(t *testing.T) {
+	// For integer a and b, I_x(a, b) is the probability of at least a
+	// successes in a+b-1 Bernoulli trials with success probability x.
+	for _, prec := range []uint{53, 200} {
+		for _, n := range []int64{1, 4, 20} {
+			for a := int64(1); a <= n; a++ {
+				x := big.NewRat(3, 7)
+				y := big.NewRat(4, 7)
+				want := new(big.Rat)
+				for k := a; k <= n; k++ {
+					t := new(big.Rat).SetInt(new(big.Int).Binomial(n, k))
+					for i := int64(0); i < k; i++ {
+						t.Mul(t, x)
+					}
+					for i := k; i < n; i++ {
+						t.Mul(t, y)
+					}
+					want.Add(want, t)
+				}
+				xf := new(big.Float).SetPrec(prec + 64).SetRat(x)
+				got := bigfloat.BetaInc(new(big.Float).SetPrec(prec), big.NewFloat(float64(a)), big.NewFloat(float64(n-a+1)), xf)
+				w := new(big.Float).SetPrec(prec + 64).SetRat(want)
+				if !closeTo(got, w, prec-4) {
+					t.Errorf("I_3/7(%d, %d) at prec %d:\ngot  %g\nwant %g", a, n-a+1, prec, got, w)
+				}
+			}
+		}
+	}
+}
+
+func TestBetaIncPrecision(t *testing.T) {
+	for _, c := range [][3]string{{"0.5", "0.5", "0.1"}, {"100", "200", "0.3"}, {"1e-3", "5", "0.01"}, {"1000", "1000", "0.49"}, {"50", "50", "1e-3"}, {"2", "3", "0.999999"}} {
+		a, b, x := parse(c[0], 1200), parse(c[1], 1200), parse(c[2], 1200)
+		want := bigfloat.BetaInc(new(big.Float).SetPrec(1000), a, b, x)
+		for _, prec := range []uint{53, 300} {
+			got := bigfloat.BetaInc(new(big.Float).SetPrec(prec), a, b, x)
+			if !closeTo(got, want, prec-2) {
+				t.Errorf("I_%s(%s, %s) at prec %d:\ngot  %g\nwant %g", c[2], c[0], c[1], prec, got, want)
+			}
+		}
+	}
+}
+
+func TestBetaIncSpecialValues(t *testing.T) {
+	for _, test := range []struct {
+		a, b, x float64
+		want    float64
+	}{
+		{2, 3, 0, 0},
+		{2, 3, math.Copysign(0, -1), 0},
+		{2, 3, 1, 1},
+		{0.5, 0.5, 0.5, 0.5},
+		{3, 3, 0.5, 0.5},
+	} {
+		x64, acc := bigfloat.BetaInc(new(big.Float).SetPrec(53), big.NewFloat(test.a), big.NewFloat(test.b), big.NewFloat(test.x)).Float64()
+		if x64 != test.want || acc != big.Exact {
+			t.Errorf("BetaInc(%g, %g, %g) = %g (%s), want %g (Exact)", test.a, test.b, test.x, x64, acc, test.want)
+		}
+	}
+	for _, test := range []struct {
+		a, b, x float64
+	}{
+		{0, 1, 0.5},
+		{1, -1, 0.5},
+		{math.Inf(1), 1, 0.5},
+		{1, 1, -0.5},
+		{1, 1, 1.5},
+	} {
+		expectNaN(t, fmt.Sprintf("BetaInc(%g, %g, %g)", test.a, test.b, test.x), func() {
+			bigfloat.BetaInc(new(big.Float), big.NewFloat(test.a), big.NewFloat(test.b), big.NewFloat(test.x))
+		})
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkBetaInc(b *testing.B) {
+	p, q, x := big.NewFloat(2.5), big.NewFloat(4), big.NewFloat(0.3)
+	for _, prec := range []uint{1e2, 1e3} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.BetaInc(o, p, q, x)
+			}
+		})
+	}
+}
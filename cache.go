@@ -0,0 +1,272 @@
+package bigfloat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math/big"
+)
+
+// ErrCacheFormat is the error LoadCache returns, possibly wrapped, when its
+// input is not a valid constant cache file.
+var ErrCacheFormat = errors.New("bigfloat: invalid constant cache")
+
+// The cache file format is, with all integers big-endian:
+//
+//	magic    [8]byte   "bfconst\n"
+//	version  uint32
+//	count    uint32
+//	count entries, each:
+//		nameLen  uint8
+//		name     [nameLen]byte
+//		dataLen  uint32
+//		data     [dataLen]byte   (*big.Float).GobEncode output
+//		crc      uint32          CRC-32 (IEEE) of name and data
+//	crc      uint32    CRC-32 (IEEE) of everything preceding it
+//
+// Entries are identified by constant name rather than number so that adding
+// constants does not invalidate existing files.
+const (
+	cacheMagic   = "bfconst\n"
+	cacheVersion = 1
+)
+
+// SaveCache writes the cached value of every constant to w in a versioned
+// binary format that LoadCache can read. Constants which have not been
+// computed are omitted. SaveCache returns any error encountered writing to w.
+func SaveCache(w io.Writer) error {
+	var vals []*big.Float
+	var names []string
+	for c := Constant(0); c < numConstants; c++ {
+		v := c.load()
+		if v.Prec() == 0 {
+			continue
+		}
+		vals = append(vals, v)
+		names = append(names, c.String())
+	}
+
+	cw := crcWriter{w: w, h: crc32.NewIEEE()}
+	cw.write([]byte(cacheMagic))
+	cw.uint32(cacheVersion)
+	cw.uint32(uint32(len(vals)))
+	for i, v := range vals {
+		data, err := v.GobEncode()
+		if err != nil {
+			return err
+		}
+		name := []byte(names[i])
+		cw.write([]byte{uint8(len(name))})
+		cw.write(name)
+		cw.uint32(uint32(len(data)))
+		cw.write(data)
+		h := crc32.NewIEEE()
+		h.Write(name)
+		h.Write(data)
+		cw.uint32(h.Sum32())
+	}
+	cw.uint32(cw.h.Sum32())
+	return cw.err
+}
+
+// LoadCache reads constants written by SaveCache from r and adds them to the
+// cache. A loaded value replaces a cached one only if it is more precise, and
+// it is rounded to the constant's cache limit, if any. Entries for constants
+// this version of the package does not know are ignored.
+//
+// Besides verifying checksums, LoadCache compares the leading bits of each
+// value against a low-precision computation of the constant. The entire input
+// is validated before any value is used, so if LoadCache returns an error, the
+// cache is unchanged. Errors describing a malformed, corrupt, or truncated
+// input wrap ErrCacheFormat; other errors are those returned by r.
+func LoadCache(r io.Reader) error {
+	cr := crcReader{r: r, h: crc32.NewIEEE()}
+	magic := make([]byte, len(cacheMagic))
+	cr.read(magic)
+	if cr.err == nil && string(magic) != cacheMagic {
+		return fmt.Errorf("%w: bad magic number", ErrCacheFormat)
+	}
+	if v := cr.uint32(); cr.err == nil && v != cacheVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrCacheFormat, v)
+	}
+	count := cr.uint32()
+	if cr.err != nil {
+		return cr.err
+	}
+
+	loaded := make(map[Constant]*big.Float)
+	var name [255]byte
+	var data bytes.Buffer
+	for i := uint32(0); i < count; i++ {
+		var n [1]byte
+		cr.read(n[:])
+		cr.read(name[:n[0]])
+		size := cr.uint32()
+		if cr.err != nil {
+			return cr.err
+		}
+		// Read incrementally rather than allocating size bytes up front so
+		// that a corrupt length in a short file can't cause a huge
+		// allocation.
+		data.Reset()
+		k, err := io.CopyN(&data, r, int64(size))
+		cr.h.Write(data.Bytes()[:k])
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("%w: %v", ErrCacheFormat, io.ErrUnexpectedEOF)
+			}
+			return err
+		}
+		sum := cr.uint32()
+		if cr.err != nil {
+			return cr.err
+		}
+		h := crc32.NewIEEE()
+		h.Write(name[:n[0]])
+		h.Write(data.Bytes())
+		if h.Sum32() != sum {
+			return fmt.Errorf("%w: checksum mismatch in entry %d", ErrCacheFormat, i)
+		}
+
+		c := constantNamed(string(name[:n[0]]))
+		if c < 0 {
+			continue
+		}
+		v := new(big.Float)
+		if err := v.GobDecode(data.Bytes()); err != nil {
+			return fmt.Errorf("%w: entry %d: %v", ErrCacheFormat, i, err)
+		}
+		if v.Prec() == 0 || v.Sign() <= 0 || v.IsInf() {
+			return fmt.Errorf("%w: entry %d has invalid value", ErrCacheFormat, i)
+		}
+		loaded[c] = v
+	}
+	sum := cr.h.Sum32()
+	if v := cr.uint32(); cr.err != nil {
+		return cr.err
+	} else if v != sum {
+		return fmt.Errorf("%w: checksum mismatch", ErrCacheFormat)
+	}
+
+	// The checksums only protect against damage after SaveCache. Compare the
+	// leading bits of each value against a quick recomputation to catch a
+	// file written with a wrong value or a mislabeled entry.
+	for c := Constant(0); c < numConstants; c++ {
+		if v := loaded[c]; v != nil && !c.matches(v) {
+			return fmt.Errorf("%w: value for %v is incorrect", ErrCacheFormat, c)
+		}
+	}
+
+	for c, v := range loaded {
+		cache := &caches[c]
+		cache.mu.Lock()
+		c.store(v)
+		cache.mu.Unlock()
+	}
+	return nil
+}
+
+// checkPrec is the precision to which LoadCache verifies loaded values.
+const checkPrec = 128
+
+// matches reports whether the leading bits of v agree with a fresh
+// low-precision computation of c. It does not use or change any cache.
+func (c Constant) matches(v *big.Float) bool {
+	prec := uint(checkPrec)
+	if v.Prec() < prec {
+		prec = v.Prec()
+	}
+	want := c.approx(prec + 16)
+	// Either value may be off by an ulp or so at its own precision, so allow
+	// a few bits of slack.
+	d := new(big.Float).SetPrec(prec+16).Sub(v, want)
+	return d.Sign() == 0 || d.MantExp(nil) <= want.MantExp(nil)-int(prec)+4
+}
+
+// approx computes c to prec bits without using or changing any cache.
+func (c Constant) approx(prec uint) *big.Float {
+	a := new(big.Float).SetPrec(prec)
+	switch c {
+	case ConstLn2:
+		// log 2 = Σ 1/(k 2**k) for k ≥ 1. Log would use the cached π.
+		t := new(big.Float).SetPrec(prec + 16)
+		a.SetPrec(prec + 16)
+		for k := 1; k <= int(prec)+16; k++ {
+			t.SetMantExp(t.SetInt64(int64(k)), k)
+			a.Add(a, t.Quo(&gonep, t))
+		}
+		return a.SetPrec(prec)
+	case ConstLemniscate:
+		// ϖ = πG
+		return a.Mul(piCalc(new(big.Float).SetPrec(prec+16)), gaussCalc(new(big.Float).SetPrec(prec+16)))
+	default:
+		return c.calc(a)
+	}
+}
+
+// constantNamed returns the constant with the given name, or -1 if there is
+// none.
+func constantNamed(name string) Constant {
+	for c := Constant(0); c < numConstants; c++ {
+		if c.String() == name {
+			return c
+		}
+	}
+	return -1
+}
+
+// crcWriter writes to w while computing a checksum of everything written.
+// After an error, all writes do nothing.
+type crcWriter struct {
+	w   io.Writer
+	h   hash.Hash32
+	err error
+}
+
+func (cw *crcWriter) write(p []byte) {
+	if cw.err != nil {
+		return
+	}
+	cw.h.Write(p)
+	_, cw.err = cw.w.Write(p)
+}
+
+func (cw *crcWriter) uint32(x uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], x)
+	cw.write(b[:])
+}
+
+// crcReader reads from r while computing a checksum of everything read. After
+// an error, all reads do nothing. Reaching the end of r early is reported as
+// a format error.
+type crcReader struct {
+	r   io.Reader
+	h   hash.Hash32
+	err error
+}
+
+func (cr *crcReader) read(p []byte) {
+	if cr.err != nil {
+		return
+	}
+	_, err := io.ReadFull(cr.r, p)
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = fmt.Errorf("%w: %v", ErrCacheFormat, io.ErrUnexpectedEOF)
+		}
+		cr.err = err
+		return
+	}
+	cr.h.Write(p)
+}
+
+func (cr *crcReader) uint32() uint32 {
+	var b [4]byte
+	cr.read(b[:])
+	return binary.BigEndian.Uint32(b[:])
+}
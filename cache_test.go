@@ -0,0 +1,254 @@
+package bigfloat
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// saveCaches returns a function which restores all constant caches to their
+// current values and limits.
+func saveCaches() func() {
+	var old [numConstants]*big.Float
+	var limits [numConstants]uint
+	for c := range old {
+		old[c] = Constant(c).load()
+		limits[c] = Constant(c).CacheLimit()
+	}
+	return func() {
+		for c, v := range old {
+			Constant(c).SetCacheLimit(limits[c])
+			caches[c].Store(v)
+		}
+	}
+}
+
+// clearCaches sets all constant caches to zero-precision values.
+func clearCaches() {
+	for c := range caches {
+		caches[c].Store(new(big.Float))
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	defer saveCaches()()
+	ConstPi.cached(3000)
+	ConstLn2.cached(500)
+	var want [numConstants]*big.Float
+	for c := range want {
+		want[c] = Constant(c).load()
+	}
+
+	var b bytes.Buffer
+	if err := SaveCache(&b); err != nil {
+		t.Fatalf("SaveCache failed: %v", err)
+	}
+	clearCaches()
+	if err := LoadCache(&b); err != nil {
+		t.Fatalf("LoadCache failed: %v", err)
+	}
+	for c, v := range want {
+		got := Constant(c).load()
+		if got.Prec() != v.Prec() || got.Cmp(v) != 0 {
+			t.Errorf("%v: wrong value after load: want %g (prec %d), got %g (prec %d)", Constant(c), v, v.Prec(), got, got.Prec())
+		}
+	}
+}
+
+func TestCacheLoadLessPrecise(t *testing.T) {
+	defer saveCaches()()
+	clearCaches()
+	ConstE.cached(200)
+	var b bytes.Buffer
+	if err := SaveCache(&b); err != nil {
+		t.Fatalf("SaveCache failed: %v", err)
+	}
+	ConstE.cached(1000)
+	if err := LoadCache(&b); err != nil {
+		t.Fatalf("LoadCache failed: %v", err)
+	}
+	if p := ConstE.load().Prec(); p != 1000 {
+		t.Errorf("less precise value replaced cache: want prec 1000, got %d", p)
+	}
+}
+
+func TestCacheLoadCorrupt(t *testing.T) {
+	defer saveCaches()()
+	clearCaches()
+	ConstPi.cached(300)
+	ConstE.cached(100)
+	var b bytes.Buffer
+	if err := SaveCache(&b); err != nil {
+		t.Fatalf("SaveCache failed: %v", err)
+	}
+	file := b.Bytes()
+	clearCaches()
+
+	check := func(t *testing.T, p []byte) {
+		t.Helper()
+		err := LoadCache(bytes.NewReader(p))
+		if !errors.Is(err, ErrCacheFormat) {
+			t.Errorf("wrong error: want %v, got %v", ErrCacheFormat, err)
+		}
+		for c := range caches {
+			if p := Constant(c).load().Prec(); p != 0 {
+				t.Errorf("%v changed after failed load: got prec %d", Constant(c), p)
+			}
+		}
+	}
+	t.Run("truncated", func(t *testing.T) {
+		for i := range file {
+			check(t, file[:i])
+		}
+	})
+	t.Run("corrupt", func(t *testing.T) {
+		p := make([]byte, len(file))
+		for i := range file {
+			for bit := uint(0); bit < 8; bit++ {
+				copy(p, file)
+				p[i] ^= 1 << bit
+				check(t, p)
+			}
+		}
+	})
+}
+
+func TestCacheLimit(t *testing.T) {
+	defer saveCaches()()
+	clearCaches()
+	ConstE.SetCacheLimit(300)
+	want := ConstE.calc(new(big.Float).SetPrec(1000))
+	if got := ConstE.cached(1000); got.Prec() != 1000 || got.Cmp(want) != 0 {
+		t.Errorf("cached(1000) over limit: want %g, got %g (prec %d)", want, got, got.Prec())
+	}
+	if p := ConstE.load().Prec(); p != 300 {
+		t.Errorf("cached(1000) with limit 300 stored prec %d", p)
+	}
+	if got := ConstE.Value(new(big.Float).SetPrec(1000)); got.Cmp(want) != 0 {
+		t.Errorf("Value(1000) over limit: want %g, got %g", want, got)
+	}
+	if p := ConstE.load().Prec(); p != 300 {
+		t.Errorf("Value(1000) with limit 300 stored prec %d", p)
+	}
+	// Requests within the limit use the cache.
+	want.SetPrec(200)
+	if got := ConstE.cached(200); got != ConstE.load() || new(big.Float).SetPrec(200).Set(got).Cmp(want) != 0 {
+		t.Errorf("cached(200) within limit: want cached value %g, got %g", want, got)
+	}
+	// Lowering the limit shrinks the cache.
+	ConstE.SetCacheLimit(100)
+	if p := ConstE.load().Prec(); p != 100 {
+		t.Errorf("SetCacheLimit(100) left prec %d", p)
+	}
+	want.SetPrec(100)
+	if got := ConstE.load(); got.Cmp(want) != 0 {
+		t.Errorf("SetCacheLimit(100): want %g, got %g", want, got)
+	}
+	// Removing the limit lets the cache grow.
+	ConstE.SetCacheLimit(0)
+	ConstE.cached(500)
+	if p := ConstE.load().Prec(); p != 500 {
+		t.Errorf("cached(500) without limit stored prec %d", p)
+	}
+}
+
+func TestCacheShrinkDrop(t *testing.T) {
+	defer saveCaches()()
+	ConstLn2.cached(1000)
+	want := ConstLn2.calc(new(big.Float).SetPrec(400))
+	ConstLn2.ShrinkCache(400)
+	if got := ConstLn2.load(); got.Prec() != 400 || got.Cmp(want) != 0 {
+		t.Errorf("ShrinkCache(400): want %g, got %g (prec %d)", want, got, got.Prec())
+	}
+	ConstLn2.ShrinkCache(800)
+	if p := ConstLn2.load().Prec(); p != 400 {
+		t.Errorf("ShrinkCache(800) changed prec 400 to %d", p)
+	}
+	ConstLn2.DropCache()
+	if p := ConstLn2.load().Prec(); p != 0 {
+		t.Errorf("DropCache left prec %d", p)
+	}
+	if got := ConstLn2.cached(400); got.Cmp(want) != 0 {
+		t.Errorf("cached(400) after DropCache: want %g, got %g", want, got)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	defer saveCaches()()
+	clearCaches()
+	ConstLn2.SetCacheLimit(1000)
+	s0 := ConstLn2.CacheStats()
+	ConstLn2.cached(640)                         // miss
+	ConstLn2.cached(100)                         // hit
+	ConstLn2.Value(new(big.Float).SetPrec(640))  // hit
+	ConstLn2.Value(new(big.Float).SetPrec(2000)) // miss
+	s := ConstLn2.CacheStats()
+	if s.Hits-s0.Hits != 2 || s.Misses-s0.Misses != 2 {
+		t.Errorf("wrong counts: want 2 hits and 2 misses, got %d and %d", s.Hits-s0.Hits, s.Misses-s0.Misses)
+	}
+	if s.Prec != 1000 || s.Limit != 1000 {
+		t.Errorf("wrong precision or limit: want 1000 and 1000, got %d and %d", s.Prec, s.Limit)
+	}
+	if s.Bytes != 128 {
+		t.Errorf("wrong size: want 128 bytes, got %d", s.Bytes)
+	}
+	ConstLn2.DropCache()
+	if s := ConstLn2.CacheStats(); s.Prec != 0 || s.Bytes != 0 {
+		t.Errorf("stats after DropCache: want prec 0 and 0 bytes, got %d and %d", s.Prec, s.Bytes)
+	}
+}
+
+func TestCacheLoadLimit(t *testing.T) {
+	defer saveCaches()()
+	clearCaches()
+	ConstPi.cached(2000)
+	var b bytes.Buffer
+	if err := SaveCache(&b); err != nil {
+		t.Fatalf("SaveCache failed: %v", err)
+	}
+	clearCaches()
+	ConstPi.SetCacheLimit(700)
+	if err := LoadCache(&b); err != nil {
+		t.Fatalf("LoadCache failed: %v", err)
+	}
+	want := piCalc(new(big.Float).SetPrec(700))
+	if got := ConstPi.load(); got.Prec() != 700 || got.Cmp(want) != 0 {
+		t.Errorf("loaded value over limit: want %g, got %g (prec %d)", want, got, got.Prec())
+	}
+}
+
+func TestCacheLoadWrongValue(t *testing.T) {
+	defer saveCaches()()
+	clearCaches()
+	// A value with valid checksums but the wrong digits, as from a buggy
+	// writer or a mislabeled entry.
+	for _, bad := range []*big.Float{
+		piCalc(new(big.Float).SetPrec(500)),
+		new(big.Float).SetPrec(500).SetMantExp(eCalc(new(big.Float).SetPrec(500)), 1),
+		new(big.Float).SetPrec(500).Add(eCalc(new(big.Float).SetPrec(500)), new(big.Float).SetMantExp(big.NewFloat(1), -100)),
+	} {
+		caches[ConstE].Store(bad)
+		var b bytes.Buffer
+		if err := SaveCache(&b); err != nil {
+			t.Fatalf("SaveCache failed: %v", err)
+		}
+		clearCaches()
+		if err := LoadCache(&b); !errors.Is(err, ErrCacheFormat) {
+			t.Errorf("loading e = %g: want %v, got %v", bad, ErrCacheFormat, err)
+		}
+		for c := range caches {
+			if p := Constant(c).load().Prec(); p != 0 {
+				t.Errorf("%v changed after failed load: got prec %d", Constant(c), p)
+			}
+		}
+	}
+	// Correct values pass at any precision.
+	for c := Constant(0); c < numConstants; c++ {
+		for _, prec := range []uint{1, 24, 53, 1000} {
+			if v := c.calc(new(big.Float).SetPrec(prec)); !c.matches(v) {
+				t.Errorf("%v at prec %d does not match: %g", c, prec, v)
+			}
+		}
+	}
+}
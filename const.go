@@ -0,0 +1,301 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// A Constant is a mathematical constant which the package can compute to
+// arbitrary precision. The most precise value of each constant computed so far
+// is cached for reuse, so that repeated requests for the same or lower
+// precision are cheap.
+type Constant int
+
+const (
+	// ConstPi is π, the ratio of a circle's circumference to its diameter.
+	ConstPi Constant = iota
+	// ConstE is e, the base of the natural logarithm.
+	ConstE
+	// ConstLn2 is the natural logarithm of 2.
+	ConstLn2
+	// ConstGauss is Gauss's constant G = 1/AGM(1, √2).
+	ConstGauss
+	// ConstLemniscate is the lemniscate constant ϖ = πG, half the length of
+	// the lemniscate of Bernoulli x² + y² = √(x² - y²).
+	ConstLemniscate
+
+	numConstants
+)
+
+var constNames = [numConstants]string{
+	ConstPi:         "pi",
+	ConstE:          "e",
+	ConstLn2:        "ln2",
+	ConstGauss:      "gauss",
+	ConstLemniscate: "lemniscate",
+}
+
+// String returns the name of the constant.
+func (c Constant) String() string {
+	if c < 0 || c >= numConstants {
+		return "Constant(invalid)"
+	}
+	return constNames[c]
+}
+
+// constCache holds the most precise value of a constant computed so far.
+type constCache struct {
+	// The 64-bit fields come first so that they are aligned for atomic
+	// access on 32-bit platforms.
+	limit  uint64 // maximum cached precision, or 0 for no limit
+	hits   uint64
+	misses uint64
+
+	atomic.Value            // *big.Float
+	mu           sync.Mutex // writers only
+}
+
+// CacheStats describes the cache of a constant.
+type CacheStats struct {
+	// Hits and Misses are the number of requests for the constant which were
+	// and were not satisfied by the cached value.
+	Hits, Misses uint64
+	// Prec is the precision of the cached value, or 0 if there is none.
+	Prec uint
+	// Limit is the maximum precision the cache retains, or 0 if there is no
+	// limit.
+	Limit uint
+	// Bytes is the approximate size of the cached value's mantissa.
+	Bytes int
+}
+
+var caches [numConstants]constCache
+var enableConstCache bool = true
+
+func init() {
+	for c := range caches {
+		caches[c].Store(new(big.Float))
+	}
+	pi, _, err := new(big.Float).SetPrec(1024).Parse("3."+
+		"14159265358979323846264338327950288419716939937510"+
+		"58209749445923078164062862089986280348253421170679"+
+		"82148086513282306647093844609550582231725359408128"+
+		"48111745028410270193852110555964462294895493038196"+
+		"44288109756659334461284756482337867831652712019091"+
+		"45648566923460348610454326648213393607260249141273"+
+		"72458700660631558817488152092096282925409171536444", 10)
+	if err != nil {
+		panic(err)
+	}
+	caches[ConstPi].Store(pi)
+}
+
+// calc performs the actual computation to obtain a value for c to a's
+// precision.
+func (c Constant) calc(a *big.Float) *big.Float {
+	switch c {
+	case ConstPi:
+		return piCalc(a)
+	case ConstE:
+		return eCalc(a)
+	case ConstLn2:
+		return Log(a, &gtwop)
+	case ConstGauss:
+		return gaussCalc(a)
+	case ConstLemniscate:
+		// ϖ = πG
+		return a.Mul(cachedPi(a.Prec()+64), ConstGauss.cached(a.Prec()+64))
+	default:
+		panic("bigfloat: unknown constant " + c.String())
+	}
+}
+
+// load returns the current cached value of c. Use cached or Value instead;
+// this is just a convenience function for those safe wrappers.
+func (c Constant) load() *big.Float {
+	return caches[c].Load().(*big.Float)
+}
+
+// cached returns the cached value of c with at least prec precision. If the
+// cache is enabled and has a precision of at least prec, then this does not
+// allocate. The returned value must not be modified. It is safe to call this
+// concurrently.
+func (c Constant) cached(prec uint) *big.Float {
+	cache := &caches[c]
+	if !enableConstCache {
+		atomic.AddUint64(&cache.misses, 1)
+		return c.calc(new(big.Float).SetPrec(prec))
+	}
+	v := c.load()
+	if v.Prec() >= prec {
+		atomic.AddUint64(&cache.hits, 1)
+		return v
+	}
+
+	// The current cached value doesn't have enough precision. Calculate a new
+	// value.
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	// It's possible another goroutine obtained a more precise value while we
+	// were locking. Re-check the cached value.
+	v = c.load()
+	if v.Prec() >= prec {
+		atomic.AddUint64(&cache.hits, 1)
+		return v
+	}
+	atomic.AddUint64(&cache.misses, 1)
+	v = c.calc(new(big.Float).SetPrec(prec))
+	c.store(v)
+	return v
+}
+
+// Value sets a to c to a's precision (even if a's precision is zero) and
+// returns a. If the cached value of c is insufficiently precise, the newly
+// computed value replaces it.
+func (c Constant) Value(a *big.Float) *big.Float {
+	prec := a.Prec()
+	if prec == 0 {
+		// Zero-precision floats represent only ±0 or ±inf.
+		return a.Set(&gzero)
+	}
+	cache := &caches[c]
+	if enableConstCache {
+		v := c.load()
+		if prec <= v.Prec() {
+			atomic.AddUint64(&cache.hits, 1)
+			return a.Set(v)
+		}
+	}
+	atomic.AddUint64(&cache.misses, 1)
+	c.calc(a)
+	if enableConstCache {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		c.store(new(big.Float).Copy(a))
+	}
+	return a
+}
+
+// store replaces the cached value of c with v, rounded to the cache limit, if
+// the result is more precise than the current value. v must not be modified
+// afterward. The caller must hold the cache's lock.
+func (c Constant) store(v *big.Float) {
+	cache := &caches[c]
+	if limit := uint(atomic.LoadUint64(&cache.limit)); limit != 0 && v.Prec() > limit {
+		v = new(big.Float).SetPrec(limit).Set(v)
+	}
+	if c.load().Prec() < v.Prec() {
+		cache.Store(v)
+	}
+}
+
+// SetCacheLimit sets the maximum precision to which c is cached. Requests for
+// more precision than the limit are computed in full, but only the limit's
+// worth of the result is retained. If the cached value is more precise than
+// the new limit, it is shrunk to the limit. A limit of 0 means no limit,
+// which is the default.
+func (c Constant) SetCacheLimit(prec uint) {
+	cache := &caches[c]
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	atomic.StoreUint64(&cache.limit, uint64(prec))
+	if prec != 0 {
+		c.shrink(prec)
+	}
+}
+
+// CacheLimit returns the maximum precision to which c is cached, or 0 if
+// there is no limit.
+func (c Constant) CacheLimit() uint {
+	return uint(atomic.LoadUint64(&caches[c].limit))
+}
+
+// ShrinkCache reduces the precision of the cached value of c to at most prec,
+// releasing the memory of the more precise value. A prec of 0 is the same as
+// DropCache.
+func (c Constant) ShrinkCache(prec uint) {
+	cache := &caches[c]
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	c.shrink(prec)
+}
+
+// DropCache discards the cached value of c. The next request for c computes
+// it anew.
+func (c Constant) DropCache() {
+	c.ShrinkCache(0)
+}
+
+// shrink reduces the cached value of c to at most prec. The caller must hold
+// the cache's lock.
+func (c Constant) shrink(prec uint) {
+	v := c.load()
+	switch {
+	case v.Prec() <= prec:
+		// Already small enough.
+	case prec == 0:
+		caches[c].Store(new(big.Float))
+	default:
+		caches[c].Store(new(big.Float).SetPrec(prec).Set(v))
+	}
+}
+
+// CacheStats returns statistics about the cache of c. The counts include all
+// requests since the program started, including those for which the cache
+// was disabled or limited.
+func (c Constant) CacheStats() CacheStats {
+	cache := &caches[c]
+	prec := c.load().Prec()
+	return CacheStats{
+		Hits:   atomic.LoadUint64(&cache.hits),
+		Misses: atomic.LoadUint64(&cache.misses),
+		Prec:   prec,
+		Limit:  uint(atomic.LoadUint64(&cache.limit)),
+		Bytes:  int((uint64(prec) + bits.UintSize - 1) / bits.UintSize * (bits.UintSize / 8)),
+	}
+}
+
+// eCalc computes e to a's precision by summing the series Σ 1/k! using binary
+// splitting.
+func eCalc(a *big.Float) *big.Float {
+	prec := a.Prec()
+	// Find n such that n! > 2**(prec+64), so the truncation error of the
+	// series is below the guard digits.
+	n, lg := int64(1), 0.0
+	for lg <= float64(prec+64) {
+		n++
+		lg += math.Log2(float64(n))
+	}
+	p, q := eBS(0, n)
+	// e = 1 + P/Q
+	a.SetPrec(prec + 64).SetInt(p)
+	a.Quo(a, new(big.Float).SetPrec(prec+64).SetInt(q))
+	a.Add(a, &gonep)
+	return a.SetPrec(prec)
+}
+
+// gaussCalc computes Gauss's constant to a's precision as 1/AGM(1, √2).
+func gaussCalc(a *big.Float) *big.Float {
+	prec := a.Prec()
+	r := new(big.Float).SetPrec(prec + 64).Sqrt(&gtwop)
+	AGM(r, &gonep, r)
+	return a.Quo(&gonep, r)
+}
+
+// eBS computes P(a, b) and Q(a, b) such that P/Q = Σ a!/k! for k in (a, b].
+func eBS(a, b int64) (p, q *big.Int) {
+	if b-a == 1 {
+		return big.NewInt(1), big.NewInt(b)
+	}
+	m := (a + b) / 2
+	pl, ql := eBS(a, m)
+	pr, qr := eBS(m, b)
+	// P = Pl Qr + Pr, Q = Ql Qr
+	p = pl.Mul(pl, qr)
+	p.Add(p, pr)
+	q = ql.Mul(ql, qr)
+	return p, q
+}
@@ -0,0 +1,32 @@
+package bigfloat_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestConstantValue(t *testing.T) {
+	for _, test := range []struct {
+		c    bigfloat.Constant
+		want string
+	}{
+		{bigfloat.ConstPi, "3.1415926535897932384626433832795028841971693993751058209749445923078164062862089986280348253421170679821480865132823066470938446095505822317253594081284811174502841027019385211055596446229489549303819644288109756659334461284756482337867831652712019091456485669234603486104543266482133936072602491412737245870066063155881748815209209628292540917153644"},
+		{bigfloat.ConstE, "2.7182818284590452353602874713526624977572470936999595749669676277240766303535475945713821785251664274274663919320030599218174135966290435729003342952605956307381323286279434907632338298807531952510190115738341879307021540891499348841675092447614606680822648001684774118537423454424371075390777449920695517027618386062613313845830007520449338265602976"},
+		{bigfloat.ConstLn2, "0.69314718055994530941723212145817656807550013436025525412068000949339362196969471560586332699641868754200148102057068573368552023575813055703267075163507596193072757082837143519030703862389167347112335011536449795523912047517268157493206515552473413952588295045300709532636664265410423915781495204374043038550080194417064167151864471283996817178454696"},
+		{bigfloat.ConstGauss, "0.834626841674073186281429732799046808993993013490347002449827370103681992709526411869691160351275324129067850352412010086724789007634750392659060526742712560320685998973751521461574107190667714762311244616644051871383967845140283869200451381335890758553020498480052804469316435809368978956890689174120737291094066558175187025477401074678379716526356753331906545"},
+		{bigfloat.ConstLemniscate, "2.622057554292119810464839589891119413682754951431623162816821703800790587070414250230295532961429093446135752671783218055608956901393935694701119434775235840422641497164906951936899979932146072383121390810206221897429600856554539772305369549710288888325526487021329012097540833128568511729752229214296692430513968456455539432881415381331735108409226312132476668"},
+	} {
+		for _, prec := range []uint{24, 53, 64, 100, 200, 300, 400, 500, 600, 700, 800, 900, 1000} {
+			want := new(big.Float).SetPrec(prec)
+			want.Parse(test.want, 10)
+
+			z := test.c.Value(new(big.Float).SetPrec(prec))
+
+			if z.Cmp(want) != 0 {
+				t.Errorf("%v.Value(%d) =\ngot  %g;\nwant %g", test.c, prec, z, want)
+			}
+		}
+	}
+}
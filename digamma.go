@@ -0,0 +1,221 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// Digamma sets o to the digamma function ψ(z) = Γ'(z)/Γ(z) to o's precision
+// and returns o. If o's precision is zero, then it is given the precision of
+// z. Digamma(+Inf) = +Inf, Digamma(+0) = -Inf, and Digamma(-0) = +Inf. Panics
+// with ErrNaN if z is a negative integer, where the limits from either side
+// differ, or if z is -Inf.
+func Digamma(o, z *big.Float) *big.Float {
+	return Polygamma(o, 0, z)
+}
+
+// Polygamma sets o to the polygamma function ψ⁽ⁿ⁾(z), the nth derivative of
+// the digamma function, to o's precision and returns o. If o's precision is
+// zero, then it is given the precision of z. Polygamma(o, 0, z) is the same as
+// Digamma(o, z). For n ≥ 1, the result at +Inf is zero.
+//
+// At the poles z = 0, -1, -2, ..., the result is +Inf if n is odd. If n is
+// even, Polygamma(o, n, ±0) = ∓Inf, and other poles panic with ErrNaN. Panics
+// with ErrNaN if z is -Inf, or with a string if n is negative.
+func Polygamma(o *big.Float, n int, z *big.Float) *big.Float {
+	if n < 0 {
+		panic("bigfloat: Polygamma: negative order")
+	}
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	switch {
+	case z.IsInf():
+		if z.Signbit() {
+			panic(ErrNaN{msg: "Polygamma: argument is -Inf"})
+		}
+		if n == 0 {
+			return o.Set(z)
+		}
+		// ψ⁽ⁿ⁾ approaches zero from above if n is odd and from below if even.
+		o.SetInt64(0)
+		if n%2 == 0 {
+			o.Neg(o)
+		}
+		return o
+	case z.Sign() == 0 || z.IsInt() && z.Signbit():
+		if n%2 == 1 {
+			return o.SetInf(false)
+		}
+		if z.Sign() == 0 {
+			return o.SetInf(!z.Signbit())
+		}
+		panic(ErrNaN{msg: "Polygamma: argument is a pole"})
+	}
+	prec := o.Prec()
+
+	// As in LogGamma, retry with more precision if the result is much
+	// smaller than the quantities we compute it from, e.g. near the zeros of
+	// the digamma function.
+	// Outside the poles, ψ⁽ⁿ⁾ is zero only at irrational points, so a zero
+	// result means that cancellation consumed every bit, and ziv retries.
+	r := ziv(prec, func(wp uint) (*big.Float, int) {
+		return psi(new(big.Float).SetPrec(wp), n, z)
+	})
+	return o.Set(r)
+}
+
+// psi sets o to ψ⁽ⁿ⁾(z) for finite z which is not a pole. It returns o and the
+// binary exponent of the largest quantity in the computation. The absolute
+// error in o is about 2**(mag - o.Prec()).
+func psi(o *big.Float, n int, z *big.Float) (*big.Float, int) {
+	if !z.Signbit() {
+		return psiPos(o, n, z)
+	}
+	// Reflect using
+	//    ψ⁽ⁿ⁾(z) = (-1)**n ψ⁽ⁿ⁾(1-z) - π**(n+1) P_n(cot(πz)),
+	// where (d/dy)**n cot(y) = P_n(cot(y)).
+	wp := o.Prec()
+	w := new(big.Float).SetPrec(reflectPrec(z, wp)).Sub(&gonep, z)
+	r, mag := psiPos(new(big.Float).SetPrec(wp), n, w)
+	if n%2 == 1 {
+		r.Neg(r)
+	}
+	c := cosPi(new(big.Float).SetPrec(wp), z)
+	c.Quo(c, sinPi(new(big.Float).SetPrec(wp), z))
+	p := new(big.Float).SetPrec(wp)
+	coef := cotDerivPoly(n)
+	for i := len(coef) - 1; i >= 0; i-- {
+		p.Mul(p, c)
+		p.Add(p, new(big.Float).SetInt(coef[i]))
+	}
+	pi := new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+	for i := 0; i <= n; i++ {
+		p.Mul(p, pi)
+	}
+	if e := p.MantExp(nil); e > mag {
+		mag = e
+	}
+	return o.Sub(r, p), mag
+}
+
+// cotDerivPoly returns the coefficients, in order of increasing degree, of the
+// polynomial P_n for which (d/dy)**n cot(y) = P_n(cot(y)). These follow from
+// P_0(c) = c and P_(n+1)(c) = -(1 + c²) P_n'(c).
+func cotDerivPoly(n int) []*big.Int {
+	p := []*big.Int{big.NewInt(0), big.NewInt(1)}
+	for i := 0; i < n; i++ {
+		q := make([]*big.Int, len(p)+1)
+		for j := range q {
+			q[j] = new(big.Int)
+		}
+		var d big.Int
+		for j := 1; j < len(p); j++ {
+			d.Mul(p[j], big.NewInt(int64(j)))
+			q[j-1].Sub(q[j-1], &d)
+			q[j+1].Sub(q[j+1], &d)
+		}
+		p = q
+	}
+	return p
+}
+
+// psiPos sets o to ψ⁽ⁿ⁾(x) for finite x > 0 and returns o along with the binary
+// exponent of the largest quantity in the computation.
+func psiPos(o *big.Float, n int, x *big.Float) (*big.Float, int) {
+	wp := o.Prec() + 16
+	// Shift x upward by m so that the asymptotic series converges to wp bits,
+	// using
+	//    ψ⁽ⁿ⁾(x) = ψ⁽ⁿ⁾(x+m) - (-1)**n n! Σ 1/(x+i)**(n+1), 0 ≤ i < m.
+	var m int64
+	lim := float64(wp)/5 + float64(n) + 1
+	if xf, _ := x.Float64(); xf < lim {
+		m = int64(math.Ceil(lim - xf))
+		wp += uint(bits.Len64(uint64(m)))
+	}
+	y := new(big.Float).SetPrec(wp).Set(x)
+	var sum *big.Float
+	if m > 0 {
+		sum = new(big.Float).SetPrec(wp)
+		t := new(big.Float).SetPrec(wp)
+		for i := int64(0); i < m; i++ {
+			t.SetInt64(i)
+			t.Add(t, x)
+			sum.Add(sum, t.Quo(&gonep, powInt(t, n+1)))
+		}
+		y.Add(y, t.SetInt64(m))
+	}
+
+	a := psiAsymp(new(big.Float).SetPrec(wp), n, y)
+	mag := a.MantExp(nil)
+	if sum != nil {
+		sum.Mul(sum, new(big.Float).SetInt(new(big.Int).MulRange(1, int64(n))))
+		if e := sum.MantExp(nil); e > mag {
+			mag = e
+		}
+		if n%2 == 0 {
+			a.Sub(a, sum)
+		} else {
+			a.Add(a, sum)
+		}
+	}
+	return o.Set(a), mag
+}
+
+// psiAsymp sets o to ψ⁽ⁿ⁾(y) to o's precision using the asymptotic series
+//
+//	ψ(y) ~ log y - 1/(2y) - Σ B_2k / (2k y**2k),
+//	ψ⁽ⁿ⁾(y) ~ (-1)**(n+1) [(n-1)!/y**n + n!/(2y**(n+1)) + Σ B_2k (2k+n-1)!/((2k)! y**(2k+n))]
+//
+// and returns o. y must be at least o.Prec()/5 + n + 1 for the series to
+// reach o's precision.
+func psiAsymp(o *big.Float, n int, y *big.Float) *big.Float {
+	wp := o.Prec()
+	r := new(big.Float).SetPrec(wp).Quo(&gonep, y)
+	r2 := new(big.Float).SetPrec(wp).Mul(r, r)
+	// rn = 1/y**n
+	rn := new(big.Float).SetPrec(wp).SetInt64(1)
+	if n > 0 {
+		rn = powInt(r, n)
+	}
+	nf := new(big.Float).SetPrec(wp).SetInt(new(big.Int).MulRange(1, int64(n)))
+	t := new(big.Float).SetPrec(wp)
+
+	// The sign is applied at the end, so this computes -ψ(y) when n is 0.
+	if n == 0 {
+		Log(o, y).Neg(o)
+	} else {
+		o.Quo(nf, t.SetInt64(int64(n)))
+		o.Mul(o, rn)
+	}
+	t.Mul(nf, rn)
+	t.Mul(t, r)
+	o.Add(o, quicksh(t, t, -1))
+
+	// f = (2k+n-1)!/(2k)!, starting from k = 1
+	f := new(big.Float).SetPrec(wp).SetInt(new(big.Int).MulRange(1, int64(n+1)))
+	quicksh(f, f, -1)
+	pw := new(big.Float).SetPrec(wp).Mul(rn, r2)
+	b := bernoulli(int(wp/7) + 2)
+	for k := 1; ; k++ {
+		if k >= len(b) {
+			b = bernoulli(2 * k)
+		}
+		t.SetRat(b[k])
+		t.Mul(t, f)
+		t.Mul(t, pw)
+		o.Add(o, t)
+		if t.Sign() == 0 || t.MantExp(nil) < o.MantExp(nil)-int(wp) {
+			break
+		}
+		pw.Mul(pw, r2)
+		j := int64(2*k + n)
+		f.Mul(f, t.SetInt64(j*(j+1)))
+		f.Quo(f, t.SetInt64(int64((2*k+1)*(2*k+2))))
+	}
+	if n%2 == 0 {
+		o.Neg(o)
+	}
+	return o
+}
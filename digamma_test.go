@@ -0,0 +1,151 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+const (
+	// Euler–Mascheroni constant γ
+	eulerStr = "0.5772156649015328606065120900824024310421593359399235988057672348848677267776646709369470632917467495"
+	// Apéry's constant ζ(3)
+	aperyStr = "1.2020569031595942853997381615114499907649862923404988817922715553418382057863130901864558736093352581"
+)
+
+func TestPolygammaSpecialPoints(t *testing.T) {
+	const prec = 300
+	wp := uint(prec + 64)
+	pi := bigfloat.Pi(new(big.Float).SetPrec(wp))
+	pi2 := new(big.Float).Mul(pi, pi)
+	eg := parse(eulerStr, wp)
+	ln2 := bigfloat.Log(new(big.Float).SetPrec(wp), big.NewFloat(2))
+
+	psi12 := new(big.Float).SetPrec(wp).Add(eg, quick(ln2, 2))
+	psi12.Neg(psi12)
+	for _, test := range []struct {
+		n    int
+		z    float64
+		want *big.Float
+	}{
+		// ψ(1) = -γ
+		{0, 1, new(big.Float).Neg(eg)},
+		// ψ(1/2) = -γ - 2 log 2
+		{0, 0.5, psi12},
+		// ψ(-1/2) = ψ(1/2) + 2
+		{0, -0.5, new(big.Float).Add(psi12, big.NewFloat(2))},
+		// ψ'(1) = π²/6
+		{1, 1, new(big.Float).Quo(pi2, big.NewFloat(6))},
+		// ψ'(1/2) = π²/2
+		{1, 0.5, new(big.Float).Quo(pi2, big.NewFloat(2))},
+		// ψ''(1) = -2ζ(3)
+		{2, 1, quick(parse(aperyStr, wp), -2)},
+		// ψ'''(1) = π⁴/15
+		{3, 1, new(big.Float).Quo(new(big.Float).Mul(pi2, pi2), big.NewFloat(15))},
+	} {
+		got := bigfloat.Polygamma(new(big.Float).SetPrec(prec), test.n, big.NewFloat(test.z))
+		if !closeTo(got, test.want, prec-2) {
+			t.Errorf("Polygamma(%d, %g) =\ngot  %g;\nwant %g", test.n, test.z, got, test.want)
+		}
+	}
+}
+
+// quick returns x·k at x's precision.
+func quick(x *big.Float, k float64) *big.Float {
+	return new(big.Float).SetPrec(x.Prec()).Mul(x, big.NewFloat(k))
+}
+
+func TestPolygammaRecurrence(t *testing.T) {
+	// ψ⁽ⁿ⁾(x+1) = ψ⁽ⁿ⁾(x) + (-1)**n n! / x**(n+1)
+	for _, prec := range []uint{53, 200, 1000} {
+		for _, x := range []float64{0.001953125, 1.75, 20.5, -0.25, -3.875, -100.5} {
+			for n := 0; n < 5; n++ {
+				got := bigfloat.Polygamma(new(big.Float).SetPrec(prec), n, big.NewFloat(x+1))
+				want := bigfloat.Polygamma(new(big.Float).SetPrec(prec+64), n, big.NewFloat(x))
+				d := new(big.Float).SetPrec(prec + 64).SetInt64(1)
+				for i := 0; i <= n; i++ {
+					d.Quo(d, big.NewFloat(x))
+				}
+				d.Mul(d, new(big.Float).SetInt(new(big.Int).MulRange(1, int64(n))))
+				if n%2 == 1 {
+					d.Neg(d)
+				}
+				want.Add(want, d)
+				if !closeTo(got, want, prec-4) {
+					t.Errorf("prec = %d: Polygamma(%d, %g) =\ngot  %g;\nwant %g", prec, n, x+1, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDigammaRoot(t *testing.T) {
+	// Near the positive root of ψ, the result is tiny, but it must still be
+	// accurate to the requested precision.
+	x0 := parse("1.4616321449683623412626595423257213284681962040064463512959884085987864403538018102430749927", 400)
+	want := bigfloat.Digamma(new(big.Float).SetPrec(400), x0)
+	if want.MantExp(nil) > -290 {
+		t.Errorf("Digamma(x0) = %g is too large", want)
+	}
+	for _, prec := range []uint{24, 53, 100, 200} {
+		got := bigfloat.Digamma(new(big.Float).SetPrec(prec), x0)
+		if !closeTo(got, want, prec-2) {
+			t.Errorf("prec = %d: Digamma(x0) =\ngot  %g;\nwant %g", prec, got, want)
+		}
+	}
+}
+
+func TestPolygammaSpecialValues(t *testing.T) {
+	for _, test := range []struct {
+		n    int
+		z    float64
+		want float64
+	}{
+		{0, math.Inf(1), math.Inf(1)},
+		{0, 0, math.Inf(-1)},
+		{0, math.Copysign(0, -1), math.Inf(1)},
+		{1, math.Inf(1), 0},
+		{2, math.Inf(1), math.Copysign(0, -1)},
+		{1, 0, math.Inf(1)},
+		{1, -3, math.Inf(1)},
+		{2, 0, math.Inf(-1)},
+		{2, math.Copysign(0, -1), math.Inf(1)},
+	} {
+		x64, acc := bigfloat.Polygamma(new(big.Float), test.n, big.NewFloat(test.z)).Float64()
+		if x64 != test.want || math.Signbit(x64) != math.Signbit(test.want) || acc != big.Exact {
+			t.Errorf("Polygamma(%d, %g) =\n got %g (%s);\nwant %g (Exact)", test.n, test.z, x64, acc, test.want)
+		}
+	}
+	for _, test := range []struct {
+		n int
+		z float64
+	}{
+		{0, -1},
+		{0, -1e10},
+		{2, -4},
+		{0, math.Inf(-1)},
+		{1, math.Inf(-1)},
+	} {
+		expectNaN(t, fmt.Sprintf("Polygamma(%d, %g)", test.n, test.z), func() {
+			bigfloat.Polygamma(new(big.Float), test.n, big.NewFloat(test.z))
+		})
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkDigamma(b *testing.B) {
+	z := big.NewFloat(2.5)
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Digamma(o, z)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package bigfloat
+
+import (
+	"io"
+	"math"
+	"math/big"
+)
+
+// digitChunk is the largest number of digits WriteDigits formats at once.
+const digitChunk = 4096
+
+// WriteDigits writes the expansion of c in the given base to w, followed by
+// exactly digits digits after the radix point, e.g. "3.14159" for π with
+// digits = 5 in base 10. If digits is zero, only the integer part is written,
+// without a radix point. Digits above 9 are written as lowercase letters, so
+// base 16 gives a hexadecimal expansion.
+//
+// The digits written are those of the exact expansion truncated after the
+// last requested digit; they are never rounded. WriteDigits takes its value of
+// c from the cache, extending it if necessary, and increases the precision
+// further when the requested digits cannot be determined from the cached
+// value. The digits are converted and written in chunks, so the full
+// expansion is never held as a string in memory.
+//
+// WriteDigits returns the number of bytes written and any error returned by w.
+// Panics if base is not between 2 and 36 inclusive or if digits is negative.
+func WriteDigits(w io.Writer, c Constant, digits int, base int) (int64, error) {
+	if base < 2 || base > 36 {
+		panic("bigfloat: WriteDigits: base out of range")
+	}
+	if digits < 0 {
+		panic("bigfloat: WriteDigits: negative digit count")
+	}
+	bpow := new(big.Int).Exp(big.NewInt(int64(base)), big.NewInt(int64(digits)), nil)
+	// All of our constants are small, so a few bits suffice for the integer
+	// part.
+	prec := uint(math.Ceil(float64(digits)*math.Log2(float64(base)))) + 8
+	guard := uint(64)
+	var n *big.Int
+	for n == nil {
+		n = truncDigits(c.cached(prec+guard), bpow)
+		guard *= 2
+	}
+
+	dw := digitWriter{w: w, base: base, pows: make(map[int]*big.Int)}
+	ip, fp := n.QuoRem(n, bpow, new(big.Int))
+	dw.buf = ip.Append(dw.buf[:0], base)
+	if digits > 0 {
+		dw.buf = append(dw.buf, '.')
+	}
+	dw.flush()
+	dw.write(fp, digits)
+	return dw.n, dw.err
+}
+
+// truncDigits returns ⌊v·bpow⌋ if that value is the same for every real v
+// strictly within one ulp of the positive number x, or nil otherwise.
+func truncDigits(x *big.Float, bpow *big.Int) *big.Int {
+	// Decompose x into m·2**exp with m an integer.
+	m := new(big.Float)
+	exp := x.MantExp(m) - int(x.Prec())
+	mi, _ := quicksh(m, m, int(x.Prec())).Int(nil)
+
+	// The result is unambiguous if ⌈(m+1)·bpow·2**exp⌉ - ⌊(m-1)·bpow·2**exp⌋
+	// is exactly 1.
+	lo := new(big.Int).Sub(mi, big.NewInt(1))
+	hi := new(big.Int).Add(mi, big.NewInt(1))
+	lo.Mul(lo, bpow)
+	hi.Mul(hi, bpow)
+	if exp >= 0 {
+		lo.Lsh(lo, uint(exp))
+		hi.Lsh(hi, uint(exp))
+	} else {
+		s := uint(-exp)
+		lo.Rsh(lo, s)
+		one := big.NewInt(1)
+		hi.Add(hi, one.Sub(one.Lsh(one, s), big.NewInt(1)))
+		hi.Rsh(hi, s)
+	}
+	if hi.Sub(hi, lo).Cmp(big.NewInt(1)) != 0 {
+		return nil
+	}
+	return lo
+}
+
+// digitWriter writes big.Int values as fixed-width digit strings in chunks.
+type digitWriter struct {
+	w    io.Writer
+	base int
+	buf  []byte
+	tmp  []byte
+	n    int64
+	err  error
+	pows map[int]*big.Int
+}
+
+// flush writes the contents of buf.
+func (dw *digitWriter) flush() {
+	if dw.err != nil {
+		return
+	}
+	k, err := dw.w.Write(dw.buf)
+	dw.n += int64(k)
+	dw.err = err
+}
+
+// pow returns base**k, memoized.
+func (dw *digitWriter) pow(k int) *big.Int {
+	p := dw.pows[k]
+	if p == nil {
+		p = new(big.Int).Exp(big.NewInt(int64(dw.base)), big.NewInt(int64(k)), nil)
+		dw.pows[k] = p
+	}
+	return p
+}
+
+// write writes x, which must be less than base**k, zero-padded to exactly k
+// digits. x is overwritten.
+func (dw *digitWriter) write(x *big.Int, k int) {
+	if dw.err != nil || k == 0 {
+		return
+	}
+	if k <= digitChunk {
+		dw.tmp = x.Append(dw.tmp[:0], dw.base)
+		dw.buf = dw.buf[:0]
+		for i := len(dw.tmp); i < k; i++ {
+			dw.buf = append(dw.buf, '0')
+		}
+		dw.buf = append(dw.buf, dw.tmp...)
+		dw.flush()
+		return
+	}
+	// Split x into its high k-h and low h digits.
+	h := k / 2
+	lo := new(big.Int)
+	x.QuoRem(x, dw.pow(h), lo)
+	dw.write(x, k-h)
+	dw.write(lo, h)
+}
@@ -0,0 +1,110 @@
+package bigfloat_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestWriteDigits(t *testing.T) {
+	for _, test := range []struct {
+		c    bigfloat.Constant
+		base int
+		want string
+	}{
+		{bigfloat.ConstPi, 10, "3.14159265358979323846264338327950288419716939937510582097494459230781640628620899862803482534211706798214808651328230664709384460955058223172535940812848111745028410270193852110555964462294895493038196442881097566593344612847564823378678316527120190914564856692346034861045432664821339360726024914127372458700660631558817488152092096282925409171536"},
+		{bigfloat.ConstPi, 16, "3.243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89452821e638d01377be5466cf34e90c6cc0ac29b7c97c50dd3f84d5b5b5470917"},
+		{bigfloat.ConstPi, 2, "11.001001000011111101101010100010001000010110100011000010001101001100010011000110011000101000101110000000110111"},
+		{bigfloat.ConstE, 10, "2.71828182845904523536028747135266249775724709369995957496696762772407663035354759457138217852516642742746639193200305992181741359662904357290033429526059563073813232862794349076323382988075319525101901157383418793070215408914993488416750924476146066808226480016847741185374234544243710753907774499206955170276183860626133138458300075204493382656029"},
+		{bigfloat.ConstLn2, 10, "0.69314718055994530941723212145817656807550013436025525412068000949339362196969471560586332699641868754200148102057068573368552023575813055703267075163507596193072757082837143519030703862389167347112335011536449795523912047517268157493206515552473413952588295045300709532636664265410423915781495204374043038550080194417064167151864471283996817178454"},
+	} {
+		// Every prefix of the expansion must match, so that no digit is
+		// affected by rounding.
+		point := strings.IndexByte(test.want, '.')
+		for digits := 0; point+1+digits <= len(test.want); digits++ {
+			want := test.want[:point+1+digits]
+			if digits == 0 {
+				want = test.want[:point]
+			}
+			var b strings.Builder
+			n, err := bigfloat.WriteDigits(&b, test.c, digits, test.base)
+			if err != nil {
+				t.Errorf("WriteDigits(%v, %d, %d) returned error %v", test.c, digits, test.base, err)
+			}
+			if n != int64(b.Len()) {
+				t.Errorf("WriteDigits(%v, %d, %d) reported %d bytes written, but wrote %d", test.c, digits, test.base, n, b.Len())
+			}
+			if b.String() != want {
+				t.Errorf("WriteDigits(%v, %d, %d) =\ngot  %s;\nwant %s", test.c, digits, test.base, b.String(), want)
+			}
+		}
+	}
+}
+
+func TestWriteDigitsLong(t *testing.T) {
+	// Enough digits that the output is split into several chunks.
+	const digits = 20000
+	pi := bigfloat.ComputePi(new(big.Float).SetPrec(digits*10/3+256), bigfloat.PiBrentSalamin)
+	want := pi.Text('f', digits+20)[:digits+2]
+	var b strings.Builder
+	if _, err := bigfloat.WriteDigits(&b, bigfloat.ConstPi, digits, 10); err != nil {
+		t.Errorf("WriteDigits returned error %v", err)
+	}
+	if got := b.String(); got != want {
+		for i := range want {
+			if i >= len(got) || got[i] != want[i] {
+				t.Errorf("digits differ starting at byte %d", i)
+				break
+			}
+		}
+		if len(got) != len(want) {
+			t.Errorf("wrong length: got %d, want %d", len(got), len(want))
+		}
+	}
+}
+
+// limitWriter fails once more than n bytes have been written to it.
+type limitWriter struct {
+	n int
+}
+
+var errLimit = errors.New("limit reached")
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		k := w.n
+		w.n = 0
+		return k, errLimit
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestWriteDigitsError(t *testing.T) {
+	w := &limitWriter{n: 5000}
+	n, err := bigfloat.WriteDigits(w, bigfloat.ConstPi, 10000, 10)
+	if err != errLimit {
+		t.Errorf("wrong error: want %v, got %v", errLimit, err)
+	}
+	if n != 5000 {
+		t.Errorf("wrong byte count: want 5000, got %d", n)
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkWriteDigits(b *testing.B) {
+	for _, digits := range []int{1e2, 1e3, 1e4, 1e5} {
+		b.Run(fmt.Sprintf("%v", digits), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.WriteDigits(ioutil.Discard, bigfloat.ConstPi, digits, 10)
+			}
+		})
+	}
+}
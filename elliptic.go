@@ -0,0 +1,429 @@
+package bigfloat
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// EllipticK sets o to the complete elliptic integral of the first kind
+//
+//	K(k) = ∫ dθ / √(1 - k² sin²θ), 0 ≤ θ ≤ π/2,
+//
+// with modulus k, to o's precision and returns o. If o's precision is zero,
+// then it is given the precision of k. K(±1) = +Inf. Panics with ErrNaN if
+// |k| > 1.
+func EllipticK(o, k *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(k.Prec())
+	}
+	switch c := ellipticCmpModulus(k); {
+	case c > 0:
+		panic(ErrNaN{msg: "EllipticK: modulus is outside [-1, 1]"})
+	case c == 0:
+		return o.SetInf(false)
+	}
+	wp := o.Prec() + 64
+	// K(k) = π / (2 AGM(1, k')), with k' = √(1 - k²)
+	m := AGM(new(big.Float).SetPrec(wp), &gonep, ellipticComplement(k, wp))
+	return o.Quo(cachedPi(wp), quicksh(m, m, 1))
+}
+
+// EllipticE sets o to the complete elliptic integral of the second kind
+//
+//	E(k) = ∫ √(1 - k² sin²θ) dθ, 0 ≤ θ ≤ π/2,
+//
+// with modulus k, to o's precision and returns o. If o's precision is zero,
+// then it is given the precision of k. E(±1) = 1. Panics with ErrNaN if
+// |k| > 1.
+func EllipticE(o, k *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(k.Prec())
+	}
+	switch c := ellipticCmpModulus(k); {
+	case c > 0:
+		panic(ErrNaN{msg: "EllipticE: modulus is outside [-1, 1]"})
+	case c == 0:
+		return o.SetInt64(1)
+	}
+	_, e := ellipticKE(k, o.Prec()+64)
+	return o.Set(e)
+}
+
+// EllipticF sets o to the incomplete elliptic integral of the first kind
+//
+//	F(φ, k) = ∫ dθ / √(1 - k² sin²θ), 0 ≤ θ ≤ φ,
+//
+// with amplitude phi and modulus k, to o's precision and returns o. If o's
+// precision is zero, then it is given the greater of the precisions of phi
+// and k. F(±0, k) = ±0, and F(φ, ±1) = ±Inf for |φ| ≥ π/2. Panics with ErrNaN
+// if phi or k is infinite or if k² sin²θ > 1 anywhere on the path of
+// integration, which for |k| > 1 means that |sin φ| must be at most 1/|k| and
+// |φ| at most π/2.
+func EllipticF(o, phi, k *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(phi, k))
+	}
+	if phi.IsInf() || k.IsInf() {
+		panic(ErrNaN{msg: "EllipticF: infinite argument"})
+	}
+	if phi.Sign() == 0 {
+		return o.Set(phi)
+	}
+	return ellipticInc(o, phi, k, false)
+}
+
+// EllipticEInc sets o to the incomplete elliptic integral of the second kind
+//
+//	E(φ, k) = ∫ √(1 - k² sin²θ) dθ, 0 ≤ θ ≤ φ,
+//
+// with amplitude phi and modulus k, to o's precision and returns o. If o's
+// precision is zero, then it is given the greater of the precisions of phi
+// and k. E(±0, k) = ±0. Panics with ErrNaN under the same conditions as
+// EllipticF.
+func EllipticEInc(o, phi, k *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(phi, k))
+	}
+	if phi.IsInf() || k.IsInf() {
+		panic(ErrNaN{msg: "EllipticEInc: infinite argument"})
+	}
+	if phi.Sign() == 0 {
+		return o.Set(phi)
+	}
+	return ellipticInc(o, phi, k, true)
+}
+
+// ellipticCmpModulus returns -1, 0, or +1 as |k| is less than, equal to, or
+// greater than 1.
+func ellipticCmpModulus(k *big.Float) int {
+	if k.IsInf() {
+		return 1
+	}
+	return new(big.Float).Abs(k).Cmp(&gonep)
+}
+
+// ellipticComplement returns the complementary modulus √(1 - k²) to precision
+// wp for |k| < 1. The result has full relative precision even for k close to
+// ±1.
+func ellipticComplement(k *big.Float, wp uint) *big.Float {
+	kc := new(big.Float).SetPrec(wp)
+	if k.MantExp(nil) < 0 {
+		// |k| < 1/2, so there is no cancellation.
+		kc.Mul(k, k)
+		kc.Sub(&gonep, kc)
+	} else {
+		// 1/2 ≤ |k| < 1, so 1 - |k| is exact at the precision of k, and
+		// 1 + |k| is exact with one more bit.
+		a := new(big.Float).SetPrec(k.Prec()).Abs(k)
+		b := new(big.Float).SetPrec(k.Prec()+1).Add(a, &gonep)
+		a.Sub(&gonep, a)
+		kc.Mul(a, b)
+	}
+	return kc.Sqrt(kc)
+}
+
+// ellipticKE returns K(k) and E(k) to precision wp for |k| < 1.
+func ellipticKE(k *big.Float, wp uint) (kk, e *big.Float) {
+	kc := ellipticComplement(k, wp)
+	// E(k) = K(k) (1 - S), where S is small when K(k) is large, so the
+	// subtraction loses about log₂ K(k) bits, which is at most about
+	// log₂ log₂(1/k').
+	if x := kc.MantExp(nil); x < 0 {
+		wp += uint(bits.Len(uint(-x)))
+		kc = ellipticComplement(k, wp)
+	}
+
+	// Run the AGM of 1 and k', summing
+	//    S = Σ 2**(n-1) c_n², n ≥ 0,
+	// with c_0 = k and c_(n+1) = (a_n - b_n)/2 = c_n² / (4 a_(n+1)), which
+	// avoids the cancellation in the difference.
+	a := new(big.Float).SetPrec(wp).SetInt64(1)
+	b := new(big.Float).SetPrec(wp).Set(kc)
+	c2 := new(big.Float).SetPrec(wp).Mul(k, k)
+	s := quicksh(new(big.Float).SetPrec(wp), c2, -1)
+	t := new(big.Float).SetPrec(wp)
+	for n := 0; c2.Sign() != 0 && c2.MantExp(nil)+n >= -int(wp); n++ {
+		t.Set(a)
+		quicksh(a, a.Add(a, b), -1)
+		b.Sqrt(b.Mul(b, t))
+		// c_(n+1)² = c_n⁴ / (16 a_(n+1)²)
+		t.Mul(a, a)
+		c2.Mul(c2, c2)
+		c2.Quo(c2, quicksh(t, t, 4))
+		s.Add(s, quicksh(t, c2, n))
+	}
+	// The remaining difference between a and b is below the working
+	// precision, so a is the AGM.
+	kk = new(big.Float).SetPrec(wp).Quo(cachedPi(wp), quicksh(a, a, 1))
+	e = new(big.Float).SetPrec(wp).Sub(&gonep, s)
+	e.Mul(e, kk)
+	return kk, e
+}
+
+// ellipticInc sets o to F(φ, k), or E(φ, k) if second is true, for finite,
+// nonzero phi and finite k.
+func ellipticInc(o, phi, k *big.Float, second bool) *big.Float {
+	name := "EllipticF"
+	if second {
+		name = "EllipticEInc"
+	}
+	prec := o.Prec()
+	wp := prec + 64
+	for {
+		// Write φ = mπ + r with |r| ≤ π/2. Then F(φ, k) = 2m K(k) + F(r, k),
+		// and likewise for E.
+		r, m := reducePi(phi, wp)
+		neg := r.Signbit()
+		r.Abs(r)
+		// sin r and cos r = sin(π/2 - r)
+		s := sinReduced(new(big.Float).SetPrec(wp), r)
+		c := new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+		quicksh(c, c, -1)
+		c.Sub(c, r)
+		sinReduced(c, c.Abs(c))
+		c.Mul(c, c)
+		// Δ² = 1 - k² sin²r
+		ks := new(big.Float).SetPrec(wp).Mul(k, s)
+		d := new(big.Float).SetPrec(wp).Mul(ks, ks)
+		d.Sub(&gonep, d)
+		if d.Sign() < 0 || m.Sign() != 0 && ellipticCmpModulus(k) > 0 {
+			panic(ErrNaN{msg: name + ": amplitude is outside the domain for modulus greater than 1"})
+		}
+		// The integrals depend on Δ² through about its square root, so an
+		// absolute error in Δ² costs about half its exponent in bits.
+		if x := d.MantExp(nil); d.Sign() != 0 && x < -32 && uint(-x/2) > wp-prec-64 {
+			wp = prec + 64 + uint(-x/2)
+			continue
+		}
+
+		// F(r, k) = sin r R_F(cos²r, Δ², 1)
+		// E(r, k) = F(r, k) - k² sin³r R_D(cos²r, Δ², 1) / 3
+		u := carlsonRF(c, d, &gonep, wp)
+		u.Mul(u, s)
+		if second {
+			v := carlsonRD(c, d, &gonep, wp)
+			ks.Mul(ks, ks)
+			ks.Mul(ks, s)
+			v.Mul(v, ks)
+			v.Quo(v, new(big.Float).SetInt64(3))
+			u.Sub(u, v)
+		}
+		if neg {
+			u.Neg(u)
+		}
+		if m.Sign() == 0 {
+			return o.Set(u)
+		}
+
+		var whole *big.Float
+		switch {
+		case ellipticCmpModulus(k) == 0:
+			if !second {
+				return o.SetInf(m.Signbit())
+			}
+			whole = new(big.Float).SetPrec(wp).SetInt64(1)
+		case second:
+			_, whole = ellipticKE(k, wp)
+		default:
+			whole, _ = ellipticKE(k, wp)
+		}
+		quicksh(whole, whole.Mul(whole, m), 1)
+		return o.Add(u, whole)
+	}
+}
+
+// reducePi returns r and m such that x = mπ + r with |r| ≤ π/2, where m is an
+// integer and r has precision and relative accuracy wp.
+func reducePi(x *big.Float, wp uint) (r, m *big.Float) {
+	m = new(big.Float)
+	if x.MantExp(nil) <= 0 {
+		// |x| < 1 < π/2
+		return new(big.Float).SetPrec(wp).Set(x), m
+	}
+	// The subtraction can cancel as many bits as x has, and then some.
+	p := wp + uint(x.MantExp(nil)) + x.Prec() + 64
+	for {
+		pi := cachedPi(p)
+		m.SetPrec(p).Quo(x, pi)
+		Round(m, m, big.ToNearestEven)
+		r = new(big.Float).SetPrec(p).Mul(m, pi)
+		r.Sub(x, r)
+		// The absolute error in r is about 2**(e - p), where e is the
+		// exponent of x.
+		if r.MantExp(nil) >= int(wp)+x.MantExp(nil)-int(p) {
+			return r.SetPrec(wp), m
+		}
+		p *= 2
+	}
+}
+
+// carlsonRF returns Carlson's symmetric elliptic integral of the first kind
+//
+//	R_F(x, y, z) = ½ ∫ dt / √((t+x)(t+y)(t+z)), t ≥ 0,
+//
+// to precision wp, for nonnegative x, y, z of which at most one is zero.
+func carlsonRF(x, y, z *big.Float, wp uint) *big.Float {
+	// Following B. C. Carlson, Numerical computation of real or complex
+	// elliptic integrals, Numerical Algorithms 10 (1995), pp. 13-26. Each
+	// application of the duplication theorem quarters the deviation of the
+	// arguments from their mean, and the series after it has error of order
+	// the sixth power of the deviation.
+	v := [3]*big.Float{
+		new(big.Float).SetPrec(wp).Set(x),
+		new(big.Float).SetPrec(wp).Set(y),
+		new(big.Float).SetPrec(wp).Set(z),
+	}
+	a := new(big.Float).SetPrec(wp).Add(v[0], v[1])
+	a.Add(a, v[2])
+	a.Quo(a, new(big.Float).SetInt64(3))
+	dx := new(big.Float).SetPrec(wp).Sub(a, v[0])
+	dy := new(big.Float).SetPrec(wp).Sub(a, v[1])
+	dz := new(big.Float).SetPrec(wp).Sub(a, v[2])
+	n := carlsonDuplicate(&v, a, nil, wp, dx, dy, dz)
+
+	// X = (A₀ - x)/(4**n A), Y likewise, Z = -(X + Y)
+	X := quicksh(dx, dx, -2*n)
+	X.Quo(X, a)
+	Y := quicksh(dy, dy, -2*n)
+	Y.Quo(Y, a)
+	Z := new(big.Float).SetPrec(wp).Add(X, Y)
+	Z.Neg(Z)
+	// E₂ = XY - Z², E₃ = XYZ
+	e2 := new(big.Float).SetPrec(wp).Mul(X, Y)
+	e3 := new(big.Float).SetPrec(wp).Mul(e2, Z)
+	e2.Sub(e2, Z.Mul(Z, Z))
+	// 1 - E₂/10 + E₃/14 + E₂²/24 - 3E₂E₃/44
+	s := carlsonSeries(wp, []carlsonTerm{
+		{-1, 10, []*big.Float{e2}},
+		{1, 14, []*big.Float{e3}},
+		{1, 24, []*big.Float{e2, e2}},
+		{-3, 44, []*big.Float{e2, e3}},
+	})
+	return s.Quo(s, a.Sqrt(a))
+}
+
+// carlsonRD returns Carlson's symmetric elliptic integral of the second kind
+//
+//	R_D(x, y, z) = 3/2 ∫ dt / ((t+z) √((t+x)(t+y)(t+z))), t ≥ 0,
+//
+// to precision wp, for nonnegative x and y of which at most one is zero and
+// positive z.
+func carlsonRD(x, y, z *big.Float, wp uint) *big.Float {
+	v := [3]*big.Float{
+		new(big.Float).SetPrec(wp).Set(x),
+		new(big.Float).SetPrec(wp).Set(y),
+		new(big.Float).SetPrec(wp).Set(z),
+	}
+	// A₀ = (x + y + 3z)/5
+	a := new(big.Float).SetPrec(wp).Mul(v[2], new(big.Float).SetInt64(3))
+	a.Add(a, v[0])
+	a.Add(a, v[1])
+	a.Quo(a, new(big.Float).SetInt64(5))
+	dx := new(big.Float).SetPrec(wp).Sub(a, v[0])
+	dy := new(big.Float).SetPrec(wp).Sub(a, v[1])
+	dz := new(big.Float).SetPrec(wp).Sub(a, v[2])
+	sum := new(big.Float).SetPrec(wp)
+	n := carlsonDuplicate(&v, a, sum, wp, dx, dy, dz)
+
+	// X = (A₀ - x)/(4**n A), Y likewise, Z = -(X + Y)/3
+	X := quicksh(dx, dx, -2*n)
+	X.Quo(X, a)
+	Y := quicksh(dy, dy, -2*n)
+	Y.Quo(Y, a)
+	Z := new(big.Float).SetPrec(wp).Add(X, Y)
+	Z.Quo(Z, new(big.Float).SetInt64(-3))
+	// E₂ = XY - 6Z², E₃ = (3XY - 8Z²)Z, E₄ = 3(XY - Z²)Z², E₅ = XYZ³
+	xy := new(big.Float).SetPrec(wp).Mul(X, Y)
+	z2 := new(big.Float).SetPrec(wp).Mul(Z, Z)
+	t := new(big.Float).SetPrec(wp)
+	e2 := new(big.Float).SetPrec(wp).Sub(xy, t.Mul(z2, new(big.Float).SetInt64(6)))
+	e3 := new(big.Float).SetPrec(wp).Mul(xy, new(big.Float).SetInt64(3))
+	e3.Sub(e3, t.Mul(z2, new(big.Float).SetInt64(8)))
+	e3.Mul(e3, Z)
+	e4 := new(big.Float).SetPrec(wp).Sub(xy, z2)
+	e4.Mul(e4, z2)
+	e4.Mul(e4, new(big.Float).SetInt64(3))
+	e5 := new(big.Float).SetPrec(wp).Mul(xy, z2)
+	e5.Mul(e5, Z)
+	// 1 - 3E₂/14 + E₃/6 + 9E₂²/88 - 3E₄/22 - 9E₂E₃/52 + 3E₅/26
+	s := carlsonSeries(wp, []carlsonTerm{
+		{-3, 14, []*big.Float{e2}},
+		{1, 6, []*big.Float{e3}},
+		{9, 88, []*big.Float{e2, e2}},
+		{-3, 22, []*big.Float{e4}},
+		{-9, 52, []*big.Float{e2, e3}},
+		{3, 26, []*big.Float{e5}},
+	})
+	// R_D = 4**-n A**(-3/2) s + 3 Σ
+	t.Sqrt(a)
+	t.Mul(t, a)
+	s.Quo(s, t)
+	quicksh(s, s, -2*n)
+	sum.Mul(sum, new(big.Float).SetInt64(3))
+	return s.Add(s, sum)
+}
+
+// carlsonDuplicate applies the duplication theorem to the arguments v with
+// mean a until the deviations d, scaled by 4**-n, are small enough for the
+// series in carlsonRF and carlsonRD, and returns n. If sum is not nil, it
+// accumulates the terms 4**-m / (√z (z + λ)) of R_D.
+func carlsonDuplicate(v *[3]*big.Float, a, sum *big.Float, wp uint, d ...*big.Float) int {
+	lim := -int(wp)/6 - 2
+	sq := [3]*big.Float{
+		new(big.Float).SetPrec(wp),
+		new(big.Float).SetPrec(wp),
+		new(big.Float).SetPrec(wp),
+	}
+	lambda := new(big.Float).SetPrec(wp)
+	t := new(big.Float).SetPrec(wp)
+	for n := 0; ; n++ {
+		done := true
+		for _, x := range d {
+			if x.Sign() != 0 && x.MantExp(nil)-2*n-a.MantExp(nil) >= lim {
+				done = false
+			}
+		}
+		if done {
+			return n
+		}
+		for i, x := range v {
+			sq[i].Sqrt(x)
+		}
+		// λ = √x√y + √y√z + √z√x
+		lambda.Mul(sq[0], sq[1])
+		lambda.Add(lambda, t.Mul(sq[1], sq[2]))
+		lambda.Add(lambda, t.Mul(sq[2], sq[0]))
+		if sum != nil {
+			t.Add(v[2], lambda)
+			t.Mul(t, sq[2])
+			t.Quo(&gonep, t)
+			sum.Add(sum, quicksh(t, t, -2*n))
+		}
+		for _, x := range v {
+			quicksh(x, x.Add(x, lambda), -2)
+		}
+		quicksh(a, a.Add(a, lambda), -2)
+	}
+}
+
+// carlsonTerm is a term num/den Πf of the series in carlsonRF and
+// carlsonRD.
+type carlsonTerm struct {
+	num, den int64
+	f        []*big.Float
+}
+
+// carlsonSeries returns 1 plus the sum of terms to precision wp.
+func carlsonSeries(wp uint, terms []carlsonTerm) *big.Float {
+	s := new(big.Float).SetPrec(wp).SetInt64(1)
+	t := new(big.Float).SetPrec(wp)
+	for _, term := range terms {
+		t.SetInt64(term.num)
+		for _, f := range term.f {
+			t.Mul(t, f)
+		}
+		t.Quo(t, new(big.Float).SetInt64(term.den))
+		s.Add(s, t)
+	}
+	return s
+}
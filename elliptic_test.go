@@ -0,0 +1,240 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestEllipticValues(t *testing.T) {
+	const prec = 300
+	wp := uint(prec + 64)
+	k := new(big.Float).SetPrec(wp).Sqrt(big.NewFloat(0.5))
+	// K(1/√2) = ϖ/√2
+	wantK := bigfloat.ConstLemniscate.Value(new(big.Float).SetPrec(wp))
+	wantK.Mul(wantK, k)
+	// E(1/√2) = (ϖ + π/ϖ)/(2√2)
+	wantE := bigfloat.Pi(new(big.Float).SetPrec(wp))
+	l := bigfloat.ConstLemniscate.Value(new(big.Float).SetPrec(wp))
+	wantE.Quo(wantE, l)
+	wantE.Add(wantE, l)
+	wantE.Mul(wantE, k)
+	wantE.Quo(wantE, big.NewFloat(2))
+	halfPi := bigfloat.Pi(new(big.Float).SetPrec(wp))
+	halfPi.Quo(halfPi, big.NewFloat(2))
+	for _, test := range []struct {
+		name string
+		f    func(o, k *big.Float) *big.Float
+		k    *big.Float
+		want *big.Float
+	}{
+		{"EllipticK", bigfloat.EllipticK, k, wantK},
+		{"EllipticE", bigfloat.EllipticE, k, wantE},
+		{"EllipticK", bigfloat.EllipticK, new(big.Float).Neg(k), wantK},
+		{"EllipticE", bigfloat.EllipticE, new(big.Float).Neg(k), wantE},
+		{"EllipticK", bigfloat.EllipticK, big.NewFloat(0), halfPi},
+		{"EllipticE", bigfloat.EllipticE, big.NewFloat(0), halfPi},
+	} {
+		got := test.f(new(big.Float).SetPrec(prec), test.k)
+		if !closeTo(got, test.want, prec-2) {
+			t.Errorf("%s(%g) =\ngot  %g;\nwant %g", test.name, test.k, got, test.want)
+		}
+	}
+}
+
+func TestEllipticLegendre(t *testing.T) {
+	// E(k) K(k') + E(k') K(k) - K(k) K(k') = π/2
+	const prec = 200
+	wp := uint(prec + 64)
+	want := bigfloat.Pi(new(big.Float).SetPrec(wp))
+	want.Quo(want, big.NewFloat(2))
+	nearOne := new(big.Float).SetPrec(wp).SetInt64(1)
+	nearOne.SetMantExp(nearOne, -100)
+	nearOne.Sub(big.NewFloat(1), nearOne)
+	for _, k := range []*big.Float{
+		big.NewFloat(1e-10),
+		big.NewFloat(0.25),
+		big.NewFloat(0.5),
+		big.NewFloat(0.9),
+		big.NewFloat(0.999999),
+		nearOne,
+	} {
+		kc := new(big.Float).SetPrec(2*wp).Mul(k, k)
+		kc.Sub(big.NewFloat(1), kc)
+		kc.Sqrt(kc)
+		kk := bigfloat.EllipticK(new(big.Float).SetPrec(wp), k)
+		e := bigfloat.EllipticE(new(big.Float).SetPrec(wp), k)
+		kkc := bigfloat.EllipticK(new(big.Float).SetPrec(wp), kc)
+		ec := bigfloat.EllipticE(new(big.Float).SetPrec(wp), kc)
+		got := new(big.Float).Mul(e, kkc)
+		got.Add(got, new(big.Float).Mul(ec, kk))
+		got.Sub(got, new(big.Float).Mul(kk, kkc))
+		if !closeTo(got, want, prec) {
+			t.Errorf("Legendre relation for k = %g: got %g, want %g", k, got, want)
+		}
+	}
+}
+
+func TestEllipticIncomplete(t *testing.T) {
+	const prec = 200
+	wp := uint(prec + 64)
+	pi := bigfloat.Pi(new(big.Float).SetPrec(wp))
+	halfPi := new(big.Float).Quo(pi, big.NewFloat(2))
+	for _, kf := range []float64{0, 0.25, -0.5, 0.9, 0.999999} {
+		k := big.NewFloat(kf)
+		kk := bigfloat.EllipticK(new(big.Float).SetPrec(wp), k)
+		e := bigfloat.EllipticE(new(big.Float).SetPrec(wp), k)
+		// F(π/2, k) = K(k), E(π/2, k) = E(k)
+		if got := bigfloat.EllipticF(new(big.Float).SetPrec(prec), halfPi, k); !closeTo(got, kk, prec-2) {
+			t.Errorf("EllipticF(π/2, %g) =\ngot  %g;\nwant %g", kf, got, kk)
+		}
+		if got := bigfloat.EllipticEInc(new(big.Float).SetPrec(prec), halfPi, k); !closeTo(got, e, prec-2) {
+			t.Errorf("EllipticEInc(π/2, %g) =\ngot  %g;\nwant %g", kf, got, e)
+		}
+		// F(φ + mπ, k) = F(φ, k) + 2m K(k), and likewise for E.
+		for _, phi := range []float64{0.125, 1, -1.5} {
+			for _, m := range []int64{-3, 1, 1000} {
+				x := new(big.Float).SetPrec(wp).SetInt64(m)
+				x.Mul(x, pi)
+				x.Add(x, big.NewFloat(phi))
+				got := bigfloat.EllipticF(new(big.Float).SetPrec(prec), x, k)
+				want := new(big.Float).SetPrec(wp).SetInt64(2 * m)
+				want.Mul(want, kk)
+				want.Add(want, bigfloat.EllipticF(new(big.Float).SetPrec(wp), big.NewFloat(phi), k))
+				if !closeTo(got, want, prec-4) {
+					t.Errorf("EllipticF(%g + %dπ, %g) =\ngot  %g;\nwant %g", phi, m, kf, got, want)
+				}
+				got = bigfloat.EllipticEInc(new(big.Float).SetPrec(prec), x, k)
+				want.SetInt64(2 * m)
+				want.Mul(want, e)
+				want.Add(want, bigfloat.EllipticEInc(new(big.Float).SetPrec(wp), big.NewFloat(phi), k))
+				if !closeTo(got, want, prec-4) {
+					t.Errorf("EllipticEInc(%g + %dπ, %g) =\ngot  %g;\nwant %g", phi, m, kf, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEllipticIncompleteFloat64(t *testing.T) {
+	// F(φ, 0) = E(φ, 0) = φ, F(φ, 1) = atanh(sin φ), E(φ, 1) = sin φ
+	for _, phi := range []float64{1e-10, 0.125, 1, 1.5, -0.75} {
+		x := big.NewFloat(phi)
+		for _, test := range []struct {
+			name string
+			f    func(o, phi, k *big.Float) *big.Float
+			k    float64
+			want float64
+		}{
+			{"EllipticF", bigfloat.EllipticF, 0, phi},
+			{"EllipticEInc", bigfloat.EllipticEInc, 0, phi},
+			{"EllipticF", bigfloat.EllipticF, 1, math.Atanh(math.Sin(phi))},
+			{"EllipticEInc", bigfloat.EllipticEInc, 1, math.Sin(phi)},
+			{"EllipticF", bigfloat.EllipticF, -1, math.Atanh(math.Sin(phi))},
+		} {
+			got, _ := test.f(new(big.Float).SetPrec(53), x, big.NewFloat(test.k)).Float64()
+			if math.Abs(got-test.want) > 1e-14*math.Abs(test.want) {
+				t.Errorf("%s(%g, %g) = %g, want %g", test.name, phi, test.k, got, test.want)
+			}
+		}
+	}
+}
+
+func TestEllipticPrecision(t *testing.T) {
+	for _, test := range []struct {
+		phi, k float64
+	}{
+		{1, 0.5},
+		{0.5, 1.9},
+		{1.5707963, 1},
+		{1e-20, 0.3},
+		{1e5, 0.99},
+		{0.5235987755982988, 2}, // just below π/6, where k sin φ = 1
+	} {
+		phi, k := big.NewFloat(test.phi), big.NewFloat(test.k)
+		wantF := bigfloat.EllipticF(new(big.Float).SetPrec(1000), phi, k)
+		wantE := bigfloat.EllipticEInc(new(big.Float).SetPrec(1000), phi, k)
+		for _, prec := range []uint{24, 53, 100, 300} {
+			got := bigfloat.EllipticF(new(big.Float).SetPrec(prec), phi, k)
+			if !closeTo(got, wantF, prec-2) {
+				t.Errorf("prec = %d: EllipticF(%g, %g) =\ngot  %g;\nwant %g", prec, test.phi, test.k, got, wantF)
+			}
+			got = bigfloat.EllipticEInc(new(big.Float).SetPrec(prec), phi, k)
+			if !closeTo(got, wantE, prec-2) {
+				t.Errorf("prec = %d: EllipticEInc(%g, %g) =\ngot  %g;\nwant %g", prec, test.phi, test.k, got, wantE)
+			}
+		}
+	}
+}
+
+func TestEllipticSpecialValues(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		f    func(o *big.Float) *big.Float
+		want float64
+	}{
+		{"EllipticK(1)", func(o *big.Float) *big.Float { return bigfloat.EllipticK(o, big.NewFloat(1)) }, math.Inf(1)},
+		{"EllipticK(-1)", func(o *big.Float) *big.Float { return bigfloat.EllipticK(o, big.NewFloat(-1)) }, math.Inf(1)},
+		{"EllipticE(1)", func(o *big.Float) *big.Float { return bigfloat.EllipticE(o, big.NewFloat(1)) }, 1},
+		{"EllipticF(0, 2)", func(o *big.Float) *big.Float { return bigfloat.EllipticF(o, big.NewFloat(0), big.NewFloat(2)) }, 0},
+		{"EllipticF(-0, 0.5)", func(o *big.Float) *big.Float {
+			return bigfloat.EllipticF(o, big.NewFloat(math.Copysign(0, -1)), big.NewFloat(0.5))
+		}, math.Copysign(0, -1)},
+		{"EllipticEInc(-0, 0.5)", func(o *big.Float) *big.Float {
+			return bigfloat.EllipticEInc(o, big.NewFloat(math.Copysign(0, -1)), big.NewFloat(0.5))
+		}, math.Copysign(0, -1)},
+		{"EllipticF(4, 1)", func(o *big.Float) *big.Float { return bigfloat.EllipticF(o, big.NewFloat(4), big.NewFloat(1)) }, math.Inf(1)},
+		{"EllipticF(-4, 1)", func(o *big.Float) *big.Float { return bigfloat.EllipticF(o, big.NewFloat(-4), big.NewFloat(1)) }, math.Inf(-1)},
+	} {
+		x64, acc := test.f(new(big.Float).SetPrec(53)).Float64()
+		if x64 != test.want || math.Signbit(x64) != math.Signbit(test.want) || acc != big.Exact {
+			t.Errorf("%s =\n got %g (%s);\nwant %g (Exact)", test.name, x64, acc, test.want)
+		}
+	}
+	for _, test := range []struct {
+		name string
+		f    func()
+	}{
+		{"EllipticK(1.5)", func() { bigfloat.EllipticK(new(big.Float), big.NewFloat(1.5)) }},
+		{"EllipticK(-Inf)", func() { bigfloat.EllipticK(new(big.Float), big.NewFloat(math.Inf(-1))) }},
+		{"EllipticE(-1.0000001)", func() { bigfloat.EllipticE(new(big.Float), big.NewFloat(-1.0000001)) }},
+		{"EllipticF(1, 2)", func() { bigfloat.EllipticF(new(big.Float), big.NewFloat(1), big.NewFloat(2)) }},
+		{"EllipticF(3.1, 2)", func() { bigfloat.EllipticF(new(big.Float), big.NewFloat(3.1), big.NewFloat(2)) }},
+		{"EllipticF(Inf, 0.5)", func() { bigfloat.EllipticF(new(big.Float), big.NewFloat(math.Inf(1)), big.NewFloat(0.5)) }},
+		{"EllipticEInc(1, 2)", func() { bigfloat.EllipticEInc(new(big.Float), big.NewFloat(1), big.NewFloat(2)) }},
+		{"EllipticEInc(1, Inf)", func() { bigfloat.EllipticEInc(new(big.Float), big.NewFloat(1), big.NewFloat(math.Inf(1))) }},
+	} {
+		expectNaN(t, test.name, test.f)
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkEllipticK(b *testing.B) {
+	k := big.NewFloat(0.75)
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.EllipticK(o, k)
+			}
+		})
+	}
+}
+
+func BenchmarkEllipticF(b *testing.B) {
+	phi, k := big.NewFloat(1), big.NewFloat(0.75)
+	for _, prec := range []uint{1e2, 1e3} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.EllipticF(o, phi, k)
+			}
+		})
+	}
+}
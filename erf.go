@@ -0,0 +1,324 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+)
+
+// Erf sets o to the error function erf(z) = 2/√π ∫₀ᶻ exp(-t²) dt to o's
+// precision and returns o. If o's precision is zero, then it is given the
+// precision of z. Erf(±0) = ±0 and Erf(±Inf) = ±1.
+func Erf(o, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	switch {
+	case z.Sign() == 0:
+		return o.Set(z)
+	case z.IsInf():
+		if z.Signbit() {
+			return o.Set(&gonem)
+		}
+		return o.Set(&gonep)
+	}
+	prec := o.Prec()
+	wp := prec + 64
+
+	x := new(big.Float).Abs(z)
+	r := new(big.Float).SetPrec(wp)
+	if erfcUnderflows(x) {
+		r.SetInt64(1)
+	} else if _, ok := erfcAsymp(r, x); ok {
+		// erfc(x) is less than 2**-wp here, so 1 - erfc(x) loses nothing.
+		r.Sub(&gonep, r)
+	} else {
+		erfSeries(r, x)
+	}
+	if z.Signbit() {
+		r.Neg(r)
+	}
+	return o.Set(r)
+}
+
+// Erfc sets o to the complementary error function erfc(z) = 1 - erf(z) to o's
+// precision and returns o. If o's precision is zero, then it is given the
+// precision of z. Erfc(+Inf) = 0 and Erfc(-Inf) = 2. Unlike computing
+// 1 - Erf(z), the result has full relative precision for large positive z,
+// including where it is far smaller than the smallest float64.
+func Erfc(o, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	switch {
+	case z.Sign() == 0:
+		return o.Set(&gonep)
+	case z.IsInf():
+		if z.Signbit() {
+			return o.Set(&gtwop)
+		}
+		return o.Set(&gzero)
+	}
+	prec := o.Prec()
+	wp := prec + 64
+
+	x := new(big.Float).Abs(z)
+	if z.Signbit() {
+		// erfc(-x) = 1 + erf(x), with no cancellation.
+		r := Erf(new(big.Float).SetPrec(wp), x)
+		return o.Add(&gonep, r)
+	}
+	if erfcUnderflows(x) {
+		return o.Set(&gzero)
+	}
+	if r, ok := erfcAsymp(new(big.Float).SetPrec(wp), x); ok {
+		return o.Set(r)
+	}
+	// Below the range of the asymptotic series, erfc(x) is no smaller than
+	// about exp(-x²), so computing 1 - erf(x) loses at most x²/log(2) bits.
+	xf, _ := x.Float64()
+	wp += uint(xf * xf / math.Ln2)
+	r := erfSeries(new(big.Float).SetPrec(wp), x)
+	return o.Sub(&gonep, r)
+}
+
+// ErfInv sets o to the inverse error function erf⁻¹(y) to o's precision and
+// returns o. If o's precision is zero, then it is given the precision of y.
+// ErfInv(±0) = ±0 and ErfInv(±1) = ±Inf. Panics with ErrNaN if |y| > 1.
+func ErfInv(o, y *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(y.Prec())
+	}
+	a := new(big.Float).Abs(y)
+	switch a.Cmp(&gonep) {
+	case 1:
+		panic(ErrNaN{msg: "ErfInv: argument is out of range"})
+	case 0:
+		return o.SetInf(y.Signbit())
+	}
+	if y.Sign() == 0 {
+		return o.Set(y)
+	}
+	var x *big.Float
+	if a.Cmp(&ghalfp) <= 0 {
+		x = erfSolve(new(big.Float).SetPrec(o.Prec()), a, false)
+	} else {
+		// Solve erfc(x) = 1 - |y| instead, so that the result stays accurate
+		// as |y| approaches 1. The subtraction is exact for 1/2 < |y| < 1.
+		x = erfSolve(new(big.Float).SetPrec(o.Prec()), a.Sub(&gonep, a), true)
+	}
+	if y.Signbit() {
+		x.Neg(x)
+	}
+	return o.Set(x)
+}
+
+// ErfcInv sets o to the inverse complementary error function erfc⁻¹(y) to o's
+// precision and returns o. If o's precision is zero, then it is given the
+// precision of y. ErfcInv(0) = +Inf, ErfcInv(1) = 0, and ErfcInv(2) = -Inf.
+// The result has full precision for y arbitrarily close to zero. Panics with
+// ErrNaN if y is less than 0 or greater than 2.
+func ErfcInv(o, y *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(y.Prec())
+	}
+	if y.Sign() < 0 || y.Cmp(&gtwop) > 0 {
+		panic(ErrNaN{msg: "ErfcInv: argument is out of range"})
+	}
+	switch {
+	case y.Sign() == 0:
+		return o.SetInf(false)
+	case y.Cmp(&gtwop) == 0:
+		return o.SetInf(true)
+	case y.Cmp(&gonep) == 0:
+		return o.SetInt64(0)
+	}
+	prec := o.Prec()
+	if y.Cmp(&ghalfp) <= 0 {
+		return o.Set(erfSolve(new(big.Float).SetPrec(prec), y, true))
+	}
+	// The subtractions below are exact for 1/2 < y < 2.
+	a := new(big.Float).SetPrec(y.Prec()).Sub(&gonep, y)
+	if a.Cmp(&ghalfm) > 0 {
+		// erfc⁻¹(y) = erf⁻¹(1 - y)
+		neg := a.Signbit()
+		x := erfSolve(new(big.Float).SetPrec(prec), a.Abs(a), false)
+		if neg {
+			x.Neg(x)
+		}
+		return o.Set(x)
+	}
+	// erfc⁻¹(y) = -erfc⁻¹(2 - y)
+	a.Sub(&gtwop, y)
+	x := erfSolve(new(big.Float).SetPrec(prec), a, true)
+	return o.Neg(x)
+}
+
+// erfcUnderflows returns whether erfc(x) < exp(-x²) is too small to represent
+// as a big.Float for x > 0.
+func erfcUnderflows(x *big.Float) bool {
+	xf, _ := x.Float64()
+	return xf > 1 && xf*xf > float64(1-big.MinExp)*math.Ln2
+}
+
+// erfSeries sets o to erf(x) to o's precision for x > 0 and returns o. It
+// uses the series
+//
+//	erf(x) = 2x/√π exp(-x²) Σ (2x²)**k / (1·3·5···(2k+1)),
+//
+// whose terms are all positive.
+func erfSeries(o, x *big.Float) *big.Float {
+	wp := o.Prec() + 32
+	// The absolute error in x² becomes relative error in exp(-x²).
+	if e := x.MantExp(nil); e > 0 {
+		wp += 2 * uint(e)
+	}
+	x2 := new(big.Float).SetPrec(wp).Mul(x, x)
+	d := quicksh(new(big.Float), x2, 1)
+	t := new(big.Float).SetPrec(wp).SetInt64(1)
+	s := new(big.Float).SetPrec(wp).SetInt64(1)
+	u := new(big.Float)
+	for k := int64(1); ; k++ {
+		t.Mul(t, d)
+		t.Quo(t, u.SetInt64(2*k+1))
+		s.Add(s, t)
+		if t.MantExp(nil) < s.MantExp(nil)-int(wp) {
+			break
+		}
+	}
+	Exp(x2, x2.Neg(x2))
+	s.Mul(s, x2)
+	s.Mul(s, x)
+	quicksh(s, s, 1)
+	return o.Quo(s, sqrtPi(wp))
+}
+
+// erfcAsymp sets o to erfc(x) to o's precision for x > 0 using the asymptotic
+// series
+//
+//	erfc(x) ~ exp(-x²)/(x√π) Σ (-1)**k 1·3·5···(2k-1) / (2x²)**k.
+//
+// It returns o and true if x is large enough for the series to reach o's
+// precision, and otherwise o unchanged and false.
+func erfcAsymp(o, x *big.Float) (*big.Float, bool) {
+	wp := o.Prec() + 32
+	if e := x.MantExp(nil); e > 0 {
+		wp += 2 * uint(e)
+	}
+	// The smallest term is about √2 exp(-x²), which must be below 2**-wp.
+	if xf, _ := x.Float64(); xf*xf < float64(wp+4)*math.Ln2 {
+		return o, false
+	}
+	x2 := new(big.Float).SetPrec(wp).Mul(x, x)
+	r := quicksh(new(big.Float), x2, 1)
+	r.Quo(&gonep, r)
+	t := new(big.Float).SetPrec(wp).SetInt64(1)
+	s := new(big.Float).SetPrec(wp).SetInt64(1)
+	u := new(big.Float)
+	for k := int64(1); ; k++ {
+		t.Mul(t, r)
+		t.Mul(t, u.SetInt64(1-2*k))
+		s.Add(s, t)
+		if t.MantExp(nil) < s.MantExp(nil)-int(wp) {
+			break
+		}
+	}
+	Exp(x2, x2.Neg(x2))
+	s.Mul(s, x2)
+	s.Quo(s, x)
+	return o.Quo(s, sqrtPi(wp)), true
+}
+
+// sqrtPi returns √π to precision prec.
+func sqrtPi(prec uint) *big.Float {
+	p := new(big.Float).SetPrec(prec).Set(cachedPi(prec))
+	return p.Sqrt(p)
+}
+
+// erfSolve sets o to the x ≥ 0 for which erf(x) = y, or erfc(x) = y if comp
+// is true, and returns o. y must be in (0, 1/2].
+func erfSolve(o, y *big.Float, comp bool) *big.Float {
+	prec := o.Prec()
+	wp := prec + 64
+	x := new(big.Float).SetPrec(64)
+	// Get a starting point with float64 math if possible. The result is good
+	// to at least 32 bits in every case.
+	yf, _ := y.Float64()
+	switch {
+	case !comp && y.MantExp(nil) < -60:
+		// erf(x) ≈ 2x/√π
+		x.Mul(y, sqrtPi(64))
+		quicksh(x, x, -1)
+	case !comp:
+		x.SetFloat64(math.Erfinv(yf))
+	default:
+		x.SetFloat64(erfcInvGuess(y))
+	}
+
+	// Newton's method on f(x) = erf(x) - y or erfc(x) - y, doubling the
+	// precision at each step until reaching the working precision:
+	//    x' = x ∓ f(x) √π/2 exp(x²)
+	p := uint(32)
+	d := new(big.Float)
+	t := new(big.Float)
+	for i := 0; i < 100; i++ {
+		if p < wp {
+			p *= 2
+			if p > wp {
+				p = wp
+			}
+		}
+		x.SetPrec(p)
+		d.SetPrec(p)
+		t.SetPrec(p)
+		if comp {
+			Erfc(d, x)
+		} else {
+			Erf(d, x)
+		}
+		d.Sub(d, y)
+		t.Mul(x, x)
+		Exp(t, t.Neg(t))
+		d.Quo(d, t)
+		d.Mul(d, sqrtPi(p))
+		quicksh(d, d, -1)
+		if comp {
+			x.Add(x, d)
+		} else {
+			x.Sub(x, d)
+		}
+		if p == wp && (d.Sign() == 0 || d.MantExp(nil) < x.MantExp(nil)-int(prec)-8) {
+			break
+		}
+	}
+	return o.Set(x)
+}
+
+// erfcInvGuess returns an approximation of erfc⁻¹(y) good to at least 32 bits
+// for 0 < y ≤ 1/2.
+func erfcInvGuess(y *big.Float) float64 {
+	yf, _ := y.Float64()
+	if yf >= 0.01 {
+		// math.Erfcinv computes erf⁻¹(1 - y), which is accurate enough here.
+		return math.Erfcinv(yf)
+	}
+	// Solve exp(-x²)/(x√π) S(x) = y by fixed-point iteration, where
+	//    S(x) = 1 - 1/(2x²) + 3/(4x⁴) - 15/(8x⁶)
+	// are the leading terms of the asymptotic series for erfc. -log(y) is
+	// finite even where y is not representable as a float64.
+	l := Log(new(big.Float).SetPrec(64), y)
+	lf, _ := l.Float64()
+	lf = -lf
+	xf := math.Sqrt(lf)
+	for i := 0; i < 6; i++ {
+		r := 1 / (2 * xf * xf)
+		s := 1 - r + 3*r*r - 15*r*r*r
+		xf = math.Sqrt(lf - math.Log(xf*math.Sqrt(math.Pi)) + math.Log(s))
+	}
+	if yf > 1e-280 {
+		// Refine with Newton's method where exp(x²) doesn't overflow.
+		for i := 0; i < 4; i++ {
+			xf += (math.Erfc(xf) - yf) * math.Sqrt(math.Pi) / 2 * math.Exp(xf*xf)
+		}
+	}
+	return xf
+}
@@ -0,0 +1,191 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestErfValues(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		f    func(o, z *big.Float) *big.Float
+		z    float64
+		want string
+		prec uint
+	}{
+		{"Erf", bigfloat.Erf, 1, "0.8427007929497148693412206350826092592960669979663029084599378978347172540960108412619833253481448885", 320},
+		{"Erf", bigfloat.Erf, -1, "-0.8427007929497148693412206350826092592960669979663029084599378978347172540960108412619833253481448885", 320},
+		{"Erfc", bigfloat.Erfc, 1, "0.1572992070502851306587793649173907407039330020336970915400621021652827459039891587380166746518551115", 320},
+		{"Erfc", bigfloat.Erfc, 30, "2.564656203756111600033397277501447146548e-393", 128},
+		{"ErfInv", bigfloat.ErfInv, 0.5, "0.4769362762044698733814183536431305598089", 128},
+		{"ErfcInv", bigfloat.ErfcInv, 1.5, "-0.4769362762044698733814183536431305598089", 128},
+	} {
+		want := parse(test.want, test.prec+64)
+		got := test.f(new(big.Float).SetPrec(test.prec), big.NewFloat(test.z))
+		if !closeTo(got, want, test.prec-2) {
+			t.Errorf("%s(%g) =\ngot  %g;\nwant %g", test.name, test.z, got, want)
+		}
+	}
+}
+
+func TestErfFloat64(t *testing.T) {
+	for _, scale := range []float64{1e-3, 1, 4, 30} {
+		for i := 0; i < 500; i++ {
+			x := (2*rand.Float64() - 1) * scale
+			// math.Erf can be off by a couple of ulps, so check that
+			// separately from the rounding of the result.
+			e, _ := bigfloat.Erf(new(big.Float).SetPrec(53), big.NewFloat(x)).Float64()
+			if want := math.Erf(x); math.Abs(e-want) > 1e-15*math.Abs(want) {
+				t.Errorf("Erf(%g) = %g, want %g", x, e, want)
+			}
+			if want, _ := bigfloat.Erf(new(big.Float).SetPrec(200), big.NewFloat(x)).Float64(); e != want {
+				t.Errorf("Erf(%g) = %g, want correctly rounded %g", x, e, want)
+			}
+			c, _ := bigfloat.Erfc(new(big.Float).SetPrec(53), big.NewFloat(x)).Float64()
+			if want := math.Erfc(x); math.Abs(c-want) > 1e-15*math.Abs(want) && want > 1e-300 {
+				t.Errorf("Erfc(%g) = %g, want %g", x, c, want)
+			}
+		}
+	}
+}
+
+func TestErfcTail(t *testing.T) {
+	// The series and asymptotic expansion switch over at a point that
+	// depends on the precision, so comparing different precisions checks
+	// each against the other.
+	for _, x := range []float64{2, 5.5, 10, 13.25, 20, 27, 100, 1e4} {
+		z := big.NewFloat(x)
+		want := bigfloat.Erfc(new(big.Float).SetPrec(1000), z)
+		for _, prec := range []uint{24, 53, 100, 200, 400} {
+			got := bigfloat.Erfc(new(big.Float).SetPrec(prec), z)
+			if !closeTo(got, want, prec-2) {
+				t.Errorf("prec = %d: Erfc(%g) =\ngot  %g;\nwant %g", prec, x, got, want)
+			}
+		}
+		// erf(x) + erfc(x) = 1
+		e := bigfloat.Erf(new(big.Float).SetPrec(1000), z)
+		e.Add(e, want)
+		if !closeTo(e, big.NewFloat(1), 990) {
+			t.Errorf("Erf(%g) + Erfc(%g) = %g, want 1", x, x, e)
+		}
+	}
+}
+
+func TestErfInvRoundTrip(t *testing.T) {
+	const prec = 200
+	tiny := new(big.Float).SetMantExp(big.NewFloat(1), -100000)
+	nearOne := new(big.Float).SetPrec(prec).SetMantExp(big.NewFloat(1), -150)
+	nearOne.Sub(big.NewFloat(1), nearOne)
+	for _, y := range []*big.Float{
+		big.NewFloat(1e-30),
+		big.NewFloat(0.125),
+		big.NewFloat(0.5),
+		big.NewFloat(-0.75),
+		big.NewFloat(0.999999),
+		nearOne,
+		tiny,
+	} {
+		x := bigfloat.ErfInv(new(big.Float).SetPrec(prec), y)
+		got := bigfloat.Erf(new(big.Float).SetPrec(prec), x)
+		// An error of one ulp in x moves erf(x) by about its derivative.
+		if !closeTo(got, y, prec-16) {
+			t.Errorf("Erf(ErfInv(%g)) = %g", y, got)
+		}
+	}
+	for _, y := range []*big.Float{
+		tiny,
+		big.NewFloat(1e-300),
+		big.NewFloat(0.001),
+		big.NewFloat(0.5),
+		big.NewFloat(0.875),
+		big.NewFloat(1.25),
+		big.NewFloat(1.75),
+		big.NewFloat(1.9999),
+	} {
+		x := bigfloat.ErfcInv(new(big.Float).SetPrec(prec), y)
+		got := bigfloat.Erfc(new(big.Float).SetPrec(prec), x)
+		// Near zero, erfc(x) is sensitive to x in proportion to x².
+		if !closeTo(got, y, prec-40) {
+			t.Errorf("Erfc(ErfcInv(%g)) = %g", y, got)
+		}
+	}
+}
+
+func TestErfSpecialValues(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		f    func(o, z *big.Float) *big.Float
+		z    float64
+		want float64
+	}{
+		{"Erf", bigfloat.Erf, 0, 0},
+		{"Erf", bigfloat.Erf, math.Copysign(0, -1), math.Copysign(0, -1)},
+		{"Erf", bigfloat.Erf, math.Inf(1), 1},
+		{"Erf", bigfloat.Erf, math.Inf(-1), -1},
+		{"Erfc", bigfloat.Erfc, 0, 1},
+		{"Erfc", bigfloat.Erfc, math.Inf(1), 0},
+		{"Erfc", bigfloat.Erfc, math.Inf(-1), 2},
+		{"Erfc", bigfloat.Erfc, 1e10, 0},
+		{"ErfInv", bigfloat.ErfInv, 0, 0},
+		{"ErfInv", bigfloat.ErfInv, math.Copysign(0, -1), math.Copysign(0, -1)},
+		{"ErfInv", bigfloat.ErfInv, 1, math.Inf(1)},
+		{"ErfInv", bigfloat.ErfInv, -1, math.Inf(-1)},
+		{"ErfcInv", bigfloat.ErfcInv, 0, math.Inf(1)},
+		{"ErfcInv", bigfloat.ErfcInv, 1, 0},
+		{"ErfcInv", bigfloat.ErfcInv, 2, math.Inf(-1)},
+	} {
+		x64, acc := test.f(new(big.Float), big.NewFloat(test.z)).Float64()
+		if x64 != test.want || math.Signbit(x64) != math.Signbit(test.want) || acc != big.Exact {
+			t.Errorf("%s(%g) =\n got %g (%s);\nwant %g (Exact)", test.name, test.z, x64, acc, test.want)
+		}
+	}
+	for _, test := range []struct {
+		name string
+		f    func(o, z *big.Float) *big.Float
+		z    float64
+	}{
+		{"ErfInv", bigfloat.ErfInv, 1.5},
+		{"ErfInv", bigfloat.ErfInv, -1.0000001},
+		{"ErfInv", bigfloat.ErfInv, math.Inf(1)},
+		{"ErfcInv", bigfloat.ErfcInv, -0.5},
+		{"ErfcInv", bigfloat.ErfcInv, 2.5},
+		{"ErfcInv", bigfloat.ErfcInv, math.Inf(-1)},
+	} {
+		expectNaN(t, fmt.Sprintf("%s(%g)", test.name, test.z), func() {
+			test.f(new(big.Float), big.NewFloat(test.z))
+		})
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkErf(b *testing.B) {
+	z := big.NewFloat(1.5)
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Erf(o, z)
+			}
+		})
+	}
+}
+
+func BenchmarkErfInv(b *testing.B) {
+	z := big.NewFloat(0.75)
+	for _, prec := range []uint{1e2, 1e3} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.ErfInv(o, z)
+			}
+		})
+	}
+}
@@ -38,7 +38,7 @@ func Exp(o, z *big.Float) *big.Float {
 		halfZ := quicksh(new(big.Float), z, -1).SetPrec(p.Prec() + 64)
 		// TODO: avoid recursion
 		halfExp := Exp(halfZ, halfZ)
-		return p.Mul(halfExp, halfExp)
+		return o.Set(p.Mul(halfExp, halfExp))
 	}
 	// we got a nice IEEE-754 estimate
 	guess := big.NewFloat(zf)
@@ -112,6 +112,17 @@ func TestExpSpecialValues(t *testing.T) {
 	}
 }
 
+func TestExpAliased(t *testing.T) {
+	// Arguments outside the range of float64 exp take a different path.
+	for _, f := range []float64{-1000, -1, 1, 1000} {
+		z := new(big.Float).SetPrec(100).SetFloat64(f)
+		want := bigfloat.Exp(new(big.Float), z)
+		if got := bigfloat.Exp(z, z); got != z || z.Cmp(want) != 0 {
+			t.Errorf("Exp(z, z) with z = %g: got %g, want %g", f, z, want)
+		}
+	}
+}
+
 // ---------- Benchmarks ----------
 
 func BenchmarkExp(b *testing.B) {
@@ -0,0 +1,312 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+)
+
+// Ei sets o to the exponential integral
+//
+//	Ei(x) = -∫ exp(-t)/t dt, t ≥ -x,
+//
+// taking the Cauchy principal value for x > 0, to o's precision and returns o.
+// If o's precision is zero, then it is given the precision of x. Ei(±0) = -Inf,
+// Ei(+Inf) = +Inf, and Ei(-Inf) = -0. For x < 0, Ei(x) = -E1(-x).
+func Ei(o, x *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(x.Prec())
+	}
+	switch {
+	case x.Sign() == 0:
+		return o.SetInf(true)
+	case x.IsInf():
+		if x.Signbit() {
+			return o.Neg(&gzero)
+		}
+		return o.Set(x)
+	}
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		return ei(x, wp)
+	})
+	return o.Set(r)
+}
+
+// E1 sets o to the exponential integral
+//
+//	E₁(x) = ∫ exp(-t)/t dt, t ≥ x,
+//
+// to o's precision and returns o. If o's precision is zero, then it is given
+// the precision of x. E1(±0) = +Inf and E1(+Inf) = 0. Panics with ErrNaN if x
+// is negative, where E₁ has a branch cut; the real part of E₁ there is
+// -Ei(-x).
+func E1(o, x *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(x.Prec())
+	}
+	switch {
+	case x.Sign() == 0:
+		return o.SetInf(false)
+	case x.Signbit():
+		panic(ErrNaN{msg: "E1: argument is negative"})
+	case x.IsInf():
+		return o.SetInt64(0)
+	}
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		return e1Pos(x, wp)
+	})
+	return o.Set(r)
+}
+
+// Li sets o to the logarithmic integral
+//
+//	li(x) = ∫ 1/log(t) dt, 0 ≤ t ≤ x,
+//
+// taking the Cauchy principal value for x > 1, to o's precision and returns
+// o. If o's precision is zero, then it is given the precision of x. This is
+// li(x) = Ei(log x), not the offset integral Li(x) = li(x) - li(2). Li(±0) = 0,
+// Li(1) = -Inf, and Li(+Inf) = +Inf. Panics with ErrNaN if x is negative.
+func Li(o, x *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(x.Prec())
+	}
+	switch {
+	case x.Sign() == 0:
+		return o.SetInt64(0)
+	case x.Signbit():
+		panic(ErrNaN{msg: "Li: argument is negative"})
+	case x.IsInf():
+		return o.Set(x)
+	case x.Cmp(&gonep) == 0:
+		return o.SetInf(true)
+	}
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		u := Log(new(big.Float).SetPrec(wp), x)
+		r, mag := ei(u, wp)
+		// The rounding error in log x, relative to log x, becomes absolute
+		// error of about x 2**-wp in li(x).
+		if e := x.MantExp(nil); e > mag {
+			mag = e
+		}
+		return r, mag
+	})
+	return o.Set(r)
+}
+
+// Si sets o to the sine integral
+//
+//	Si(x) = ∫ sin(t)/t dt, 0 ≤ t ≤ x,
+//
+// to o's precision and returns o. If o's precision is zero, then it is given
+// the precision of x. Si is odd, with Si(±0) = ±0 and Si(±Inf) = ±π/2.
+func Si(o, x *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(x.Prec())
+	}
+	if x.Sign() == 0 {
+		return o.Set(x)
+	}
+	if x.IsInf() {
+		r := new(big.Float).SetPrec(o.Prec() + 64).Set(cachedPi(o.Prec() + 64))
+		quicksh(r, r, -1)
+		if x.Signbit() {
+			r.Neg(r)
+		}
+		return o.Set(r)
+	}
+	a := new(big.Float).Abs(x)
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		return siCiPos(a, wp, false)
+	})
+	if x.Signbit() {
+		r.Neg(r)
+	}
+	return o.Set(r)
+}
+
+// Ci sets o to the cosine integral
+//
+//	Ci(x) = -∫ cos(t)/t dt, t ≥ x,
+//
+// to o's precision and returns o. If o's precision is zero, then it is given
+// the precision of x. Ci(±0) = -Inf and Ci(+Inf) = 0. Panics with ErrNaN if x
+// is negative, where Ci has a branch cut; the real part of Ci there is Ci(-x).
+func Ci(o, x *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(x.Prec())
+	}
+	switch {
+	case x.Sign() == 0:
+		return o.SetInf(true)
+	case x.Signbit():
+		panic(ErrNaN{msg: "Ci: argument is negative"})
+	case x.IsInf():
+		return o.SetInt64(0)
+	}
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		return siCiPos(x, wp, true)
+	})
+	return o.Set(r)
+}
+
+// ei returns Ei(x) to precision wp for finite nonzero x, along with the binary
+// exponent of the largest quantity in the computation for ziv.
+func ei(x *big.Float, wp uint) (*big.Float, int) {
+	if x.Signbit() {
+		r, mag := e1Pos(new(big.Float).Neg(x), wp)
+		return r.Neg(r), mag
+	}
+	if xf, _ := x.Float64(); xf*math.Log2E > float64(wp) {
+		// Ei(x) ~ exp(x)/x Σ k!/x**k
+		v := new(big.Float)
+		t := new(big.Float).SetPrec(wp).SetInt64(1)
+		s, ok := sumAsymp(t, wp, func(t *big.Float, k int64) {
+			t.Mul(t, v.SetInt64(k))
+			t.Quo(t, x)
+		})
+		if ok {
+			s.Mul(s, expIntExp(x, wp))
+			s.Quo(s, x)
+			return s, s.MantExp(nil)
+		}
+	}
+
+	// Ei(x) = γ + log x + Σ x**k/(k k!), k ≥ 1. The terms are all positive.
+	v := new(big.Float)
+	t := new(big.Float).SetPrec(wp).Set(x)
+	s, mag := sumSeries(t, wp, func(t *big.Float, k int64) {
+		// t *= x k/(k+1)²
+		t.Mul(t, x)
+		t.Mul(t, v.SetInt64(k))
+		t.Quo(t, v.SetInt64(k+1))
+		t.Quo(t, v)
+	})
+	return expIntLog(s, x, wp, mag)
+}
+
+// e1Pos returns E₁(x) to precision wp for finite x > 0, along with the binary
+// exponent of the largest quantity in the computation for ziv.
+func e1Pos(x *big.Float, wp uint) (*big.Float, int) {
+	if xf, _ := x.Float64(); xf > float64(wp)*math.Ln2/4 {
+		// E₁(x) = Γ(0, x). The continued fraction takes about
+		// (wp log 2)²/16x terms, while the series below loses about 2x/log 2
+		// bits to cancellation, so switch between them where both are about
+		// wp/4.
+		e := expIntExp(new(big.Float).Neg(x), wp)
+		if e.Sign() == 0 {
+			return e, math.MinInt32
+		}
+		r := gammaIncCF(&gzero, x, wp)
+		r.Mul(r, e)
+		return r, r.MantExp(nil)
+	}
+
+	// E₁(x) = -γ - log x + Σ (-1)**(k+1) x**k/(k k!), k ≥ 1.
+	v := new(big.Float)
+	t := new(big.Float).SetPrec(wp).Set(x)
+	s, mag := sumSeries(t, wp, func(t *big.Float, k int64) {
+		// t *= -x k/(k+1)²
+		t.Mul(t, x)
+		t.Mul(t, v.SetInt64(-k))
+		t.Quo(t, v.SetInt64(k+1))
+		t.Quo(t, v)
+	})
+	s.Neg(s)
+	r, mag := expIntLog(s, x, wp, mag)
+	return r.Neg(r), mag
+}
+
+// siCiPos returns Si(x), or Ci(x) if ci is true, to precision wp for finite
+// x > 0, along with the binary exponent of the largest quantity in the
+// computation for ziv.
+func siCiPos(x *big.Float, wp uint, ci bool) (*big.Float, int) {
+	x2 := new(big.Float).SetPrec(wp).Mul(x, x)
+	v := new(big.Float)
+	if xf, _ := x.Float64(); xf*math.Log2E > float64(wp) {
+		// Si(x) = π/2 - f(x) cos x - g(x) sin x and
+		// Ci(x) = f(x) sin x - g(x) cos x, where
+		//	f(x) ~ 1/x Σ (-1)**k (2k)!/x**2k,
+		//	g(x) ~ 1/x² Σ (-1)**k (2k+1)!/x**2k.
+		f, ok := sumAsymp(new(big.Float).SetPrec(wp).SetInt64(1), wp, func(t *big.Float, k int64) {
+			t.Mul(t, v.SetInt64(1-2*k))
+			t.Mul(t, v.SetInt64(2*k))
+			t.Quo(t, x2)
+		})
+		var g *big.Float
+		if ok {
+			g, ok = sumAsymp(new(big.Float).SetPrec(wp).SetInt64(1), wp, func(t *big.Float, k int64) {
+				t.Mul(t, v.SetInt64(-2*k))
+				t.Mul(t, v.SetInt64(2*k+1))
+				t.Quo(t, x2)
+			})
+		}
+		if ok {
+			f.Quo(f, x)
+			g.Quo(g, x2)
+			s, c := sinCos(x, wp)
+			if ci {
+				s.Mul(s, f)
+				c.Mul(c, g)
+				return s.Sub(s, c), f.MantExp(nil)
+			}
+			c.Mul(c, f)
+			s.Mul(s, g)
+			r := new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+			quicksh(r, r, -1)
+			r.Sub(r, c)
+			return r.Sub(r, s), 1
+		}
+	}
+
+	if !ci {
+		// Si(x) = Σ (-1)**k x**(2k+1)/((2k+1) (2k+1)!), k ≥ 0
+		return sumSeries(new(big.Float).SetPrec(wp).Set(x), wp, func(t *big.Float, k int64) {
+			// t *= -x² (2k-1)/(2k (2k+1)²)
+			t.Mul(t, x2)
+			t.Mul(t, v.SetInt64(1-2*k))
+			t.Quo(t, v.SetInt64(2*k))
+			t.Quo(t, v.SetInt64(2*k+1))
+			t.Quo(t, v)
+		})
+	}
+	// Ci(x) = γ + log x + Σ (-1)**k x**2k/(2k (2k)!), k ≥ 1
+	t := quicksh(new(big.Float), x2, -2).SetPrec(wp)
+	t.Neg(t)
+	s, mag := sumSeries(t, wp, func(t *big.Float, k int64) {
+		// t *= -x² k/(2(k+1)² (2k+1))
+		t.Mul(t, x2)
+		t.Mul(t, v.SetInt64(-k))
+		t.Quo(t, v.SetInt64(k+1))
+		t.Quo(t, v)
+		t.Quo(t, v.SetInt64(2*k+1))
+		quicksh(t, t, -1)
+	})
+	return expIntLog(s, x, wp, mag)
+}
+
+// expIntLog returns s + γ + log x to precision wp, along with the greater of
+// mag and the binary exponents of the terms.
+func expIntLog(s, x *big.Float, wp uint, mag int) (*big.Float, int) {
+	l := Log(new(big.Float).SetPrec(wp), x)
+	if e := l.MantExp(nil); e > mag {
+		mag = e
+	}
+	// γ = -ψ(1), which is less than 1.
+	g := Digamma(new(big.Float).SetPrec(wp), &gonep)
+	if mag < 0 {
+		mag = 0
+	}
+	s.Add(s, l)
+	return s.Sub(s, g), mag
+}
+
+// expIntExp returns exp(x) to precision wp for finite x, with enough extra
+// working precision that the result has full relative precision even when x
+// has more than wp bits.
+func expIntExp(x *big.Float, wp uint) *big.Float {
+	p := wp
+	if e := x.MantExp(nil); e > 0 {
+		p += uint(e)
+	}
+	y := new(big.Float).SetPrec(p).Set(x)
+	return Exp(y, y).SetPrec(wp)
+}
@@ -0,0 +1,192 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestExpIntValues(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		f    func(o, x *big.Float) *big.Float
+		x    float64
+		want string
+	}{
+		{"Ei", bigfloat.Ei, 1, "1.89511781635593675546652093433163426901706058173270759164623"},
+		{"Ei", bigfloat.Ei, -1, "-0.219383934395520273677163775460121649031047293406908207577979"},
+		{"E1", bigfloat.E1, 1, "0.219383934395520273677163775460121649031047293406908207577979"},
+		{"Li", bigfloat.Li, 2, "1.04516378011749278484458888919461313652261557815120157583291"},
+		{"Si", bigfloat.Si, 1, "0.946083070367183014941353313823179657812337954738111790471455"},
+		{"Si", bigfloat.Si, -1, "-0.946083070367183014941353313823179657812337954738111790471455"},
+		{"Ci", bigfloat.Ci, 1, "0.337403922900968134662646203889150769997578032585731894801319"},
+	} {
+		want := parse(test.want, 256)
+		got := test.f(new(big.Float).SetPrec(190), big.NewFloat(test.x))
+		if !closeTo(got, want, 188) {
+			t.Errorf("%s(%g) =\ngot  %g;\nwant %g", test.name, test.x, got, want)
+		}
+	}
+}
+
+func TestExpIntZeros(t *testing.T) {
+	// Ei and li have simple zeros at x₀ and at Soldner's constant μ = exp(x₀).
+	// Near them, the results must still have full relative precision.
+	for _, test := range []struct {
+		name string
+		f    func(o, x *big.Float) *big.Float
+		x    string
+	}{
+		{"Ei", bigfloat.Ei, "0.372507410781366634461991866580119133535689497771654"},
+		{"Li", bigfloat.Li, "1.451369234883381050283968485892027449493032283646329"},
+	} {
+		x := parse(test.x, 170)
+		want := test.f(new(big.Float).SetPrec(500), x)
+		got := test.f(new(big.Float).SetPrec(100), x)
+		if want.MantExp(nil) > -150 {
+			t.Errorf("%s(%s) = %g is not near zero", test.name, test.x, want)
+		}
+		if !closeTo(got, want, 98) {
+			t.Errorf("%s(%s):\ngot  %g\nwant %g", test.name, test.x, got, want)
+		}
+	}
+}
+
+func TestExpIntIdentities(t *testing.T) {
+	for _, prec := range []uint{53, 200, 1000} {
+		for _, xf := range []float64{1e-30, 0.01, 0.5, 3, 20, 150, 800, 1e5} {
+			x := new(big.Float).SetPrec(prec).SetFloat64(xf)
+			// Ei(-x) = -E₁(x)
+			a := bigfloat.Ei(new(big.Float).SetPrec(prec), new(big.Float).Neg(x))
+			b := bigfloat.E1(new(big.Float).SetPrec(prec+64), x)
+			if !closeTo(a.Neg(a), b, prec-2) {
+				t.Errorf("-Ei(-%g) at prec %d:\ngot  %g\nwant %g", xf, prec, a, b)
+			}
+			if xf < 1e-3 {
+				// exp(x) rounds away too much of x to recover it.
+				continue
+			}
+			// li(exp(x)) = Ei(x)
+			e := bigfloat.Exp(new(big.Float).SetPrec(prec+64), new(big.Float).SetPrec(prec+64).Set(x))
+			a = bigfloat.Li(new(big.Float).SetPrec(prec), e)
+			b = bigfloat.Ei(new(big.Float).SetPrec(prec+64), x)
+			if !closeTo(a, b, prec-4) {
+				t.Errorf("li(exp(%g)) at prec %d:\ngot  %g\nwant %g", xf, prec, a, b)
+			}
+		}
+	}
+}
+
+func TestExpIntPrecision(t *testing.T) {
+	// The series, continued fraction, and asymptotic expansions switch over
+	// at points that depend on the precision, so comparing different
+	// precisions checks each against the others.
+	for _, f := range []struct {
+		name string
+		f    func(o, x *big.Float) *big.Float
+	}{
+		{"Ei", bigfloat.Ei},
+		{"E1", bigfloat.E1},
+		{"Li", bigfloat.Li},
+		{"Si", bigfloat.Si},
+		{"Ci", bigfloat.Ci},
+	} {
+		for _, xf := range []float64{1e-20, 0.25, 2, 10, 40, 100, 300, 1000, 1e5} {
+			x := big.NewFloat(xf)
+			want := f.f(new(big.Float).SetPrec(800), x)
+			for _, prec := range []uint{53, 200} {
+				got := f.f(new(big.Float).SetPrec(prec), x)
+				if !closeTo(got, want, prec-2) {
+					t.Errorf("%s(%g) at prec %d:\ngot  %g\nwant %g", f.name, xf, prec, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestExpIntSpecialValues(t *testing.T) {
+	inf, negz := math.Inf(1), math.Copysign(0, -1)
+	for _, test := range []struct {
+		name string
+		f    func(o, x *big.Float) *big.Float
+		x    float64
+		want float64
+	}{
+		{"Ei", bigfloat.Ei, 0, -inf},
+		{"Ei", bigfloat.Ei, negz, -inf},
+		{"Ei", bigfloat.Ei, inf, inf},
+		{"Ei", bigfloat.Ei, -inf, negz},
+		{"E1", bigfloat.E1, 0, inf},
+		{"E1", bigfloat.E1, negz, inf},
+		{"E1", bigfloat.E1, inf, 0},
+		{"Li", bigfloat.Li, 0, 0},
+		{"Li", bigfloat.Li, 1, -inf},
+		{"Li", bigfloat.Li, inf, inf},
+		{"Si", bigfloat.Si, 0, 0},
+		{"Si", bigfloat.Si, negz, negz},
+		{"Ci", bigfloat.Ci, 0, -inf},
+		{"Ci", bigfloat.Ci, inf, 0},
+	} {
+		x64, acc := test.f(new(big.Float), big.NewFloat(test.x)).Float64()
+		if x64 != test.want || math.Signbit(x64) != math.Signbit(test.want) || acc != big.Exact {
+			t.Errorf("%s(%g) = %g (%s), want %g (Exact)", test.name, test.x, x64, acc, test.want)
+		}
+	}
+	for _, x := range []float64{inf, -inf} {
+		got := bigfloat.Si(new(big.Float).SetPrec(100), big.NewFloat(x))
+		want := bigfloat.Pi(new(big.Float).SetPrec(164))
+		want.Quo(want, big.NewFloat(math.Copysign(2, x)))
+		if !closeTo(got, want, 98) {
+			t.Errorf("Si(%g) = %g, want %g", x, got, want)
+		}
+	}
+	// Ei(-x) underflows for large x.
+	if got := bigfloat.Ei(new(big.Float).SetPrec(53), big.NewFloat(-1e10)); got.Sign() != 0 || !got.Signbit() {
+		t.Errorf("Ei(-1e10) = %g, want -0", got)
+	}
+	for _, test := range []struct {
+		name string
+		f    func(o, x *big.Float) *big.Float
+	}{
+		{"E1", bigfloat.E1},
+		{"Li", bigfloat.Li},
+		{"Ci", bigfloat.Ci},
+	} {
+		for _, x := range []float64{-1, -inf} {
+			expectNaN(t, fmt.Sprintf("%s(%g)", test.name, x), func() {
+				test.f(new(big.Float), big.NewFloat(x))
+			})
+		}
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkEi(b *testing.B) {
+	x := big.NewFloat(2.5)
+	for _, prec := range []uint{1e2, 1e3} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Ei(o, x)
+			}
+		})
+	}
+}
+
+func BenchmarkCi(b *testing.B) {
+	x := big.NewFloat(2.5)
+	for _, prec := range []uint{1e2, 1e3} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Ci(o, x)
+			}
+		})
+	}
+}
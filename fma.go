@@ -0,0 +1,108 @@
+package bigfloat
+
+import "math/big"
+
+// Fma sets o to x·y + z, computed exactly and rounded once to o's precision
+// according to o's rounding mode, and returns o. If o's precision is zero,
+// then it is given the greatest of the precisions of x, y, and z. Panics with
+// ErrNaN if x·y is 0·Inf or if x·y and z are infinities with opposite signs.
+func Fma(o, x, y, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(x, y, z))
+	}
+	if x.IsInf() && y.Sign() == 0 || x.Sign() == 0 && y.IsInf() {
+		panic(ErrNaN{msg: "Fma: zero times infinity"})
+	}
+	p := fmaMul(x, y)
+	if p.IsInf() && z.IsInf() && p.Signbit() != z.Signbit() {
+		panic(ErrNaN{msg: "Fma: sum of opposite infinities"})
+	}
+	return o.Add(p, z)
+}
+
+// TwoSum sets s to x+y rounded to s's precision according to s's rounding
+// mode and e to the error x+y-s rounded to e's precision, and returns s and
+// e. If s's precision is zero, then it is given the greater of the precisions
+// of x and y, and if e's precision is zero, then it is given s's precision.
+// When s rounds to nearest and x and y have no more precision than either of s
+// and e, the error is exactly representable, so that s + e = x + y exactly.
+// That is not so if s is less precise than x or y: with s at 1 bit, the error
+// of 1.5 + 2**-100 needs 99 bits. If s is infinite, then e is set to zero.
+// Panics with ErrNaN if x and y are infinities with opposite signs.
+//
+// s and e must be distinct, but either may be the same as x or y.
+func TwoSum(s, e, x, y *big.Float) (*big.Float, *big.Float) {
+	if s.Prec() == 0 {
+		s.SetPrec(greatestPrec(x, y))
+	}
+	if e.Prec() == 0 {
+		e.SetPrec(s.Prec())
+	}
+	if x.IsInf() && y.IsInf() && x.Signbit() != y.Signbit() {
+		panic(ErrNaN{msg: "TwoSum: sum of opposite infinities"})
+	}
+	// Order the operands by magnitude, so that x - s is small enough to be
+	// computed exactly at a modest precision.
+	if cmpAbs(x, y) < 0 {
+		x, y = y, x
+	}
+	x, y = new(big.Float).Copy(x), new(big.Float).Copy(y)
+	s.Add(x, y)
+	if s.IsInf() {
+		e.SetInt64(0)
+		return s, e
+	}
+	// x - s is exact with enough bits to span from the top of x, which
+	// has at least the magnitude of s/2, to the bottom of x or s.
+	d := new(big.Float)
+	if x.Sign() != 0 {
+		lo := lowBit(x)
+		if s.Sign() != 0 && lowBit(s) < lo {
+			lo = lowBit(s)
+		}
+		d.SetPrec(uint(x.MantExp(nil)-lo) + 2)
+	}
+	d.Sub(x, s)
+	e.Add(d, y)
+	return s, e
+}
+
+// TwoProduct sets p to x·y rounded to p's precision according to p's rounding
+// mode and e to the error x·y-p rounded to e's precision, and returns p and
+// e. If p's precision is zero, then it is given the greater of the
+// precisions of x and y, and if e's precision is zero, then it is given p's
+// precision. When x and y have no more precision than e, and p has at least
+// e's precision, the error is exactly representable, so that p + e = x·y
+// exactly. If p is infinite, then e is set to zero. Panics with ErrNaN if x·y
+// is 0·Inf.
+//
+// p and e must be distinct, but either may be the same as x or y.
+func TwoProduct(p, e, x, y *big.Float) (*big.Float, *big.Float) {
+	if p.Prec() == 0 {
+		p.SetPrec(greatestPrec(x, y))
+	}
+	if e.Prec() == 0 {
+		e.SetPrec(p.Prec())
+	}
+	if x.IsInf() && y.Sign() == 0 || x.Sign() == 0 && y.IsInf() {
+		panic(ErrNaN{msg: "TwoProduct: zero times infinity"})
+	}
+	t := fmaMul(x, y)
+	p.Set(t)
+	if p.IsInf() {
+		e.SetInt64(0)
+		return p, e
+	}
+	e.Sub(t, p)
+	return p, e
+}
+
+// fmaMul returns x·y exactly.
+func fmaMul(x, y *big.Float) *big.Float {
+	return new(big.Float).SetPrec(x.Prec()+y.Prec()).Mul(x, y)
+}
+
+// lowBit returns the exponent of the lowest set bit of the finite nonzero x.
+func lowBit(x *big.Float) int {
+	return x.MantExp(nil) - int(x.MinPrec())
+}
@@ -0,0 +1,224 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+// smallFloats returns every float with a 3-bit mantissa and binary exponent
+// in [-2, 2], both signs, and zero.
+func smallFloats() []*big.Float {
+	r := []*big.Float{new(big.Float).SetPrec(3)}
+	for m := int64(4); m < 8; m++ {
+		for e := -2; e <= 2; e++ {
+			x := new(big.Float).SetPrec(3).SetInt64(m)
+			x.SetMantExp(x, e-3)
+			r = append(r, x, new(big.Float).Neg(x))
+		}
+	}
+	return r
+}
+
+var allModes = []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
+
+// ratOf returns the exact value of the finite x.
+func ratOf(x *big.Float) *big.Rat {
+	r, _ := x.Rat(nil)
+	return r
+}
+
+func TestFmaExhaustive(t *testing.T) {
+	xs := smallFloats()
+	for _, mode := range allModes {
+		for _, x := range xs {
+			for _, y := range xs {
+				xy := new(big.Rat).Mul(ratOf(x), ratOf(y))
+				for _, z := range xs {
+					v := new(big.Rat).Add(xy, ratOf(z))
+					want := new(big.Float).SetPrec(3).SetMode(mode).SetRat(v)
+					got := bigfloat.Fma(new(big.Float).SetPrec(3).SetMode(mode), x, y, z)
+					if got.Cmp(want) != 0 {
+						t.Errorf("Fma(%g, %g, %g) under %v = %g, want %g", x, y, z, mode, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestFma(t *testing.T) {
+	// x·y + z where the product and the sum round differently: with a
+	// separate multiplication, (1+2**-60)(1-2**-60) - 1 is zero at 53 bits.
+	x := new(big.Float).SetMantExp(big.NewFloat(1), -60)
+	x.SetPrec(61)
+	y := new(big.Float).Neg(x)
+	x.Add(x, big.NewFloat(1))
+	y.Add(y, big.NewFloat(1))
+	got := bigfloat.Fma(new(big.Float).SetPrec(53), x, y, big.NewFloat(-1))
+	want := new(big.Float).SetMantExp(big.NewFloat(-1), -120)
+	if got.Cmp(want) != 0 {
+		t.Errorf("Fma(1+2**-60, 1-2**-60, -1) = %g, want %g", got, want)
+	}
+	// Precision defaults to the greatest of the arguments'.
+	z := new(big.Float).SetPrec(200).SetInt64(3)
+	if got := bigfloat.Fma(new(big.Float), big.NewFloat(2), big.NewFloat(5), z); got.Prec() != 200 || got.Cmp(big.NewFloat(13)) != 0 {
+		t.Errorf("Fma(2, 5, 3) = %g with precision %d, want 13 with precision 200", got, got.Prec())
+	}
+	// Aliasing.
+	a := big.NewFloat(3)
+	if got := bigfloat.Fma(a, a, a, a); got.Cmp(big.NewFloat(12)) != 0 {
+		t.Errorf("Fma(3, 3, 3) in place = %g, want 12", got)
+	}
+	// Infinities.
+	inf := big.NewFloat(math.Inf(1))
+	if got := bigfloat.Fma(new(big.Float), inf, big.NewFloat(-2), big.NewFloat(1)); !got.IsInf() || !got.Signbit() {
+		t.Errorf("Fma(Inf, -2, 1) = %g, want -Inf", got)
+	}
+	if got := bigfloat.Fma(new(big.Float), big.NewFloat(2), big.NewFloat(3), inf); !got.IsInf() || got.Signbit() {
+		t.Errorf("Fma(2, 3, Inf) = %g, want +Inf", got)
+	}
+	expectNaN(t, "Fma(Inf, 0, 1)", func() { bigfloat.Fma(new(big.Float), inf, new(big.Float), big.NewFloat(1)) })
+	expectNaN(t, "Fma(0, -Inf, 1)", func() { bigfloat.Fma(new(big.Float), new(big.Float), new(big.Float).Neg(inf), big.NewFloat(1)) })
+	expectNaN(t, "Fma(Inf, 1, -Inf)", func() { bigfloat.Fma(new(big.Float), inf, big.NewFloat(1), new(big.Float).Neg(inf)) })
+}
+
+func TestTwoSumExhaustive(t *testing.T) {
+	xs := smallFloats()
+	for _, mode := range allModes {
+		for _, x := range xs {
+			for _, y := range xs {
+				v := new(big.Rat).Add(ratOf(x), ratOf(y))
+				want := new(big.Float).SetPrec(3).SetMode(mode).SetRat(v)
+				// With a wide enough e, the error is exact under every mode.
+				s, e := bigfloat.TwoSum(new(big.Float).SetPrec(3).SetMode(mode), new(big.Float).SetPrec(16), x, y)
+				if s.Cmp(want) != 0 {
+					t.Errorf("TwoSum(%g, %g) under %v: s = %g, want %g", x, y, mode, s, want)
+				}
+				if r := new(big.Rat).Add(ratOf(s), ratOf(e)); r.Cmp(v) != 0 {
+					t.Errorf("TwoSum(%g, %g) under %v: s + e = %v, want %v", x, y, mode, r, v)
+				}
+			}
+		}
+	}
+	// Rounding to nearest, the error fits in s's precision.
+	for _, x := range xs {
+		for _, y := range xs {
+			s, e := bigfloat.TwoSum(new(big.Float).SetPrec(3), new(big.Float), x, y)
+			if e.Prec() != 3 {
+				t.Errorf("TwoSum(%g, %g): e has precision %d, want 3", x, y, e.Prec())
+			}
+			v := new(big.Rat).Add(ratOf(x), ratOf(y))
+			if r := new(big.Rat).Add(ratOf(s), ratOf(e)); r.Cmp(v) != 0 {
+				t.Errorf("TwoSum(%g, %g): s + e = %v, want %v", x, y, r, v)
+			}
+		}
+	}
+}
+
+func TestTwoProductExhaustive(t *testing.T) {
+	xs := smallFloats()
+	for _, mode := range allModes {
+		for _, x := range xs {
+			for _, y := range xs {
+				v := new(big.Rat).Mul(ratOf(x), ratOf(y))
+				want := new(big.Float).SetPrec(3).SetMode(mode).SetRat(v)
+				p, e := bigfloat.TwoProduct(new(big.Float).SetPrec(3).SetMode(mode), new(big.Float), x, y)
+				if p.Cmp(want) != 0 {
+					t.Errorf("TwoProduct(%g, %g) under %v: p = %g, want %g", x, y, mode, p, want)
+				}
+				if e.Prec() != 3 {
+					t.Errorf("TwoProduct(%g, %g) under %v: e has precision %d, want 3", x, y, mode, e.Prec())
+				}
+				if r := new(big.Rat).Add(ratOf(p), ratOf(e)); r.Cmp(v) != 0 {
+					t.Errorf("TwoProduct(%g, %g) under %v: p + e = %v, want %v", x, y, mode, r, v)
+				}
+			}
+		}
+	}
+}
+
+func TestTwoSum(t *testing.T) {
+	// Widely separated operands.
+	x := big.NewFloat(1)
+	y := new(big.Float).SetMantExp(big.NewFloat(1), -100000)
+	s, e := bigfloat.TwoSum(new(big.Float), new(big.Float), y, x)
+	if s.Cmp(x) != 0 || e.Cmp(y) != 0 {
+		t.Errorf("TwoSum(2**-100000, 1) = %g, %g; want 1, 2**-100000", s, e)
+	}
+	// Cancellation.
+	x = parse("1.00000000000000000001", 100)
+	y = parse("-1", 100)
+	s, e = bigfloat.TwoSum(new(big.Float), new(big.Float), x, y)
+	if want := new(big.Float).SetPrec(100).Sub(x, big.NewFloat(1)); s.Cmp(want) != 0 || e.Sign() != 0 {
+		t.Errorf("TwoSum(1.00000000000000000001, -1) = %g, %g; want %g, 0", s, e, want)
+	}
+	// The error is exact only if s is at least as precise as the operands.
+	x = new(big.Float).SetPrec(5).SetFloat64(1.4375)
+	y = new(big.Float).SetPrec(5).SetMantExp(big.NewFloat(1), -100)
+	v := new(big.Rat).Add(ratOf(x), ratOf(y))
+	s, e = bigfloat.TwoSum(new(big.Float).SetPrec(5), new(big.Float), x, y)
+	if r := new(big.Rat).Add(ratOf(s), ratOf(e)); r.Cmp(v) != 0 {
+		t.Errorf("TwoSum(1.4375, 2**-100) at 5 bits: s + e = %v, want %v", r, v)
+	}
+	s, e = bigfloat.TwoSum(new(big.Float).SetPrec(1), new(big.Float).SetPrec(5), x, y)
+	if r := new(big.Rat).Add(ratOf(s), ratOf(e)); r.Cmp(v) == 0 {
+		t.Errorf("TwoSum(1.4375, 2**-100) at 1 bit: s + e = x + y exactly, but e has only 5 bits")
+	}
+	// Aliasing both outputs with the inputs.
+	a, b := big.NewFloat(1), new(big.Float).SetMantExp(big.NewFloat(1), -60)
+	s, e = bigfloat.TwoSum(a, b, a, b)
+	if s.Cmp(big.NewFloat(1)) != 0 || e.Cmp(new(big.Float).SetMantExp(big.NewFloat(1), -60)) != 0 {
+		t.Errorf("TwoSum(1, 2**-60) in place = %g, %g; want 1, 2**-60", s, e)
+	}
+	inf := big.NewFloat(math.Inf(1))
+	s, e = bigfloat.TwoSum(new(big.Float), new(big.Float), inf, big.NewFloat(1))
+	if !s.IsInf() || e.Sign() != 0 {
+		t.Errorf("TwoSum(Inf, 1) = %g, %g; want +Inf, 0", s, e)
+	}
+	expectNaN(t, "TwoSum(Inf, -Inf)", func() { bigfloat.TwoSum(new(big.Float), new(big.Float), inf, new(big.Float).Neg(inf)) })
+}
+
+func TestTwoProduct(t *testing.T) {
+	x := parse("1.1", 200)
+	y := parse("3.3", 200)
+	p, e := bigfloat.TwoProduct(new(big.Float), new(big.Float), x, y)
+	want := new(big.Float).SetPrec(400).Mul(x, y)
+	sum := new(big.Float).SetPrec(400).Add(p, e)
+	if p.Prec() != 200 || e.Prec() != 200 || sum.Cmp(want) != 0 {
+		t.Errorf("TwoProduct(1.1, 3.3) = %g, %g; p + e = %g, want %g", p, e, sum, want)
+	}
+	a, b := big.NewFloat(1.1), big.NewFloat(3.3)
+	p, e = bigfloat.TwoProduct(b, a, a, b)
+	want = new(big.Float).SetPrec(106).Mul(big.NewFloat(1.1), big.NewFloat(3.3))
+	sum = new(big.Float).SetPrec(106).Add(p, e)
+	if sum.Cmp(want) != 0 {
+		t.Errorf("TwoProduct(1.1, 3.3) in place: p + e = %g, want %g", sum, want)
+	}
+	inf := big.NewFloat(math.Inf(1))
+	p, e = bigfloat.TwoProduct(new(big.Float), new(big.Float), inf, big.NewFloat(-1))
+	if !p.IsInf() || !p.Signbit() || e.Sign() != 0 {
+		t.Errorf("TwoProduct(Inf, -1) = %g, %g; want -Inf, 0", p, e)
+	}
+	expectNaN(t, "TwoProduct(Inf, 0)", func() { bigfloat.TwoProduct(new(big.Float), new(big.Float), inf, new(big.Float)) })
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkFma(b *testing.B) {
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		x := bigfloat.Pi(new(big.Float).SetPrec(prec))
+		y := new(big.Float).SetPrec(prec).Sqrt(big.NewFloat(2).SetPrec(prec))
+		z := new(big.Float).SetPrec(prec).Neg(x)
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Fma(o, x, y, z)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// gammaExactMax is the largest integer for which Gamma and LogGamma use the
+// exact factorial.
+const gammaExactMax = 1000
+
+// Gamma sets o to Γ(z) to o's precision and returns o. If o's precision is
+// zero, then it is given the precision of z. Gamma(±0) = ±Inf and
+// Gamma(+Inf) = +Inf. Panics with ErrNaN if z is a negative integer or -Inf.
+// For positive integers up to 1000, the result is the exact factorial (z-1)!
+// rounded to o's precision.
+func Gamma(o, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	switch {
+	case z.IsInf():
+		if z.Signbit() {
+			panic(ErrNaN{msg: "Gamma: argument is -Inf"})
+		}
+		return o.Set(z)
+	case z.Sign() == 0:
+		return o.SetInf(z.Signbit())
+	case z.IsInt():
+		if z.Signbit() {
+			panic(ErrNaN{msg: "Gamma: argument is a negative integer"})
+		}
+		if n, acc := z.Int64(); acc == big.Exact && n <= gammaExactMax {
+			return o.SetInt(new(big.Int).MulRange(1, n-1))
+		}
+	}
+	prec := o.Prec()
+	wp := prec + 64
+
+	if z.Signbit() {
+		// Γ(z) = π / (sin(πz) Γ(1-z))
+		s := sinPi(new(big.Float).SetPrec(wp), z)
+		g := new(big.Float).SetPrec(reflectPrec(z, wp)).Sub(&gonep, z)
+		g = Gamma(new(big.Float).SetPrec(wp), g)
+		g.Mul(g, s)
+		r := new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+		return o.Set(r.Quo(r, g))
+	}
+
+	// Γ(z) = exp(log Γ(z)). The absolute error in log Γ(z) becomes relative
+	// error in Γ(z), so we need extra precision in proportion to the size of
+	// log Γ(z), which is about z log z for large z and -log z for small z.
+	e := z.MantExp(nil)
+	if e > 0 {
+		wp += uint(e)
+	}
+	if e < 0 {
+		e = -e
+	}
+	wp += uint(bits.Len(uint(e)))
+	lg, _ := lgammaPos(new(big.Float).SetPrec(wp), z)
+	return o.Set(Exp(lg, lg))
+}
+
+// LogGamma sets o to the natural logarithm of |Γ(z)| to o's precision and
+// returns o and the sign of Γ(z), either -1 or +1. If o's precision is zero,
+// then it is given the precision of z. As with math.Lgamma, the result is +Inf
+// when z is +Inf, zero, or a negative integer, and -Inf when z is -Inf. The
+// sign is -1 for z = -0, matching Gamma(-0) = -Inf. LogGamma(1) and
+// LogGamma(2) are exactly zero, and the result has full relative precision
+// near those points as well.
+func LogGamma(o, z *big.Float) (*big.Float, int) {
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	switch {
+	case z.IsInf():
+		return o.Set(z), 1
+	case z.Sign() == 0:
+		if z.Signbit() {
+			return o.SetInf(false), -1
+		}
+		return o.SetInf(false), 1
+	case z.IsInt():
+		if z.Signbit() {
+			return o.SetInf(false), 1
+		}
+		if n, acc := z.Int64(); acc == big.Exact && n <= gammaExactMax {
+			if n <= 2 {
+				return o.SetInt64(0), 1
+			}
+			f := new(big.Int).MulRange(1, n-1)
+			return Log(o, new(big.Float).SetInt(f)), 1
+		}
+	}
+	prec := o.Prec()
+
+	// The result may be much smaller than the terms we compute it from, e.g.
+	// near 1 and 2. Retry with enough extra precision to cover the bits lost
+	// to cancellation.
+	var sign int
+	r := ziv(prec, func(wp uint) (*big.Float, int) {
+		var r *big.Float
+		var mag int
+		r, mag, sign = lgamma(new(big.Float).SetPrec(wp), z)
+		return r, mag
+	})
+	return o.Set(r), sign
+}
+
+// lgamma sets o to log |Γ(z)| for finite, nonzero z which is not a negative
+// integer. It returns o, the binary exponent of the largest quantity in the
+// computation, and the sign of Γ(z). The absolute error in o is about
+// 2**(mag - o.Prec()).
+func lgamma(o, z *big.Float) (r *big.Float, mag, sign int) {
+	if !z.Signbit() {
+		r, mag = lgammaPos(o, z)
+		return r, mag, 1
+	}
+	// log |Γ(z)| = log π - log |sin(πz)| - log Γ(1-z)
+	wp := o.Prec()
+	s := sinPi(new(big.Float).SetPrec(wp), z)
+	sign = s.Sign()
+	Log(s, s.Abs(s))
+	w := new(big.Float).SetPrec(reflectPrec(z, wp)).Sub(&gonep, z)
+	g, mag := lgammaPos(new(big.Float).SetPrec(wp), w)
+	if e := s.MantExp(nil); e > mag {
+		mag = e
+	}
+	lp := Log(new(big.Float).SetPrec(wp), cachedPi(wp))
+	o.Sub(lp, s)
+	o.Sub(o, g)
+	return o, mag, sign
+}
+
+// reflectPrec returns a precision at least wp which is enough to represent
+// 1-z exactly if |z| ≥ 1, so that a large non-integer z does not become an
+// integer under reflection.
+func reflectPrec(z *big.Float, wp uint) uint {
+	if z.MantExp(nil) > 0 && z.Prec() >= wp {
+		return z.Prec() + 1
+	}
+	return wp
+}
+
+// lgammaPos sets o to log Γ(x) for finite x > 0 and returns o along with the
+// binary exponent of the largest quantity in the computation. The absolute
+// error in o is about 2**(mag - o.Prec()).
+func lgammaPos(o, x *big.Float) (*big.Float, int) {
+	wp := o.Prec() + 16
+	// Shift x upward by n so that the Stirling series converges to wp bits,
+	// using
+	//    log Γ(x) = log Γ(x+n) - log(x (x+1) ... (x+n-1)).
+	var n int64
+	lim := float64(wp)/5 + 1
+	if xf, _ := x.Float64(); xf < lim {
+		n = int64(math.Ceil(lim - xf))
+		wp += uint(bits.Len64(uint64(n)))
+	}
+	y := new(big.Float).SetPrec(wp).Set(x)
+	var p *big.Float
+	if n > 0 {
+		p = new(big.Float).SetPrec(wp).Set(x)
+		t := new(big.Float).SetPrec(wp)
+		for i := int64(1); i < n; i++ {
+			t.SetInt64(i)
+			p.Mul(p, t.Add(t, x))
+		}
+		y.Add(y, t.SetInt64(n))
+	}
+
+	s := stirling(new(big.Float).SetPrec(wp), y)
+	mag := s.MantExp(nil)
+	if p != nil {
+		Log(p, p)
+		if e := p.MantExp(nil); e > mag {
+			mag = e
+		}
+		s.Sub(s, p)
+	}
+	return o.Set(s), mag
+}
+
+// stirling sets o to log Γ(y) to o's precision using the Stirling series
+//
+//	log Γ(y) ~ (y - 1/2) log y - y + log(2π)/2 + Σ B_2k / (2k (2k-1) y**(2k-1))
+//
+// and returns o. y must be at least o.Prec()/5 + 1 for the series to reach
+// o's precision.
+func stirling(o, y *big.Float) *big.Float {
+	wp := o.Prec()
+	ly := Log(new(big.Float).SetPrec(wp), y)
+	o.Sub(y, &ghalfp)
+	o.Mul(o, ly)
+	o.Sub(o, y)
+	l2pi := quicksh(new(big.Float), cachedPi(wp), 1).SetPrec(wp)
+	Log(l2pi, l2pi)
+	o.Add(o, quicksh(l2pi, l2pi, -1))
+
+	r := new(big.Float).SetPrec(wp).Quo(&gonep, y)
+	r2 := new(big.Float).SetPrec(wp).Mul(r, r)
+	t := new(big.Float).SetPrec(wp)
+	b := bernoulli(int(wp/7) + 2)
+	for k := 1; ; k++ {
+		if k >= len(b) {
+			b = bernoulli(2 * k)
+		}
+		t.SetRat(b[k])
+		t.Mul(t, r)
+		t.Quo(t, new(big.Float).SetInt64(int64(2*k*(2*k-1))))
+		o.Add(o, t)
+		if t.MantExp(nil) < o.MantExp(nil)-int(wp) {
+			return o
+		}
+		r.Mul(r, r2)
+	}
+}
@@ -0,0 +1,241 @@
+package bigfloat_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+// closeTo returns true if x and want differ by at most 2**-bits relative to
+// want, or if both are zero.
+func closeTo(x, want *big.Float, bits uint) bool {
+	if want.Sign() == 0 {
+		return x.Sign() == 0
+	}
+	d := new(big.Float).SetPrec(want.Prec()+64).Sub(x, want)
+	d.Quo(d, want)
+	return d.Sign() == 0 || d.MantExp(nil) <= -int(bits)
+}
+
+// parse returns s parsed as a big.Float with the given precision.
+func parse(s string, prec uint) *big.Float {
+	x, _, err := new(big.Float).SetPrec(prec).Parse(s, 10)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+// expectNaN calls f and reports an error if it does not panic with
+// ErrNaN.
+func expectNaN(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, big.ErrNaN{}) {
+			t.Errorf("%s: expected ErrNaN panic, got %v", name, r)
+		}
+	}()
+	f()
+}
+
+func TestGammaIdentities(t *testing.T) {
+	for _, prec := range []uint{24, 53, 64, 100, 200, 500, 1000} {
+		wp := prec + 64
+		sqrtPi := bigfloat.Pi(new(big.Float).SetPrec(wp))
+		sqrtPi.Sqrt(sqrtPi)
+
+		// Γ(1/2) = √π
+		g := bigfloat.Gamma(new(big.Float).SetPrec(prec), big.NewFloat(0.5))
+		if !closeTo(g, sqrtPi, prec-1) {
+			t.Errorf("prec = %d: Gamma(1/2) = %g, want √π = %g", prec, g, sqrtPi)
+		}
+
+		// Γ(-1/2) = -2√π
+		g = bigfloat.Gamma(new(big.Float).SetPrec(prec), big.NewFloat(-0.5))
+		want := new(big.Float).SetPrec(wp).Mul(sqrtPi, big.NewFloat(-2))
+		if !closeTo(g, want, prec-1) {
+			t.Errorf("prec = %d: Gamma(-1/2) = %g, want -2√π = %g", prec, g, want)
+		}
+
+		// Γ(1/4)² = (2π)**(3/2) / AGM(1, √2)
+		g = bigfloat.Gamma(new(big.Float).SetPrec(prec), big.NewFloat(0.25))
+		g.SetPrec(wp).Mul(g, g)
+		want = bigfloat.Pi(new(big.Float).SetPrec(wp))
+		want.Mul(want, big.NewFloat(2))
+		want.Mul(want, new(big.Float).Sqrt(want))
+		sqrt2 := new(big.Float).SetPrec(wp).Sqrt(big.NewFloat(2))
+		want.Quo(want, bigfloat.AGM(new(big.Float), big.NewFloat(1).SetPrec(wp), sqrt2))
+		if !closeTo(g, want, prec-2) {
+			t.Errorf("prec = %d: Gamma(1/4)² = %g, want %g", prec, g, want)
+		}
+
+		// Γ(5/4) Γ(7/4) = 3π / (8√2), by the duplication formula
+		g = bigfloat.Gamma(new(big.Float).SetPrec(prec), big.NewFloat(1.25))
+		h := bigfloat.Gamma(new(big.Float).SetPrec(prec), big.NewFloat(1.75))
+		g.SetPrec(wp).Mul(g, h)
+		want = bigfloat.Pi(new(big.Float).SetPrec(wp))
+		want.Mul(want, big.NewFloat(3.0/8))
+		want.Quo(want, sqrt2)
+		if !closeTo(g, want, prec-2) {
+			t.Errorf("prec = %d: Gamma(5/4) Gamma(7/4) = %g, want %g", prec, g, want)
+		}
+
+		// Γ(x+1) = x Γ(x)
+		for _, x := range []float64{2.375, 0.015625, -3.75, 37.125, -0.9990234375} {
+			g = bigfloat.Gamma(new(big.Float).SetPrec(prec), big.NewFloat(x+1))
+			want = bigfloat.Gamma(new(big.Float).SetPrec(wp), big.NewFloat(x))
+			want.Mul(want, big.NewFloat(x))
+			if !closeTo(g, want, prec-2) {
+				t.Errorf("prec = %d: Gamma(%g) = %g, want %g", prec, x+1, g, want)
+			}
+		}
+	}
+}
+
+func TestGammaIntegers(t *testing.T) {
+	for n := int64(1); n <= 1001; n += 10 {
+		want := new(big.Int).MulRange(1, n-1)
+		for _, prec := range []uint{24, 53, 1000, 10000} {
+			w := new(big.Float).SetPrec(prec).SetInt(want)
+			g := bigfloat.Gamma(new(big.Float).SetPrec(prec), big.NewFloat(float64(n)))
+			if !closeTo(g, w, prec) {
+				t.Errorf("prec = %d: Gamma(%d) =\ngot  %g;\nwant %g", prec, n, g, w)
+			}
+		}
+	}
+	// Γ(21) = 20! fits in 64 bits, so it must be exact.
+	g := bigfloat.Gamma(new(big.Float).SetPrec(64), big.NewFloat(21))
+	if i, acc := g.Uint64(); i != 2432902008176640000 || acc != big.Exact {
+		t.Errorf("Gamma(21) = %d (%v), want 2432902008176640000 (Exact)", i, acc)
+	}
+}
+
+func TestGammaFloat64(t *testing.T) {
+	for _, scale := range []float64{1e-3, 1, 10, 170, -10} {
+		for i := 0; i < 200; i++ {
+			x := rand.Float64() * scale
+			if x == math.Trunc(x) {
+				continue
+			}
+			g, _ := bigfloat.Gamma(new(big.Float).SetPrec(53), big.NewFloat(x)).Float64()
+			want := math.Gamma(x)
+			// math.Gamma is accurate to only a few ulps.
+			if math.Abs(g-want) > 1e-13*math.Abs(want) {
+				t.Errorf("Gamma(%g) = %g, want %g", x, g, want)
+			}
+		}
+	}
+}
+
+func TestGammaSpecialValues(t *testing.T) {
+	for _, f := range []float64{
+		+0.0,
+		math.Copysign(0, -1),
+		math.Inf(+1),
+	} {
+		z := big.NewFloat(f)
+		x64, acc := bigfloat.Gamma(z, z).Float64()
+		want := math.Gamma(f)
+		if x64 != want || acc != big.Exact {
+			t.Errorf("Gamma(%g) =\n got %g (%s);\nwant %g (Exact)", f, x64, acc, want)
+		}
+	}
+	for _, f := range []float64{-1, -2, -1e10, math.Inf(-1)} {
+		expectNaN(t, fmt.Sprintf("Gamma(%g)", f), func() { bigfloat.Gamma(new(big.Float), big.NewFloat(f)) })
+	}
+}
+
+func TestLogGammaFloat64(t *testing.T) {
+	for _, scale := range []float64{1e-3, 1, 3, 100, 1e10, -10} {
+		for i := 0; i < 200; i++ {
+			x := rand.Float64() * scale
+			if x == math.Trunc(x) {
+				continue
+			}
+			l, sign := bigfloat.LogGamma(new(big.Float).SetPrec(53), big.NewFloat(x))
+			lf, _ := l.Float64()
+			want, wsign := math.Lgamma(x)
+			if math.Abs(lf-want) > 1e-13*math.Max(1, math.Abs(want)) || sign != wsign {
+				t.Errorf("LogGamma(%g) = %g, %d; want %g, %d", x, lf, sign, want, wsign)
+			}
+		}
+	}
+}
+
+func TestLogGammaNearOneTwo(t *testing.T) {
+	const (
+		prec = 150
+		// Euler–Mascheroni constant γ
+		gammaStr = "0.57721566490153286060651209008240243104215933593992"
+	)
+	eps := new(big.Float).SetMantExp(big.NewFloat(1), -100)
+	eg := parse(gammaStr, prec+64)
+	pi2 := bigfloat.Pi(new(big.Float).SetPrec(prec + 64))
+	pi2.Mul(pi2, pi2)
+	for _, sgn := range []float64{1, -1} {
+		e := new(big.Float).SetPrec(prec+64).Mul(eps, big.NewFloat(sgn))
+		e2 := new(big.Float).SetPrec(prec+64).Mul(e, e)
+		// log Γ(1+ε) = -γε + π²ε²/12 - O(ε³)
+		z := new(big.Float).SetPrec(prec+64).Add(big.NewFloat(1), e)
+		want := new(big.Float).SetPrec(prec+64).Mul(eg, e)
+		want.Neg(want)
+		want.Add(want, new(big.Float).Quo(new(big.Float).Mul(pi2, e2), big.NewFloat(12)))
+		got, _ := bigfloat.LogGamma(new(big.Float).SetPrec(prec), z)
+		if !closeTo(got, want, prec-4) {
+			t.Errorf("LogGamma(1%+g) =\ngot  %g;\nwant %g", e, got, want)
+		}
+		// log Γ(2+ε) = (1-γ)ε + (π²/12 - 1/2)ε² + O(ε³)
+		z.Add(big.NewFloat(2), e)
+		want.Sub(big.NewFloat(1), eg)
+		want.Mul(want, e)
+		c := new(big.Float).Quo(pi2, big.NewFloat(12))
+		c.Sub(c, big.NewFloat(0.5))
+		want.Add(want, c.Mul(c, e2))
+		got, _ = bigfloat.LogGamma(new(big.Float).SetPrec(prec), z)
+		if !closeTo(got, want, prec-4) {
+			t.Errorf("LogGamma(2%+g) =\ngot  %g;\nwant %g", e, got, want)
+		}
+	}
+}
+
+func TestLogGammaSpecialValues(t *testing.T) {
+	for _, f := range []float64{
+		+0.0,
+		-3,
+		math.Inf(+1),
+		math.Inf(-1),
+		1,
+		2,
+	} {
+		z := big.NewFloat(f)
+		x, _ := bigfloat.LogGamma(z, z)
+		x64, acc := x.Float64()
+		want, _ := math.Lgamma(f)
+		if x64 != want || acc != big.Exact {
+			t.Errorf("LogGamma(%g) =\n got %g (%s);\nwant %g (Exact)", f, x64, acc, want)
+		}
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkGamma(b *testing.B) {
+	z := big.NewFloat(2.5)
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Gamma(o, z)
+			}
+		})
+	}
+}
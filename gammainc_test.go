@@ -0,0 +1,179 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestGammaIncIdentities(t *testing.T) {
+	for _, prec := range []uint{53, 100, 300, 1000} {
+		for _, xf := range []float64{1e-10, 0.25, 1, 2.5, 7, 30, 200} {
+			x := new(big.Float).SetPrec(prec).SetFloat64(xf)
+			// P(1, x) = 1 - exp(-x)
+			want := bigfloat.Exp(new(big.Float).SetPrec(prec+64), new(big.Float).SetPrec(prec+64).Neg(x))
+			want.Sub(big.NewFloat(1), want)
+			got := bigfloat.GammaIncP(new(big.Float).SetPrec(prec), big.NewFloat(1), x)
+			if !closeTo(got, want, prec-2) {
+				t.Errorf("P(1, %g) at prec %d:\ngot  %g\nwant %g", xf, prec, got, want)
+			}
+			// Γ(1, x) = exp(-x)
+			want = bigfloat.Exp(new(big.Float).SetPrec(prec+64), new(big.Float).SetPrec(prec+64).Neg(x))
+			got = bigfloat.GammaIncUpper(new(big.Float).SetPrec(prec), big.NewFloat(1), x)
+			if !closeTo(got, want, prec-2) {
+				t.Errorf("Γ(1, %g) at prec %d:\ngot  %g\nwant %g", xf, prec, got, want)
+			}
+			// Q(1/2, x) = erfc(√x)
+			r := new(big.Float).SetPrec(prec + 64).Sqrt(x)
+			want = bigfloat.Erfc(new(big.Float).SetPrec(prec+64), r)
+			got = bigfloat.GammaIncQ(new(big.Float).SetPrec(prec), big.NewFloat(0.5), x)
+			if !closeTo(got, want, prec-4) {
+				t.Errorf("Q(1/2, %g) at prec %d:\ngot  %g\nwant %g", xf, prec, got, want)
+			}
+		}
+	}
+}
+
+func TestGammaIncComplement(t *testing.T) {
+	for _, prec := range []uint{53, 200, 500} {
+		for _, a := range []float64{1e-3, 0.5, 3, 10.5, 100} {
+			for _, xf := range []float64{1e-3, 0.5, 3, 11, 90, 120} {
+				x := big.NewFloat(xf)
+				a := big.NewFloat(a)
+				// P + Q = 1
+				p := bigfloat.GammaIncP(new(big.Float).SetPrec(prec), a, x)
+				q := bigfloat.GammaIncQ(new(big.Float).SetPrec(prec), a, x)
+				s := new(big.Float).SetPrec(prec+64).Add(p, q)
+				if !closeTo(s, big.NewFloat(1), prec-2) {
+					t.Errorf("P + Q at (%g, %g) prec %d = %g", a, x, prec, s)
+				}
+				// γ + Γ(a, x) = Γ(a)
+				l := bigfloat.GammaIncLower(new(big.Float).SetPrec(prec), a, x)
+				u := bigfloat.GammaIncUpper(new(big.Float).SetPrec(prec), a, x)
+				s.Add(l, u)
+				g := bigfloat.Gamma(new(big.Float).SetPrec(prec+64), a)
+				if !closeTo(s, g, prec-2) {
+					t.Errorf("γ + Γ at (%g, %g) prec %d:\ngot  %g\nwant %g", a, x, prec, s, g)
+				}
+				// γ = Γ(a) P
+				g.Mul(g, p)
+				if !closeTo(l, g, prec-3) {
+					t.Errorf("γ at (%g, %g) prec %d:\ngot  %g\nwant %g", a, x, prec, l, g)
+				}
+			}
+		}
+	}
+}
+
+func TestGammaIncTail(t *testing.T) {
+	// Far into either tail, the small function must keep full relative
+	// precision rather than coming from 1 minus the large one.
+	for _, test := range []struct {
+		a, x float64
+		f    func(o, a, x *big.Float) *big.Float
+		name string
+	}{
+		{50, 1e-3, bigfloat.GammaIncP, "P"},
+		{2.5, 30, bigfloat.GammaIncQ, "Q"},
+		{1, 1000, bigfloat.GammaIncQ, "Q"},
+		{1000, 1, bigfloat.GammaIncLower, "γ"},
+	} {
+		a, x := big.NewFloat(test.a), big.NewFloat(test.x)
+		got := test.f(new(big.Float).SetPrec(100), a, x)
+		want := test.f(new(big.Float).SetPrec(300), a, x)
+		if got.Sign() == 0 || !closeTo(got, want, 98) {
+			t.Errorf("%s(%g, %g):\ngot  %g\nwant %g", test.name, test.a, test.x, got, want)
+		}
+	}
+	// Poisson CDF: P(N ≤ 2) with mean 1 is Q(3, 1) = 5/(2e).
+	e := bigfloat.Exp(new(big.Float).SetPrec(300), new(big.Float).SetPrec(300).SetInt64(1))
+	want := new(big.Float).SetPrec(300).Quo(big.NewFloat(2.5), e)
+	got := bigfloat.GammaIncQ(new(big.Float).SetPrec(200), big.NewFloat(3), big.NewFloat(1))
+	if !closeTo(got, want, 198) {
+		t.Errorf("Q(3, 1):\ngot  %g\nwant %g", got, want)
+	}
+}
+
+func TestGammaIncPrecision(t *testing.T) {
+	for _, c := range [][2]string{{"100", "90"}, {"100", "101.5"}, {"0.001", "0.5"}, {"1e4", "1e4"}, {"2.5", "30"}, {"1e-20", "1e-5"}} {
+		a, x := parse(c[0], 1200), parse(c[1], 1200)
+		for _, f := range []struct {
+			name string
+			f    func(o, a, x *big.Float) *big.Float
+		}{
+			{"GammaIncLower", bigfloat.GammaIncLower},
+			{"GammaIncUpper", bigfloat.GammaIncUpper},
+			{"GammaIncP", bigfloat.GammaIncP},
+			{"GammaIncQ", bigfloat.GammaIncQ},
+		} {
+			want := f.f(new(big.Float).SetPrec(1000), a, x)
+			for _, prec := range []uint{53, 300} {
+				got := f.f(new(big.Float).SetPrec(prec), a, x)
+				if !closeTo(got, want, prec-2) {
+					t.Errorf("%s(%s, %s) at prec %d:\ngot  %g\nwant %g", f.name, c[0], c[1], prec, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestGammaIncSpecialValues(t *testing.T) {
+	inf := math.Inf(1)
+	for _, test := range []struct {
+		name string
+		f    func(o, a, x *big.Float) *big.Float
+		a, x float64
+		want float64
+	}{
+		{"GammaIncLower", bigfloat.GammaIncLower, 3, 0, 0},
+		{"GammaIncLower", bigfloat.GammaIncLower, 3, inf, 2},
+		{"GammaIncUpper", bigfloat.GammaIncUpper, 3, 0, 2},
+		{"GammaIncUpper", bigfloat.GammaIncUpper, 3, math.Copysign(0, -1), 2},
+		{"GammaIncUpper", bigfloat.GammaIncUpper, 3, inf, 0},
+		{"GammaIncP", bigfloat.GammaIncP, 0.5, 0, 0},
+		{"GammaIncP", bigfloat.GammaIncP, 0.5, inf, 1},
+		{"GammaIncP", bigfloat.GammaIncP, 1, 1e10, 1},
+		{"GammaIncQ", bigfloat.GammaIncQ, 0.5, 0, 1},
+		{"GammaIncQ", bigfloat.GammaIncQ, 0.5, inf, 0},
+		{"GammaIncQ", bigfloat.GammaIncQ, 1, 1e10, 0},
+	} {
+		x64, acc := test.f(new(big.Float).SetPrec(53), big.NewFloat(test.a), big.NewFloat(test.x)).Float64()
+		if x64 != test.want || acc != big.Exact {
+			t.Errorf("%s(%g, %g) = %g (%s), want %g (Exact)", test.name, test.a, test.x, x64, acc, test.want)
+		}
+	}
+	for _, test := range []struct {
+		a, x float64
+	}{
+		{0, 1},
+		{-1, 1},
+		{inf, 1},
+		{1, -1},
+		{1, math.Inf(-1)},
+	} {
+		expectNaN(t, fmt.Sprintf("GammaIncP(%g, %g)", test.a, test.x), func() {
+			bigfloat.GammaIncP(new(big.Float), big.NewFloat(test.a), big.NewFloat(test.x))
+		})
+		expectNaN(t, fmt.Sprintf("GammaIncUpper(%g, %g)", test.a, test.x), func() {
+			bigfloat.GammaIncUpper(new(big.Float), big.NewFloat(test.a), big.NewFloat(test.x))
+		})
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkGammaIncP(b *testing.B) {
+	a, x := big.NewFloat(2.5), big.NewFloat(3)
+	for _, prec := range []uint{1e2, 1e3} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.GammaIncP(o, a, x)
+			}
+		})
+	}
+}
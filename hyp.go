@@ -0,0 +1,309 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+)
+
+// HypPFQ sets o to the generalized hypergeometric function
+//
+//	pFq(a; b; z) = Σ (a_1)_k ... (a_p)_k / ((b_1)_k ... (b_q)_k) z**k/k!,
+//
+// where p = len(a), q = len(b), and (x)_k = x (x+1) ... (x+k-1) is the rising
+// factorial, to o's precision and returns o. If o's precision is zero, then it
+// is given the greatest of the precisions of the parameters and z. The working
+// precision grows as needed to compensate for cancellation among the terms.
+// pFq(a; b; 0) = 1.
+//
+// If some a_i is a nonpositive integer -m, the series is a polynomial of
+// degree m, and it is evaluated for any z. Otherwise, the series must
+// converge: p ≤ q, or p = q+1 with |z| < 1. Panics with ErrNaN if the series
+// diverges, if any argument is infinite, or if some b_j is a nonpositive
+// integer -n and the series does not terminate before its pole, i.e. there
+// is no a_i = -m with m < n.
+func HypPFQ(o *big.Float, a, b []*big.Float, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(append(append([]*big.Float{z}, a...), b...)...))
+	}
+	term := hypCheck("HypPFQ", a, b, z)
+	if z.Sign() == 0 {
+		return o.SetInt64(1)
+	}
+	if !term {
+		switch p, q := len(a), len(b); {
+		case p > q+1:
+			panic(ErrNaN{msg: "HypPFQ: series diverges for p > q+1"})
+		case p == q+1 && new(big.Float).Abs(z).Cmp(&gonep) >= 0:
+			panic(ErrNaN{msg: "HypPFQ: series diverges for p = q+1 and |z| ≥ 1"})
+		}
+	}
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		return hypSeries(a, b, z, wp)
+	})
+	return o.Set(r)
+}
+
+// Hyp0F1 sets o to the confluent hypergeometric limit function 0F1(; b; z) to
+// o's precision and returns o. If o's precision is zero, then it is given the
+// greater of the precisions of b and z. Panics with ErrNaN if b is a
+// nonpositive integer or if either argument is infinite.
+func Hyp0F1(o, b, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(b, z))
+	}
+	return HypPFQ(o, nil, []*big.Float{b}, z)
+}
+
+// Hyp1F1 sets o to Kummer's confluent hypergeometric function 1F1(a; b; z) to
+// o's precision and returns o. If o's precision is zero, then it is given the
+// greatest of the precisions of a, b, and z. For z < 0, it uses Kummer's
+// transformation
+//
+//	1F1(a; b; z) = exp(z) 1F1(b-a; b; -z),
+//
+// whose terms do not alternate. Panics with ErrNaN under the same conditions
+// as HypPFQ.
+func Hyp1F1(o, a, b, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(a, b, z))
+	}
+	as, bs := []*big.Float{a}, []*big.Float{b}
+	term := hypCheck("Hyp1F1", as, bs, z)
+	if term || !z.Signbit() {
+		return HypPFQ(o, as, bs, z)
+	}
+	nz := new(big.Float).Neg(z)
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		c := hypSub(b, a, wp)
+		r, mag := hypSeries([]*big.Float{c}, bs, nz, wp)
+		e := expIntExp(z, wp)
+		if e.Sign() == 0 {
+			return e, math.MinInt32
+		}
+		r.Mul(r, e)
+		return r, mag + e.MantExp(nil)
+	})
+	return o.Set(r)
+}
+
+// Hyp2F1 sets o to Gauss's hypergeometric function 2F1(a, b; c; z) to o's
+// precision and returns o. If o's precision is zero, then it is given the
+// greatest of the precisions of a, b, c, and z. Besides the series for
+// |z| < 1, Hyp2F1 evaluates
+//
+//	2F1(a, b; c; 1) = Γ(c) Γ(c-a-b) / (Γ(c-a) Γ(c-b))
+//
+// for c-a-b > 0 and uses the analytic continuation for all z < 1, through the
+// Pfaff transformation for -2 ≤ z < -1/2 and the transformation to 1/z for
+// z < -2. When b-a is an integer, the latter is degenerate, and the Pfaff
+// transformation is used instead, which becomes slow for large negative z.
+// Panics with ErrNaN if z > 1, where 2F1 has a branch cut, if z = 1
+// and c-a-b ≤ 0, or under the same conditions as HypPFQ. If a or b is a
+// nonpositive integer, the result is the polynomial, evaluated for any z.
+func Hyp2F1(o, a, b, c, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(a, b, c, z))
+	}
+	as, cs := []*big.Float{a, b}, []*big.Float{c}
+	term := hypCheck("Hyp2F1", as, cs, z)
+	switch {
+	case term || z.Sign() == 0:
+		return HypPFQ(o, as, cs, z)
+	case z.Cmp(&gonep) > 0:
+		panic(ErrNaN{msg: "Hyp2F1: z is on the branch cut"})
+	case z.Cmp(&gonep) == 0:
+		wp := o.Prec() + 64
+		s := hypSub(hypSub(c, a, wp), b, wp)
+		if s.Sign() <= 0 {
+			panic(ErrNaN{msg: "Hyp2F1: series diverges at z = 1 for c-a-b ≤ 0"})
+		}
+		r := Gamma(new(big.Float).SetPrec(wp), c)
+		r.Mul(r, Gamma(s, s))
+		r.Mul(r, hypRGamma(hypSub(c, a, wp), wp))
+		return o.Mul(r, hypRGamma(hypSub(c, b, wp), wp))
+	}
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		return hyp2F1(a, b, c, z, wp)
+	})
+	return o.Set(r)
+}
+
+// hyp2F1 returns 2F1(a, b; c; z) to precision wp for z < 1 when neither a nor
+// b is a nonpositive integer, along with the binary exponent of the largest
+// quantity in the computation for ziv.
+func hyp2F1(a, b, c, z *big.Float, wp uint) (*big.Float, int) {
+	if z.Cmp(&ghalfm) >= 0 {
+		return hypSeries([]*big.Float{a, b}, []*big.Float{c}, z, wp)
+	}
+	y := new(big.Float).SetPrec(wp+z.Prec()).Sub(&gonep, z)
+	if z.Cmp(new(big.Float).SetInt64(-2)) >= 0 || hypSub(b, a, wp).IsInt() {
+		// Pfaff: 2F1(a, b; c; z) = (1-z)**-a 2F1(a, c-b; c; z/(z-1)). The new
+		// argument is in (1/3, 2/3] for z in [-2, -1/2). If b-a is an
+		// integer, the transformation to 1/z is degenerate, so this is the
+		// fallback for all z, converging more slowly as z decreases.
+		w := new(big.Float).SetPrec(wp).Quo(z, y)
+		w.Neg(w)
+		r, mag := hypSeries([]*big.Float{a, hypSub(c, b, wp)}, []*big.Float{c}, w, wp)
+		f := Pow(new(big.Float).SetPrec(wp), y.SetPrec(wp), new(big.Float).Neg(a))
+		r.Mul(r, f)
+		return r, mag + f.MantExp(nil)
+	}
+	// 2F1(a, b; c; z) = Γ(c) Γ(b-a) / (Γ(b) Γ(c-a)) (-z)**-a 2F1(a, a-c+1; a-b+1; 1/z)
+	//                 + Γ(c) Γ(a-b) / (Γ(a) Γ(c-b)) (-z)**-b 2F1(b, b-c+1; b-a+1; 1/z)
+	// The argument 1/z is in (-1/2, 0).
+	v := new(big.Float).SetPrec(wp).Quo(&gonep, z)
+	nz := new(big.Float).SetPrec(wp).Neg(z)
+	gc := Gamma(new(big.Float).SetPrec(wp), c)
+	one := func(a, b *big.Float) (*big.Float, int) {
+		u := hypSub(a, c, wp)
+		u.Add(u, &gonep)
+		d := hypSub(a, b, wp)
+		d.Add(d, &gonep)
+		r, mag := hypSeries([]*big.Float{a, u}, []*big.Float{d}, v, wp)
+		f := Gamma(new(big.Float).SetPrec(wp), hypSub(b, a, wp))
+		f.Mul(f, gc)
+		f.Mul(f, hypRGamma(b, wp))
+		f.Mul(f, hypRGamma(hypSub(c, a, wp), wp))
+		f.Mul(f, Pow(new(big.Float).SetPrec(wp), nz, new(big.Float).Neg(a)))
+		if f.Sign() == 0 {
+			return f, math.MinInt32
+		}
+		r.Mul(r, f)
+		return r, mag + f.MantExp(nil)
+	}
+	r, mag := one(a, b)
+	s, smag := one(b, a)
+	if smag > mag {
+		mag = smag
+	}
+	return r.Add(r, s), mag
+}
+
+// hypSeries returns the sum of the pFq series to precision wp, along with the
+// binary exponent of the largest term or partial sum. Unlike sumSeries, it
+// allows the terms to shrink and grow again while the rising factorials pass
+// through zero, ending the sum only once the remaining terms are provably
+// small or some factor is exactly zero.
+func hypSeries(a, b []*big.Float, z *big.Float, wp uint) (*big.Float, int) {
+	// Past kmin, every a_i+k and b_j+k is positive, and the ratio of
+	// successive terms is no greater than about ρ below.
+	var kmin float64
+	af := make([]float64, len(a))
+	bf := make([]float64, len(b))
+	for i, x := range a {
+		af[i], _ = x.Float64()
+		kmin = math.Max(kmin, -af[i])
+	}
+	for j, x := range b {
+		bf[j], _ = x.Float64()
+		kmin = math.Max(kmin, -bf[j])
+	}
+	zf, _ := z.Float64()
+	zf = math.Abs(zf)
+
+	t := new(big.Float).SetPrec(wp).SetInt64(1)
+	s := new(big.Float).SetPrec(wp).SetInt64(1)
+	u := new(big.Float).SetPrec(wp)
+	v := new(big.Float)
+	mag := 0
+	// A terminating series may be computed exactly, including when it is
+	// exactly zero, which ziv must not mistake for total cancellation.
+	exact := true
+	for k := int64(1); ; k++ {
+		for _, x := range a {
+			exact = u.Add(x, v.SetInt64(k-1)).Acc() == big.Exact && exact
+			exact = t.Mul(t, u).Acc() == big.Exact && exact
+		}
+		if t.Sign() == 0 {
+			if exact {
+				if s.Sign() == 0 {
+					return s, math.MinInt32
+				}
+				return s, s.MantExp(nil)
+			}
+			return s, mag
+		}
+		for _, x := range b {
+			exact = u.Add(x, v.SetInt64(k-1)).Acc() == big.Exact && exact
+			exact = t.Quo(t, u).Acc() == big.Exact && exact
+		}
+		exact = t.Mul(t, z).Acc() == big.Exact && exact
+		exact = t.Quo(t, v.SetInt64(k)).Acc() == big.Exact && exact
+		exact = s.Add(s, t).Acc() == big.Exact && exact
+		e := t.MantExp(nil)
+		if e > mag {
+			mag = e
+		}
+		if s.Sign() != 0 && s.MantExp(nil) > mag {
+			mag = s.MantExp(nil)
+		}
+		if float64(k) <= kmin+1 || e >= mag-int(wp) {
+			continue
+		}
+		// The rest of the series is at most t ρ/(1-ρ), where ρ bounds the
+		// ratio of successive terms from here on.
+		kf := float64(k)
+		rho := zf / (kf + 1)
+		for _, x := range af {
+			rho *= math.Abs(x + kf)
+		}
+		for _, x := range bf {
+			rho /= math.Abs(x + kf)
+		}
+		if len(a) == len(b)+1 {
+			rho = math.Max(rho, zf)
+		}
+		if rho < 1 && float64(e)+math.Log2(rho/(1-rho)) < float64(mag-int(wp)) {
+			return s, mag
+		}
+	}
+}
+
+// hypCheck panics with ErrNaN, naming the function as name, if any argument
+// is infinite or if some b_j is a nonpositive integer which the series
+// reaches. It reports whether the series terminates.
+func hypCheck(name string, a, b []*big.Float, z *big.Float) bool {
+	if z.IsInf() {
+		panic(ErrNaN{msg: name + ": argument is infinite"})
+	}
+	var m int64 = math.MaxInt64
+	term := false
+	for _, x := range a {
+		if x.IsInf() {
+			panic(ErrNaN{msg: name + ": argument is infinite"})
+		}
+		if x.IsInt() && x.Sign() <= 0 {
+			if n, _ := x.Int64(); -n < m {
+				m = -n
+			}
+			term = true
+		}
+	}
+	for _, x := range b {
+		if x.IsInf() {
+			panic(ErrNaN{msg: name + ": argument is infinite"})
+		}
+		if x.IsInt() && x.Sign() <= 0 {
+			if n, _ := x.Int64(); !term || m >= -n {
+				panic(ErrNaN{msg: name + ": b is a nonpositive integer"})
+			}
+		}
+	}
+	return term
+}
+
+// hypSub returns x-y with enough precision that it is exact for parameters
+// of similar magnitude, and at least wp.
+func hypSub(x, y *big.Float, wp uint) *big.Float {
+	return new(big.Float).SetPrec(wp+x.Prec()+y.Prec()).Sub(x, y)
+}
+
+// hypRGamma returns 1/Γ(x) to precision wp, which is zero at the poles of Γ.
+func hypRGamma(x *big.Float, wp uint) *big.Float {
+	r := new(big.Float).SetPrec(wp)
+	if x.IsInt() && x.Sign() <= 0 {
+		return r
+	}
+	Gamma(r, x)
+	return r.Quo(&gonep, r)
+}
@@ -0,0 +1,220 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestHyp0F1(t *testing.T) {
+	for _, prec := range []uint{53, 200, 1000} {
+		wp := prec + 64
+		for _, xf := range []float64{0.5, 3, 40} {
+			x := new(big.Float).SetPrec(wp).SetFloat64(xf)
+			x2 := new(big.Float).Mul(x, x)
+			s, c := sinCosRef(x, wp)
+			// 0F1(; 1/2; -x²/4) = cos x
+			z := new(big.Float).Quo(x2, big.NewFloat(-4))
+			got := bigfloat.Hyp0F1(new(big.Float).SetPrec(prec), big.NewFloat(0.5), z)
+			if !closeTo(got, c, prec-4) {
+				t.Errorf("0F1(; 1/2; -%g²/4) at prec %d:\ngot  %g\nwant %g", xf, prec, got, c)
+			}
+			// 0F1(; 3/2; -x²/4) = sin(x)/x
+			got = bigfloat.Hyp0F1(new(big.Float).SetPrec(prec), big.NewFloat(1.5), z)
+			s.Quo(s, x)
+			if !closeTo(got, s, prec-4) {
+				t.Errorf("0F1(; 3/2; -%g²/4) at prec %d:\ngot  %g\nwant %g", xf, prec, got, s)
+			}
+		}
+	}
+}
+
+// sinCosRef returns sin x and cos x to precision wp for x in (0, 100) by
+// their Taylor series, summed with generous extra precision.
+func sinCosRef(x *big.Float, wp uint) (s, c *big.Float) {
+	p := wp + 200
+	s = new(big.Float).SetPrec(p)
+	c = new(big.Float).SetPrec(p)
+	t := new(big.Float).SetPrec(p).SetInt64(1)
+	for k := int64(0); k < 2000; k++ {
+		if k%4 == 0 {
+			c.Add(c, t)
+		} else if k%4 == 1 {
+			s.Add(s, t)
+		} else if k%4 == 2 {
+			c.Sub(c, t)
+		} else {
+			s.Sub(s, t)
+		}
+		t.Mul(t, x)
+		t.Quo(t, new(big.Float).SetInt64(k+1))
+	}
+	return s.SetPrec(wp), c.SetPrec(wp)
+}
+
+func TestHyp1F1(t *testing.T) {
+	for _, prec := range []uint{53, 200, 1000} {
+		wp := prec + 64
+		for _, zf := range []float64{-200, -10, -0.5, 0.5, 10, 200} {
+			z := new(big.Float).SetPrec(wp).SetFloat64(zf)
+			e := bigfloat.Exp(new(big.Float).SetPrec(wp), z)
+			// 1F1(a; a; z) = exp(z)
+			got := bigfloat.Hyp1F1(new(big.Float).SetPrec(prec), big.NewFloat(2.5), big.NewFloat(2.5), z)
+			if !closeTo(got, e, prec-4) {
+				t.Errorf("1F1(5/2; 5/2; %g) at prec %d:\ngot  %g\nwant %g", zf, prec, got, e)
+			}
+			// 1F1(1; 2; z) = (exp(z) - 1)/z
+			want := new(big.Float).Sub(e, big.NewFloat(1))
+			want.Quo(want, z)
+			got = bigfloat.Hyp1F1(new(big.Float).SetPrec(prec), big.NewFloat(1), big.NewFloat(2), z)
+			if !closeTo(got, want, prec-4) {
+				t.Errorf("1F1(1; 2; %g) at prec %d:\ngot  %g\nwant %g", zf, prec, got, want)
+			}
+		}
+		// 1F1(1/2; 3/2; -x²) = √π erf(x)/2x
+		x := new(big.Float).SetPrec(wp).SetInt64(3)
+		want := bigfloat.Erf(new(big.Float).SetPrec(wp), x)
+		want.Mul(want, new(big.Float).Sqrt(bigfloat.Pi(new(big.Float).SetPrec(wp))))
+		want.Quo(want, big.NewFloat(6))
+		got := bigfloat.Hyp1F1(new(big.Float).SetPrec(prec), big.NewFloat(0.5), big.NewFloat(1.5), big.NewFloat(-9))
+		if !closeTo(got, want, prec-4) {
+			t.Errorf("1F1(1/2; 3/2; -9) at prec %d:\ngot  %g\nwant %g", prec, got, want)
+		}
+	}
+}
+
+func TestHyp2F1(t *testing.T) {
+	for _, prec := range []uint{53, 200, 1000} {
+		wp := prec + 64
+		one := big.NewFloat(1)
+		for _, zf := range []float64{-1e6, -30, -5, -1.5, -0.75, -0.1, 0.3, 0.9} {
+			z := new(big.Float).SetPrec(wp).SetFloat64(zf)
+			// 2F1(1, 1; 2; z) = -log(1-z)/z. Since b-a is an integer, this
+			// takes the slow path for very negative z.
+			if zf >= -30 {
+				want := bigfloat.Log(new(big.Float).SetPrec(wp), new(big.Float).Sub(one, z))
+				want.Quo(want, z)
+				want.Neg(want)
+				got := bigfloat.Hyp2F1(new(big.Float).SetPrec(prec), one, one, big.NewFloat(2), z)
+				if !closeTo(got, want, prec-4) {
+					t.Errorf("2F1(1, 1; 2; %g) at prec %d:\ngot  %g\nwant %g", zf, prec, got, want)
+				}
+			}
+			// 2F1(a, b; b; z) = (1-z)**-a
+			want := bigfloat.Pow(new(big.Float).SetPrec(wp), new(big.Float).Sub(one, z), big.NewFloat(-0.75))
+			got := bigfloat.Hyp2F1(new(big.Float).SetPrec(prec), big.NewFloat(0.75), big.NewFloat(2.25), big.NewFloat(2.25), z)
+			if !closeTo(got, want, prec-4) {
+				t.Errorf("2F1(3/4, 9/4; 9/4; %g) at prec %d:\ngot  %g\nwant %g", zf, prec, got, want)
+			}
+		}
+		// 2F1(1/2, 1/2; 3/2; 1) = π/2
+		want := bigfloat.Pi(new(big.Float).SetPrec(wp))
+		want.Quo(want, big.NewFloat(2))
+		got := bigfloat.Hyp2F1(new(big.Float).SetPrec(prec), big.NewFloat(0.5), big.NewFloat(0.5), big.NewFloat(1.5), one)
+		if !closeTo(got, want, prec-4) {
+			t.Errorf("2F1(1/2, 1/2; 3/2; 1) at prec %d:\ngot  %g\nwant %g", prec, got, want)
+		}
+	}
+}
+
+func TestHypPFQ(t *testing.T) {
+	f := func(x ...float64) []*big.Float {
+		r := make([]*big.Float, len(x))
+		for i, v := range x {
+			r[i] = big.NewFloat(v)
+		}
+		return r
+	}
+	for _, test := range []struct {
+		a, b []*big.Float
+		z    float64
+		want string
+	}{
+		// 3F2(1, 1, 1; 2, 2; z) = Li_2(z)/z
+		{f(1, 1, 1), f(2, 2), 0.5, "1.16448105293002501180531264031936021748839694961225285086869409574634"},
+		// Terminating: 3F2(-2, 3, 1/2; 4, 5/2; 7) = 1 - 21/10 + 63/25
+		{f(-2, 3, 0.5), f(4, 2.5), 7, "1.42"},
+		// 2F1(-1, 2; 1; 1/2) = 0 exactly
+		{f(-1, 2), f(1), 0.5, "0"},
+		// A pole in b past the end of the series: 2F1(-2, 1; -3; 1) = 1 + 2/3 + 1/3
+		{f(-2, 1), f(-3), 1, "2"},
+	} {
+		want := parse(test.want, 256)
+		got := bigfloat.HypPFQ(new(big.Float).SetPrec(190), test.a, test.b, big.NewFloat(test.z))
+		if !closeTo(got, want, 186) {
+			t.Errorf("HypPFQ(%v; %v; %g):\ngot  %g\nwant %g", test.a, test.b, test.z, got, want)
+		}
+	}
+}
+
+func TestHypPrecision(t *testing.T) {
+	for _, c := range [][4]float64{{0.5, -1.25, 3.5, 0.8}, {-2.5, 1, 0.3, -0.6}, {3, 4.5, 2.25, -12}, {1.5, 2, 5.5, -40}} {
+		a, b, cc, z := big.NewFloat(c[0]), big.NewFloat(c[1]), big.NewFloat(c[2]), big.NewFloat(c[3])
+		want := bigfloat.Hyp2F1(new(big.Float).SetPrec(600), a, b, cc, z)
+		want1 := bigfloat.Hyp1F1(new(big.Float).SetPrec(600), a, cc, new(big.Float).Mul(z, big.NewFloat(10)))
+		for _, prec := range []uint{53, 200} {
+			got := bigfloat.Hyp2F1(new(big.Float).SetPrec(prec), a, b, cc, z)
+			if !closeTo(got, want, prec-2) {
+				t.Errorf("2F1(%g, %g; %g; %g) at prec %d:\ngot  %g\nwant %g", c[0], c[1], c[2], c[3], prec, got, want)
+			}
+			got = bigfloat.Hyp1F1(new(big.Float).SetPrec(prec), a, cc, new(big.Float).Mul(z, big.NewFloat(10)))
+			if !closeTo(got, want1, prec-2) {
+				t.Errorf("1F1(%g; %g; %g) at prec %d:\ngot  %g\nwant %g", c[0], c[2], 10*c[3], prec, got, want1)
+			}
+		}
+	}
+}
+
+func TestHypSpecialValues(t *testing.T) {
+	x64, acc := bigfloat.HypPFQ(new(big.Float).SetPrec(53), []*big.Float{big.NewFloat(2)}, nil, big.NewFloat(0)).Float64()
+	if x64 != 1 || acc != big.Exact {
+		t.Errorf("1F0(2;; 0) = %g (%s), want 1 (Exact)", x64, acc)
+	}
+	inf := math.Inf(1)
+	for _, test := range []struct {
+		name string
+		f    func()
+	}{
+		{"1F0(2;; 1/2)", func() {
+			bigfloat.HypPFQ(new(big.Float), []*big.Float{big.NewFloat(2), big.NewFloat(1)}, nil, big.NewFloat(0.5))
+		}},
+		{"2F1(1/2, 1; 2; 1)", func() {
+			bigfloat.HypPFQ(new(big.Float), []*big.Float{big.NewFloat(0.5), big.NewFloat(1)}, []*big.Float{big.NewFloat(2)}, big.NewFloat(1))
+		}},
+		{"1F1(1; -2; 1)", func() {
+			bigfloat.Hyp1F1(new(big.Float), big.NewFloat(1), big.NewFloat(-2), big.NewFloat(1))
+		}},
+		{"1F1(-3; -2; 1)", func() {
+			bigfloat.Hyp1F1(new(big.Float), big.NewFloat(-3), big.NewFloat(-2), big.NewFloat(1))
+		}},
+		{"0F1(; 1; Inf)", func() {
+			bigfloat.Hyp0F1(new(big.Float), big.NewFloat(1), big.NewFloat(inf))
+		}},
+		{"2F1(1, 1; 2; 2)", func() {
+			bigfloat.Hyp2F1(new(big.Float), big.NewFloat(1), big.NewFloat(1), big.NewFloat(2), big.NewFloat(2))
+		}},
+		{"2F1(1, 1; 2; 1)", func() {
+			bigfloat.Hyp2F1(new(big.Float), big.NewFloat(1), big.NewFloat(1), big.NewFloat(2), big.NewFloat(1))
+		}},
+	} {
+		expectNaN(t, test.name, test.f)
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkHyp2F1(b *testing.B) {
+	p, q, c, z := big.NewFloat(0.5), big.NewFloat(1.25), big.NewFloat(2.5), big.NewFloat(-3)
+	for _, prec := range []uint{1e2, 1e3} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Hyp2F1(o, p, q, c, z)
+			}
+		})
+	}
+}
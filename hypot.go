@@ -0,0 +1,92 @@
+package bigfloat
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Hypot sets o to √(x² + y²) rounded to o's precision according to o's
+// rounding mode and returns o. The squares are never formed at a rounded
+// precision, so the result is correctly rounded regardless of the magnitudes
+// of x and y. If o's precision is zero, then it is given the greater of the
+// precisions of x and y. If either of x or y is infinite, then o is set to
+// +Inf.
+func Hypot(o, x, y *big.Float) *big.Float {
+	return Norm(o, []*big.Float{x, y})
+}
+
+// Norm sets o to the Euclidean norm √(Σ x²) of xs rounded to o's precision
+// according to o's rounding mode and returns o. If o's precision is zero, then
+// it is given the greatest of the precisions of the elements of xs. If any
+// element is infinite, then o is set to +Inf. The norm of an empty vector is
+// +0. o may be an element of xs.
+func Norm(o *big.Float, xs []*big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(xs...))
+	}
+	emax, xprec := 0, uint(0)
+	nz := make([]*big.Float, 0, len(xs))
+	for _, x := range xs {
+		switch {
+		case x.IsInf():
+			return o.SetInf(false)
+		case x.Sign() == 0:
+			continue
+		}
+		if e := x.MantExp(nil); len(nz) == 0 || e > emax {
+			emax = e
+		}
+		if x.Prec() > xprec {
+			xprec = x.Prec()
+		}
+		nz = append(nz, x)
+	}
+	if len(nz) == 0 {
+		return o.SetInt64(0)
+	}
+	// Scale the squares by 2**c so that their sum F is an integer-sized
+	// value in [2**(2p+4), n·2**(2p+6)). Then the integer square root of
+	// ⌊F⌋ has at least p+2 bits, and along with whether F is a perfect
+	// square, it determines the rounding of √F in every mode.
+	p := o.Prec()
+	c := 2*int64(p) + 6 - 2*int64(emax)
+	nb := uint(bits.Len(uint(len(nz))))
+	m := new(big.Float)
+	// Squares below 2**-g are left out of the exact sum A. Their total D is
+	// less than n·2**-g, so ⌊F⌋ = ⌊A⌋ unless the fraction of A is within
+	// that of 1, in which case g grows to include more of them.
+	for g := int64(64); ; g *= 2 {
+		a := new(big.Float).SetPrec(2*p + 2*xprec + uint(g) + nb + 12)
+		dropped := false
+		for _, x := range nz {
+			e := x.MantExp(m)
+			k := 2*int64(e) + c
+			if k <= -g {
+				dropped = true
+				continue
+			}
+			t := new(big.Float).SetPrec(2*m.Prec()).Mul(m, m)
+			a.Add(a, t.SetMantExp(t, int(k)))
+		}
+		n, _ := a.Int(nil)
+		frac := a.Sub(a, new(big.Float).SetInt(n))
+		if dropped {
+			lim := new(big.Float).SetPrec(uint(g) + nb).SetInt64(int64(len(nz)))
+			lim.SetMantExp(lim, -int(g))
+			lim.Sub(&gonep, lim)
+			if frac.Cmp(lim) >= 0 {
+				continue
+			}
+		}
+		q := new(big.Int).Sqrt(n)
+		exp := -int(c / 2)
+		if dropped || frac.Sign() != 0 || new(big.Int).Mul(q, q).Cmp(n) != 0 {
+			// √F is strictly between q and q+1, so q+½ rounds the same.
+			q.Lsh(q, 1)
+			q.Add(q, intOne)
+			exp--
+		}
+		o.SetInt(q)
+		return o.SetMantExp(o, exp)
+	}
+}
@@ -0,0 +1,193 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+// refNorm returns the norm of the finite xs rounded to prec bits under mode,
+// using exact rational arithmetic.
+func refNorm(xs []*big.Float, prec uint, mode big.RoundingMode) *big.Float {
+	s := new(big.Rat)
+	for _, x := range xs {
+		r := ratOf(x)
+		s.Add(s, r.Mul(r, r))
+	}
+	if s.Sign() == 0 {
+		return new(big.Float).SetPrec(prec)
+	}
+	sq := func(x *big.Float) *big.Rat {
+		r := ratOf(x)
+		return r.Mul(r, r)
+	}
+	// Find lo ≤ √s < hi with adjacent lo and hi at prec bits.
+	v := new(big.Float).SetPrec(2*prec + 64).SetRat(s)
+	v.Sqrt(v)
+	lo := new(big.Float).SetPrec(prec).SetMode(big.ToZero).Set(v)
+	for sq(lo).Cmp(s) > 0 {
+		bigfloat.NextDown(lo, lo)
+	}
+	for sq(bigfloat.NextUp(new(big.Float), lo)).Cmp(s) <= 0 {
+		bigfloat.NextUp(lo, lo)
+	}
+	if sq(lo).Cmp(s) == 0 {
+		return lo
+	}
+	// Any value on the same side of the midpoint as √s rounds the same way.
+	hi := bigfloat.NextUp(new(big.Float), lo)
+	mid := new(big.Rat).Add(ratOf(lo), ratOf(hi))
+	mid.Quo(mid, big.NewRat(2, 1))
+	var w *big.Rat
+	switch c := new(big.Rat).Mul(mid, mid).Cmp(s); {
+	case c > 0:
+		w = new(big.Rat).Add(ratOf(lo), mid)
+	case c < 0:
+		w = new(big.Rat).Add(mid, ratOf(hi))
+	default:
+		w = new(big.Rat).Add(mid, mid)
+	}
+	w.Quo(w, big.NewRat(2, 1))
+	return new(big.Float).SetPrec(prec).SetMode(mode).SetRat(w)
+}
+
+func TestHypotExhaustive(t *testing.T) {
+	xs := smallFloats()
+	for _, mode := range allModes {
+		for _, x := range xs {
+			for _, y := range xs {
+				want := refNorm([]*big.Float{x, y}, 3, mode)
+				got := bigfloat.Hypot(new(big.Float).SetPrec(3).SetMode(mode), x, y)
+				if got.Cmp(want) != 0 {
+					t.Errorf("Hypot(%g, %g) under %v = %g, want %g", x, y, mode, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestHypotFloat64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		x := math.Ldexp(r.Float64()-0.5, r.Intn(2000)-1000)
+		y := math.Ldexp(r.Float64()-0.5, r.Intn(2000)-1000)
+		got, _ := bigfloat.Hypot(new(big.Float).SetPrec(53), big.NewFloat(x), big.NewFloat(y)).Float64()
+		want := math.Hypot(x, y)
+		if math.Abs(got-want) > want*0x1p-52 {
+			t.Errorf("Hypot(%g, %g) = %g, want %g", x, y, got, want)
+		}
+	}
+}
+
+func TestNorm(t *testing.T) {
+	pow2 := func(k int) *big.Float { return new(big.Float).SetMantExp(big.NewFloat(1), k) }
+	// Exact results, including a tie at 2 bits: 5 is between 4 and 6.
+	cases := []struct {
+		xs   []*big.Float
+		prec uint
+		want *big.Float
+	}{
+		{[]*big.Float{big.NewFloat(3), big.NewFloat(-4)}, 53, big.NewFloat(5)},
+		{[]*big.Float{big.NewFloat(3), big.NewFloat(4)}, 2, big.NewFloat(4)},
+		{[]*big.Float{big.NewFloat(2), big.NewFloat(3), big.NewFloat(6)}, 3, big.NewFloat(7)},
+		{[]*big.Float{pow2(big.MaxExp - 2), pow2(big.MaxExp - 2), pow2(big.MaxExp - 2), pow2(big.MaxExp - 2)}, 10, pow2(big.MaxExp - 1)},
+		{[]*big.Float{pow2(big.MinExp + 2), pow2(big.MinExp + 2)}, 1, pow2(big.MinExp + 2)},
+	}
+	for _, c := range cases {
+		got := bigfloat.Norm(new(big.Float).SetPrec(c.prec), c.xs)
+		if got.Cmp(c.want) != 0 {
+			t.Errorf("Norm(%v) at prec %d = %v, want %v", c.xs, c.prec, got.Text('p', 0), c.want.Text('p', 0))
+		}
+	}
+	ones := make([]*big.Float, 100)
+	for i := range ones {
+		ones[i] = big.NewFloat(1)
+	}
+	if got := bigfloat.Norm(new(big.Float), ones); got.Prec() != 53 || got.Cmp(big.NewFloat(10)) != 0 {
+		t.Errorf("Norm of 100 ones = %g with precision %d, want 10 with precision 53", got, got.Prec())
+	}
+	// Components too small to appear in the result still decide directed
+	// rounding.
+	xs := []*big.Float{big.NewFloat(1), pow2(-1000000)}
+	up := new(big.Float).SetPrec(53).SetMode(big.ToPositiveInf)
+	if got := bigfloat.Norm(up, xs); got.Cmp(bigfloat.NextUp(new(big.Float), big.NewFloat(1))) != 0 {
+		t.Errorf("Norm(1, 2**-1000000) rounding up = %g, want 1 + 2**-52", got)
+	}
+	if got := bigfloat.Norm(new(big.Float).SetPrec(53), xs); got.Cmp(big.NewFloat(1)) != 0 {
+		t.Errorf("Norm(1, 2**-1000000) = %g, want 1", got)
+	}
+	// The sum of the larger squares is just below an integer at the scale
+	// of the result, so the tiny component must be included to round it.
+	x := new(big.Float).SetPrec(80).Sub(big.NewFloat(1), pow2(-70))
+	x.SetMantExp(x, -4)
+	xs = []*big.Float{big.NewFloat(1), x, pow2(-200)}
+	for _, mode := range allModes {
+		got := bigfloat.Norm(new(big.Float).SetPrec(2).SetMode(mode), xs)
+		if want := refNorm(xs, 2, mode); got.Cmp(want) != 0 {
+			t.Errorf("Norm(1, (1-2**-70)/16, 2**-200) under %v = %g, want %g", mode, got, want)
+		}
+	}
+	// High precision against the reference.
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 20; i++ {
+		xs := make([]*big.Float, 1+r.Intn(8))
+		for j := range xs {
+			xs[j] = new(big.Float).SetPrec(uint(1 + r.Intn(300))).SetFloat64(r.NormFloat64())
+			xs[j].SetMantExp(xs[j], r.Intn(200)-100)
+		}
+		mode := allModes[r.Intn(len(allModes))]
+		got := bigfloat.Norm(new(big.Float).SetPrec(500).SetMode(mode), xs)
+		if want := refNorm(xs, 500, mode); got.Cmp(want) != 0 {
+			t.Errorf("Norm(%v) under %v = %g, want %g", xs, mode, got, want)
+		}
+	}
+	// Aliasing.
+	a := big.NewFloat(5)
+	if got := bigfloat.Hypot(a, a, big.NewFloat(12)); got.Cmp(big.NewFloat(13)) != 0 {
+		t.Errorf("Hypot(5, 12) in place = %g, want 13", got)
+	}
+}
+
+func TestNormSpecialValues(t *testing.T) {
+	inf := big.NewFloat(math.Inf(1))
+	ninf := big.NewFloat(math.Inf(-1))
+	if got := bigfloat.Hypot(new(big.Float), ninf, new(big.Float)); !got.IsInf() || got.Signbit() {
+		t.Errorf("Hypot(-Inf, 0) = %g, want +Inf", got)
+	}
+	if got := bigfloat.Norm(new(big.Float), []*big.Float{big.NewFloat(1), inf, ninf}); !got.IsInf() || got.Signbit() {
+		t.Errorf("Norm(1, +Inf, -Inf) = %g, want +Inf", got)
+	}
+	nzero := new(big.Float).Neg(new(big.Float).SetPrec(53))
+	if got := bigfloat.Hypot(new(big.Float), nzero, nzero); got.Sign() != 0 || got.Signbit() {
+		t.Errorf("Hypot(-0, -0) = %g, want +0", got)
+	}
+	if got := bigfloat.Norm(new(big.Float), nil); got.Sign() != 0 || got.Signbit() {
+		t.Errorf("Norm() = %g, want +0", got)
+	}
+	if got := bigfloat.Hypot(new(big.Float), big.NewFloat(-3), new(big.Float)); got.Cmp(big.NewFloat(3)) != 0 {
+		t.Errorf("Hypot(-3, 0) = %g, want 3", got)
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkNorm(b *testing.B) {
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		xs := make([]*big.Float, 10)
+		for i := range xs {
+			xs[i] = new(big.Float).SetPrec(prec).SetInt64(int64(i + 1))
+			xs[i].Quo(bigfloat.Pi(new(big.Float).SetPrec(prec)), xs[i])
+		}
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Norm(o, xs)
+			}
+		})
+	}
+}
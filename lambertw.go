@@ -0,0 +1,221 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// LambertW0 sets o to the principal branch W₀(z) of the Lambert W function,
+// the solution w ≥ -1 of w exp(w) = z, to o's precision and returns o. If o's
+// precision is zero, then it is given the precision of z. LambertW0(±0) = ±0
+// and LambertW0(+Inf) = +Inf. The result has full precision even for z close
+// to the branch point at -1/e, where W₀(z) approaches -1. Panics with ErrNaN if
+// z < -1/e.
+func LambertW0(o, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	switch {
+	case z.Sign() == 0:
+		return o.Set(z)
+	case z.IsInf():
+		if z.Signbit() {
+			panic(ErrNaN{msg: "LambertW0: argument is less than -1/e"})
+		}
+		return o.Set(z)
+	}
+	return lambertW(o, z, false)
+}
+
+// LambertWm1 sets o to the lower branch W₋₁(z) of the Lambert W function, the
+// solution w ≤ -1 of w exp(w) = z for -1/e ≤ z < 0, to o's precision and
+// returns o. If o's precision is zero, then it is given the precision of z.
+// LambertWm1(±0) = -Inf. The result has full precision even for z close to
+// the branch point at -1/e, where W₋₁(z) approaches -1. Panics with ErrNaN if
+// z < -1/e or z > 0.
+func LambertWm1(o, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	switch {
+	case z.Sign() == 0:
+		return o.SetInf(true)
+	case z.Sign() > 0 || z.IsInf():
+		panic(ErrNaN{msg: "LambertWm1: argument is out of range"})
+	}
+	return lambertW(o, z, true)
+}
+
+// lambertW sets o to W₀(z), or W₋₁(z) if lower is true, for finite, nonzero z
+// in the domain of the branch.
+func lambertW(o, z *big.Float, lower bool) *big.Float {
+	prec := o.Prec()
+	wp := prec + 64
+	q := branchDist(z, wp)
+	if q.Signbit() {
+		panic(ErrNaN{msg: "LambertW: argument is less than -1/e"})
+	}
+	if q.Sign() == 0 {
+		return o.SetInt64(-1)
+	}
+	if q.Cmp(big.NewFloat(0.25)) < 0 {
+		return o.Set(lambertWBranch(wp, q, lower))
+	}
+
+	// Away from the branch point, solve the equivalent
+	//    f(w) = w + log|w| - log|z| = 0,
+	// for which
+	//    f(w)/f'(w) = (w + log|w| - log|z|) w / (w + 1).
+	// The absolute error in log|z| grows with the exponent of z.
+	e := z.MantExp(nil)
+	if e < 0 {
+		e = -e
+	}
+	wp += uint(bits.Len(uint(e)))
+	lz := Log(new(big.Float).SetPrec(wp), new(big.Float).Abs(z))
+
+	var guess *big.Float
+	if !lower && z.MantExp(nil) < -60 {
+		// W₀(z) = z - z² + O(z³)
+		guess = new(big.Float).SetPrec(wp).Mul(z, z)
+		guess.Sub(z, guess)
+		if c := uint(-3 * z.MantExp(nil)); c < 2*wp {
+			guess.SetPrec(c)
+		}
+	} else {
+		lf, _ := lz.Float64()
+		zf, _ := z.Float64()
+		guess = big.NewFloat(lambertWGuess(zf, lf, lower)).SetPrec(48)
+	}
+	f := func(w *big.Float) *big.Float {
+		p := w.Prec()
+		t := new(big.Float).SetPrec(p).Abs(w)
+		Log(t, t)
+		t.Add(t, w)
+		t.Sub(t, lz)
+		t.Mul(t, w)
+		u := new(big.Float).SetPrec(p).Add(w, &gonep)
+		return t.Quo(t, u)
+	}
+	return o.Set(newton(f, guess, wp))
+}
+
+// branchDist returns e z + 1 with full relative precision, to at least
+// precision wp.
+func branchDist(z *big.Float, wp uint) *big.Float {
+	// e z + 1 can be much smaller than either term, but only by about as
+	// many bits as z has, since 1/e is irrational.
+	p := wp + z.Prec() + 64
+	for {
+		q := new(big.Float).SetPrec(p).Set(ConstE.Value(new(big.Float).SetPrec(p)))
+		q.Mul(q, z)
+		q.Add(q, &gonep)
+		if q.Sign() == 0 || q.MantExp(nil) >= int(wp)-int(p)+32 {
+			return q
+		}
+		p *= 2
+	}
+}
+
+// lambertWBranch returns W₀(z), or W₋₁(z) if lower is true, to precision wp,
+// where q = e z + 1 is in (0, 1/4).
+func lambertWBranch(wp uint, q *big.Float, lower bool) *big.Float {
+	// Write w = u - 1. Then w exp(w) = z becomes
+	//    h(u) = 1 - (1-u) exp(u) = q,
+	// which we solve for u, with
+	//    (h(u) - q) / h'(u) = (h(u) - q) / (u exp(u)).
+	// Since u is small, computing it rather than w avoids the loss of
+	// precision near the branch point. Starting from the series
+	//    u = p - p²/3 + 11p³/72 - ...,
+	// where p = ±√(2q), the first omitted term gives the accuracy of the
+	// guess.
+	p := quicksh(new(big.Float), q, 1).SetPrec(wp)
+	p.Sqrt(p)
+	if lower {
+		p.Neg(p)
+	}
+	guess := new(big.Float).SetPrec(wp).SetRat(big.NewRat(11, 72))
+	guess.Mul(guess, p)
+	guess.Sub(guess, new(big.Float).SetPrec(wp).SetRat(big.NewRat(1, 3)))
+	guess.Mul(guess, p)
+	guess.Add(guess, &gonep)
+	guess.Mul(guess, p)
+	// The next term is 43p⁴/540, so the guess has about 3 log₂(1/p) + 3
+	// bits correct.
+	c := 3 - 3*p.MantExp(nil)
+	if c > int(2*wp) {
+		c = int(2 * wp)
+	}
+	guess.SetPrec(uint(c))
+
+	f := func(u *big.Float) *big.Float {
+		prec := u.Prec()
+		h := lambertH(new(big.Float).SetPrec(prec), u)
+		h.Sub(h, q)
+		d := new(big.Float).SetPrec(prec).Set(u)
+		Exp(d, d)
+		d.Mul(d, u)
+		return h.Quo(h, d)
+	}
+	u := newton(f, guess, wp)
+	return u.Sub(u, &gonep)
+}
+
+// lambertH sets o to h(u) = 1 - (1-u) exp(u) for |u| ≤ 1 to o's precision
+// using the series
+//
+//	h(u) = Σ (k-1) u**k / k!, k ≥ 2,
+//
+// and returns o.
+func lambertH(o, u *big.Float) *big.Float {
+	wp := o.Prec() + 16
+	t := new(big.Float).SetPrec(wp).Mul(u, u)
+	quicksh(t, t, -1)
+	s := new(big.Float).SetPrec(wp).Set(t)
+	v := new(big.Float).SetPrec(wp)
+	for k := int64(3); ; k++ {
+		// t = u**k / k!
+		t.Mul(t, u)
+		t.Quo(t, v.SetInt64(k))
+		v.Mul(t, v.SetInt64(k-1))
+		s.Add(s, v)
+		if v.Sign() == 0 || v.MantExp(nil) < s.MantExp(nil)-int(wp) {
+			break
+		}
+	}
+	return o.Set(s)
+}
+
+// lambertWGuess returns an approximation of W₀(z), or W₋₁(z) if lower is
+// true, good to about 48 bits, given z and lz = log|z| in float64. z may be
+// zero or infinite if it is outside the range of float64, in which case it is
+// assumed to be large on W₀ and tiny on W₋₁.
+func lambertWGuess(z, lz float64, lower bool) float64 {
+	if !lower && lz < 1 {
+		// Halley's method on w exp(w) - z. Here -1/e + 1/(4e) < z < e and
+		// W₀(z) is in (-0.72, 1).
+		w := math.Log1p(z)
+		for i := 0; i < 100; i++ {
+			ew := math.Exp(w)
+			f := w*ew - z
+			d := f / (ew*(w+1) - (w+2)*f/(2*w+2))
+			w -= d
+			if math.Abs(d) <= 1e-16*math.Abs(w) {
+				break
+			}
+		}
+		return w
+	}
+	// Newton's method on w + log|w| - lz, starting from the first terms of
+	// the asymptotic expansion.
+	w := lz - math.Log(math.Abs(lz))
+	for i := 0; i < 100; i++ {
+		d := (w + math.Log(math.Abs(w)) - lz) * w / (w + 1)
+		w -= d
+		if math.Abs(d) <= 1e-16*math.Abs(w) {
+			break
+		}
+	}
+	return w
+}
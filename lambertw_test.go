@@ -0,0 +1,166 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+const omegaStr = "0.56714329040978387299996866221035554975381578718651250813513107922304579308668456669321944696364"
+
+func TestLambertWValues(t *testing.T) {
+	const prec = 300
+	e := bigfloat.Exp(new(big.Float), new(big.Float).SetPrec(prec+64).SetInt64(1))
+	for _, test := range []struct {
+		name string
+		f    func(o, z *big.Float) *big.Float
+		z    *big.Float
+		want *big.Float
+		prec uint
+	}{
+		{"LambertW0", bigfloat.LambertW0, big.NewFloat(1), parse(omegaStr, 330), 300},
+		{"LambertW0", bigfloat.LambertW0, e, big.NewFloat(1), prec},
+		// W₀(-log(2)/2) = -log(2), W₋₁(-log(2)/2) = -2 log(2)
+		{"LambertW0", bigfloat.LambertW0, new(big.Float).Quo(bigfloat.Log(new(big.Float).SetPrec(prec+64), big.NewFloat(0.5)), big.NewFloat(2)), bigfloat.Log(new(big.Float).SetPrec(prec+64), big.NewFloat(0.5)), prec},
+		{"LambertWm1", bigfloat.LambertWm1, new(big.Float).Quo(bigfloat.Log(new(big.Float).SetPrec(prec+64), big.NewFloat(0.5)), big.NewFloat(2)), bigfloat.Log(new(big.Float).SetPrec(prec+64), big.NewFloat(0.25)), prec},
+		// W₋₁(-2 exp(-2)) = -2
+		{"LambertWm1", bigfloat.LambertWm1, new(big.Float).Mul(big.NewFloat(-2), bigfloat.Exp(new(big.Float), new(big.Float).SetPrec(prec+64).SetInt64(-2))), big.NewFloat(-2), prec},
+	} {
+		got := test.f(new(big.Float).SetPrec(test.prec), test.z)
+		if !closeTo(got, test.want, test.prec-2) {
+			t.Errorf("%s(%g) =\ngot  %g;\nwant %g", test.name, test.z, got, test.want)
+		}
+	}
+}
+
+func TestLambertWRoundTrip(t *testing.T) {
+	huge := new(big.Float).SetMantExp(big.NewFloat(1), 100000)
+	tiny := new(big.Float).SetMantExp(big.NewFloat(-1), -100000)
+	for _, prec := range []uint{24, 53, 200, 1000} {
+		for _, test := range []struct {
+			name string
+			f    func(o, z *big.Float) *big.Float
+			z    *big.Float
+		}{
+			{"LambertW0", bigfloat.LambertW0, big.NewFloat(1e-30)},
+			{"LambertW0", bigfloat.LambertW0, big.NewFloat(0.125)},
+			{"LambertW0", bigfloat.LambertW0, big.NewFloat(10)},
+			{"LambertW0", bigfloat.LambertW0, big.NewFloat(1e300)},
+			{"LambertW0", bigfloat.LambertW0, huge},
+			{"LambertW0", bigfloat.LambertW0, tiny},
+			{"LambertW0", bigfloat.LambertW0, big.NewFloat(-0.1)},
+			{"LambertW0", bigfloat.LambertW0, big.NewFloat(-0.3)},
+			{"LambertW0", bigfloat.LambertW0, big.NewFloat(-0.36)},
+			{"LambertWm1", bigfloat.LambertWm1, big.NewFloat(-0.36)},
+			{"LambertWm1", bigfloat.LambertWm1, big.NewFloat(-0.3)},
+			{"LambertWm1", bigfloat.LambertWm1, big.NewFloat(-0.1)},
+			{"LambertWm1", bigfloat.LambertWm1, big.NewFloat(-1e-30)},
+			{"LambertWm1", bigfloat.LambertWm1, tiny},
+		} {
+			w := test.f(new(big.Float).SetPrec(prec), test.z)
+			// w exp(w) = z
+			got := new(big.Float).SetPrec(prec + 64).Set(w)
+			bigfloat.Exp(got, got)
+			got.Mul(got, w)
+			// An error of one ulp in w moves w exp(w) by about w+1 ulps.
+			e := w.MantExp(nil)
+			if e < 0 {
+				e = 0
+			}
+			if !closeTo(got, test.z, prec-uint(e)-4) {
+				t.Errorf("prec = %d: %s(%g) = %g; w exp(w) = %g", prec, test.name, test.z, w, got)
+			}
+		}
+	}
+}
+
+func TestLambertWBranchPoint(t *testing.T) {
+	// Near -1/e, W(z) + 1 is about ±√(2(e z + 1)), so z must be much more
+	// precise than the result for the result to be far from -1. Compare
+	// results at different precisions, including for z on either side of
+	// the switch to the expansion about the branch point.
+	e := bigfloat.Exp(new(big.Float), new(big.Float).SetPrec(2000).SetInt64(1))
+	for _, d := range []int{-3, -10, -100, -500} {
+		// z = (2**d - 1) / e
+		z := new(big.Float).SetPrec(2000).SetInt64(1)
+		z.Sub(z.SetMantExp(z, d), big.NewFloat(1))
+		z.Quo(z, e)
+		for _, f := range []struct {
+			name string
+			f    func(o, z *big.Float) *big.Float
+		}{
+			{"LambertW0", bigfloat.LambertW0},
+			{"LambertWm1", bigfloat.LambertWm1},
+		} {
+			want := f.f(new(big.Float).SetPrec(1000), z)
+			for _, prec := range []uint{24, 53, 100, 300} {
+				got := f.f(new(big.Float).SetPrec(prec), z)
+				if !closeTo(got, want, prec-2) {
+					t.Errorf("prec = %d: %s(%g) =\ngot  %g;\nwant %g", prec, f.name, z, got, want)
+				}
+			}
+			// W(z) + 1 carries the information near the branch point.
+			got := f.f(new(big.Float).SetPrec(1000), z)
+			got.Add(got, big.NewFloat(1))
+			got.Mul(got, got)
+			got.Quo(got, new(big.Float).SetMantExp(big.NewFloat(2), d))
+			if !closeTo(got, big.NewFloat(1), 1) {
+				t.Errorf("(%s(%g) + 1)² / 2(e z + 1) = %g, want 1", f.name, z, got)
+			}
+		}
+	}
+}
+
+func TestLambertWSpecialValues(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		f    func(o, z *big.Float) *big.Float
+		z    float64
+		want float64
+	}{
+		{"LambertW0", bigfloat.LambertW0, 0, 0},
+		{"LambertW0", bigfloat.LambertW0, math.Copysign(0, -1), math.Copysign(0, -1)},
+		{"LambertW0", bigfloat.LambertW0, math.Inf(1), math.Inf(1)},
+		{"LambertWm1", bigfloat.LambertWm1, 0, math.Inf(-1)},
+		{"LambertWm1", bigfloat.LambertWm1, math.Copysign(0, -1), math.Inf(-1)},
+	} {
+		x64, acc := test.f(new(big.Float), big.NewFloat(test.z)).Float64()
+		if x64 != test.want || math.Signbit(x64) != math.Signbit(test.want) || acc != big.Exact {
+			t.Errorf("%s(%g) =\n got %g (%s);\nwant %g (Exact)", test.name, test.z, x64, acc, test.want)
+		}
+	}
+	for _, test := range []struct {
+		name string
+		f    func(o, z *big.Float) *big.Float
+		z    float64
+	}{
+		{"LambertW0", bigfloat.LambertW0, -0.5},
+		{"LambertW0", bigfloat.LambertW0, math.Inf(-1)},
+		{"LambertWm1", bigfloat.LambertWm1, -0.5},
+		{"LambertWm1", bigfloat.LambertWm1, 1},
+		{"LambertWm1", bigfloat.LambertWm1, math.Inf(-1)},
+		{"LambertWm1", bigfloat.LambertWm1, math.Inf(1)},
+	} {
+		expectNaN(t, fmt.Sprintf("%s(%g)", test.name, test.z), func() {
+			test.f(new(big.Float), big.NewFloat(test.z))
+		})
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkLambertW0(b *testing.B) {
+	z := big.NewFloat(1)
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.LambertW0(o, z)
+			}
+		})
+	}
+}
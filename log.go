@@ -68,7 +68,10 @@ func Log(o, z *big.Float) *big.Float {
 	// where prec is the desired precision (in bits)
 	pi := cachedPi(prec)
 	agm := AGM(new(big.Float), one, o.Quo(four, o)) // agm = AGM(1, 4/z)
-	o.Quo(pi, o.Mul(two, agm))
+	// The cached π may be much more precise than we need. Round it to o's
+	// precision first so that the division doesn't use its entire mantissa.
+	agm.Mul(two, agm)
+	o.Set(pi).Quo(o, agm)
 
 	if neg {
 		o.Neg(o)
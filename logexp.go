@@ -0,0 +1,168 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// LogAddExp sets o to log(exp(a) + exp(b)) to o's precision and returns o,
+// without forming exp(a) or exp(b), so that it is accurate for log-domain
+// values far outside the range of float64. If o's precision is zero, then it
+// is given the greater of the precisions of a and b. LogAddExp(a, -Inf) = a,
+// and the result is +Inf if either of a or b is +Inf.
+func LogAddExp(o, a, b *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(a, b))
+	}
+	return logSumExp(o, []*big.Float{a, b})
+}
+
+// LogSumExp sets o to log(Σ exp(x)) over xs to o's precision and returns o,
+// without forming any exp(x), so that it is accurate for log-domain values
+// far outside the range of float64. If o's precision is zero, then it is given
+// the greatest of the precisions of the elements of xs. Elements that are -Inf
+// contribute nothing; the result is -Inf if there are no other elements and
+// +Inf if any element is +Inf. o may be an element of xs.
+func LogSumExp(o *big.Float, xs []*big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(xs...))
+	}
+	return logSumExp(o, xs)
+}
+
+// LogSubExp sets o to log(exp(a) - exp(b)) to o's precision and returns o,
+// without forming exp(a) or exp(b). The result is accurate even when a and b
+// are nearly equal. If o's precision is zero, then it is given the greater of
+// the precisions of a and b. LogSubExp(a, -Inf) = a and LogSubExp(a, a) =
+// -Inf. Panics with ErrNaN if b > a or if a and b are both +Inf.
+func LogSubExp(o, a, b *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(a, b))
+	}
+	switch c := a.Cmp(b); {
+	case a.IsInf() && b.IsInf() && !a.Signbit() && !b.Signbit():
+		panic(ErrNaN{msg: "LogSubExp: difference of infinities"})
+	case c < 0:
+		panic(ErrNaN{msg: "LogSubExp: b is greater than a"})
+	case c == 0:
+		return o.SetInf(true)
+	case b.IsInf() || a.IsInf():
+		return o.Set(a)
+	}
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		// log(exp(a) - exp(b)) = a + log(1 - exp(-δ)) with δ = a - b > 0.
+		// exp(-δ) needs extra bits to give 1 - exp(-δ) in full when δ is
+		// small.
+		p := wp + 64
+		if e := a.MantExp(nil); e > 0 {
+			p += uint(e)
+		}
+		d := new(big.Float).SetPrec(p).Sub(b, a)
+		if e := d.MantExp(nil); e < 0 {
+			p += uint(-e)
+		}
+		u := Exp(new(big.Float).SetPrec(p), d)
+		var l *big.Float
+		if u.Cmp(&ghalfp) >= 0 {
+			l = Log(new(big.Float).SetPrec(wp), u.Sub(&gonep, u))
+		} else {
+			l = logExpLog1p(u.Neg(u), wp)
+		}
+		return logExpAdd(a, l, wp)
+	})
+	return o.Set(r)
+}
+
+// logSumExp performs LogSumExp once o has its precision.
+func logSumExp(o *big.Float, xs []*big.Float) *big.Float {
+	var m *big.Float
+	fin := make([]*big.Float, 0, len(xs))
+	for _, x := range xs {
+		switch {
+		case x.IsInf() && !x.Signbit():
+			return o.SetInf(false)
+		case x.IsInf():
+			continue
+		}
+		if m == nil || x.Cmp(m) > 0 {
+			m = x
+		}
+		fin = append(fin, x)
+	}
+	switch len(fin) {
+	case 0:
+		return o.SetInf(true)
+	case 1:
+		return o.Set(fin[0])
+	}
+	nb := uint(bits.Len(uint(len(fin))))
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		// log Σ exp(x) = m + log1p(t) where t = Σ exp(x - m) over all but
+		// one of the maximal terms. Terms much smaller than the largest
+		// in t are below its precision.
+		p := wp + nb + 64
+		if e := m.MantExp(nil); e > 0 {
+			p += uint(e)
+		}
+		d := make([]*big.Float, 0, len(fin)-1)
+		var d2 *big.Float
+		skipped := false
+		for _, x := range fin {
+			if x == m && !skipped {
+				skipped = true
+				continue
+			}
+			v := new(big.Float).SetPrec(p).Sub(x, m)
+			if d2 == nil || v.Cmp(d2) > 0 {
+				d2 = v
+			}
+			d = append(d, v)
+		}
+		lim := new(big.Float).SetPrec(p).SetFloat64(-float64(wp+nb+4) * math.Ln2)
+		lim.Add(lim, d2)
+		t := new(big.Float).SetPrec(p)
+		for _, v := range d {
+			if v.Cmp(lim) >= 0 {
+				t.Add(t, Exp(new(big.Float).SetPrec(p), v))
+			}
+		}
+		if t.Sign() == 0 {
+			// Every other term underflows.
+			if m.Sign() == 0 {
+				return new(big.Float), math.MinInt32
+			}
+			return new(big.Float).SetPrec(wp).Set(m), m.MantExp(nil)
+		}
+		return logExpAdd(m, logExpLog1p(t, wp), wp)
+	})
+	return o.Set(r)
+}
+
+// logExpAdd returns m + l to precision wp along with the exponent of the
+// larger of them, for ziv.
+func logExpAdd(m, l *big.Float, wp uint) (*big.Float, int) {
+	mag := l.MantExp(nil)
+	if m.Sign() != 0 && m.MantExp(nil) > mag {
+		mag = m.MantExp(nil)
+	}
+	return new(big.Float).SetPrec(wp).Add(m, l), mag + 1
+}
+
+// logExpLog1p returns log(1 + t) to precision wp for t > -1/2, with full
+// relative precision when t is small.
+func logExpLog1p(t *big.Float, wp uint) *big.Float {
+	e := t.MantExp(nil)
+	if e < -int(wp) {
+		// log(1 + t) = t - t²/2 + ..., and t²/3 is below the precision.
+		h := new(big.Float).SetMantExp(t, -1)
+		h.SetPrec(wp).Sub(&gonep, h)
+		return h.Mul(h, t)
+	}
+	p := wp
+	if e < 0 {
+		p += uint(-e)
+	}
+	u := new(big.Float).SetPrec(p).Add(&gonep, t)
+	return Log(new(big.Float).SetPrec(p), u).SetPrec(wp)
+}
@@ -0,0 +1,162 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestLogAddExpFloat64(t *testing.T) {
+	vals := []float64{0, 1, -1, 0.5, -3, 10, -20, 100, -700, 1e-10}
+	for _, a := range vals {
+		for _, b := range vals {
+			got, _ := bigfloat.LogAddExp(new(big.Float).SetPrec(53), big.NewFloat(a), big.NewFloat(b)).Float64()
+			m := math.Max(a, b)
+			want := m + math.Log(math.Exp(a-m)+math.Exp(b-m))
+			if math.Abs(got-want) > 1e-14*math.Max(1, math.Abs(want)) {
+				t.Errorf("LogAddExp(%g, %g) = %g, want %g", a, b, got, want)
+			}
+			if a > b {
+				got, _ := bigfloat.LogSubExp(new(big.Float).SetPrec(53), big.NewFloat(a), big.NewFloat(b)).Float64()
+				want := a + math.Log(-math.Expm1(b-a))
+				if math.Abs(got-want) > 1e-14*math.Max(1, math.Abs(want)) {
+					t.Errorf("LogSubExp(%g, %g) = %g, want %g", a, b, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestLogSumExp(t *testing.T) {
+	const prec = 500
+	ln2 := bigfloat.Log(new(big.Float).SetPrec(prec), big.NewFloat(2))
+	// Far below the range of float64: log(2 exp(x)) = x + log 2.
+	x := parse("-1e50", prec)
+	got := bigfloat.LogAddExp(new(big.Float).SetPrec(prec), x, x)
+	if want := new(big.Float).SetPrec(prec).Add(x, ln2); !closeTo(got, want, prec-4) {
+		t.Errorf("LogAddExp(-1e50, -1e50) = %g, want %g", got, want)
+	}
+	// n equal terms.
+	xs := make([]*big.Float, 10)
+	for i := range xs {
+		xs[i] = parse("-123456.789", prec)
+	}
+	got = bigfloat.LogSumExp(new(big.Float).SetPrec(prec), xs)
+	want := bigfloat.Log(new(big.Float).SetPrec(prec), big.NewFloat(10))
+	want.Add(want, xs[0])
+	if !closeTo(got, want, prec-4) {
+		t.Errorf("LogSumExp(10 × -123456.789) = %g, want %g", got, want)
+	}
+	// A result near zero: log(1 + exp(-1000)) ≈ exp(-1000), to full
+	// relative precision.
+	got = bigfloat.LogAddExp(new(big.Float).SetPrec(prec), big.NewFloat(-1000), new(big.Float))
+	e := bigfloat.Exp(new(big.Float).SetPrec(prec+64), big.NewFloat(-1000))
+	want = new(big.Float).SetPrec(prec+64).Mul(e, e)
+	want.SetMantExp(want, -1)
+	want.Sub(e, want)
+	if !closeTo(got, want, prec-4) {
+		t.Errorf("LogAddExp(-1000, 0) = %g, want %g", got, want)
+	}
+	// Cancellation between the maximum and the logarithm:
+	// log(exp(log ½ - ε) + ½) = -ε/2 + O(ε²).
+	h := new(big.Float).SetPrec(prec).Neg(ln2)
+	a := new(big.Float).SetPrec(prec).Sub(h, parse("1e-100", prec))
+	got = bigfloat.LogAddExp(new(big.Float).SetPrec(100), a, h)
+	if want := parse("-5e-101", 100); !closeTo(got, want, 90) {
+		t.Errorf("LogAddExp(log ½ - 1e-100, log ½) = %g, want %g", got, want)
+	}
+	// Widely separated terms leave the largest alone.
+	xs = []*big.Float{big.NewFloat(5), parse("-1e30", 100), big.NewFloat(math.Inf(-1))}
+	if got := bigfloat.LogSumExp(new(big.Float).SetPrec(100), xs); got.Cmp(big.NewFloat(5)) != 0 {
+		t.Errorf("LogSumExp(5, -1e30, -Inf) = %g, want 5", got)
+	}
+	// Precision defaults to the greatest of the arguments'.
+	xs = []*big.Float{big.NewFloat(1), parse("2", 200)}
+	if got := bigfloat.LogSumExp(new(big.Float), xs); got.Prec() != 200 {
+		t.Errorf("LogSumExp(1, 2) has precision %d, want 200", got.Prec())
+	}
+	// Aliasing.
+	b := big.NewFloat(0)
+	if got, _ := bigfloat.LogAddExp(b, b, b).Float64(); got != math.Ln2 {
+		t.Errorf("LogAddExp(0, 0) in place = %g, want %g", got, math.Ln2)
+	}
+}
+
+func TestLogSubExp(t *testing.T) {
+	const prec = 300
+	// Nearly equal arguments: log(exp(a) - exp(a - δ)) = a + log(1 - exp(-δ))
+	// ≈ a + log δ - δ/2.
+	a := parse("1e20", prec)
+	d := new(big.Float).SetMantExp(big.NewFloat(1), -200)
+	b := new(big.Float).SetPrec(prec).Sub(a, d)
+	got := bigfloat.LogSubExp(new(big.Float).SetPrec(prec), a, b)
+	want := bigfloat.Log(new(big.Float).SetPrec(prec+64), d)
+	want.Add(want, a)
+	want.Sub(want, new(big.Float).SetMantExp(d, -1))
+	if !closeTo(got, want, prec-4) {
+		t.Errorf("LogSubExp(1e20, 1e20 - 2**-200) = %g, want %g", got, want)
+	}
+	// Identity with LogAddExp.
+	x, y := parse("-7777.25", prec), parse("-7790.5", prec)
+	s := bigfloat.LogAddExp(new(big.Float).SetPrec(prec+32), x, y)
+	if got := bigfloat.LogSubExp(new(big.Float).SetPrec(prec), s, y); !closeTo(got, x, prec-8) {
+		t.Errorf("LogSubExp(LogAddExp(x, y), y) = %g, want %g", got, x)
+	}
+}
+
+func TestLogExpSpecialValues(t *testing.T) {
+	inf := big.NewFloat(math.Inf(1))
+	ninf := big.NewFloat(math.Inf(-1))
+	three := big.NewFloat(3)
+	if got := bigfloat.LogAddExp(new(big.Float), three, ninf); got.Cmp(three) != 0 {
+		t.Errorf("LogAddExp(3, -Inf) = %g, want 3", got)
+	}
+	if got := bigfloat.LogAddExp(new(big.Float), ninf, ninf); !got.IsInf() || !got.Signbit() {
+		t.Errorf("LogAddExp(-Inf, -Inf) = %g, want -Inf", got)
+	}
+	if got := bigfloat.LogAddExp(new(big.Float), inf, ninf); !got.IsInf() || got.Signbit() {
+		t.Errorf("LogAddExp(+Inf, -Inf) = %g, want +Inf", got)
+	}
+	if got := bigfloat.LogSumExp(new(big.Float), nil); !got.IsInf() || !got.Signbit() {
+		t.Errorf("LogSumExp() = %g, want -Inf", got)
+	}
+	if got := bigfloat.LogSumExp(new(big.Float), []*big.Float{ninf, three, ninf}); got.Cmp(three) != 0 {
+		t.Errorf("LogSumExp(-Inf, 3, -Inf) = %g, want 3", got)
+	}
+	if got := bigfloat.LogSubExp(new(big.Float), three, ninf); got.Cmp(three) != 0 {
+		t.Errorf("LogSubExp(3, -Inf) = %g, want 3", got)
+	}
+	if got := bigfloat.LogSubExp(new(big.Float), three, three); !got.IsInf() || !got.Signbit() {
+		t.Errorf("LogSubExp(3, 3) = %g, want -Inf", got)
+	}
+	if got := bigfloat.LogSubExp(new(big.Float), ninf, ninf); !got.IsInf() || !got.Signbit() {
+		t.Errorf("LogSubExp(-Inf, -Inf) = %g, want -Inf", got)
+	}
+	if got := bigfloat.LogSubExp(new(big.Float), inf, three); !got.IsInf() || got.Signbit() {
+		t.Errorf("LogSubExp(+Inf, 3) = %g, want +Inf", got)
+	}
+	expectNaN(t, "LogSubExp(+Inf, +Inf)", func() { bigfloat.LogSubExp(new(big.Float), inf, inf) })
+	expectNaN(t, "LogSubExp(1, 3)", func() { bigfloat.LogSubExp(new(big.Float), big.NewFloat(1), three) })
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkLogSumExp(b *testing.B) {
+	for _, prec := range []uint{1e2, 1e3} {
+		xs := make([]*big.Float, 10)
+		for i := range xs {
+			xs[i] = new(big.Float).SetPrec(prec).SetInt64(int64(-1000 * i))
+			xs[i].Quo(xs[i], bigfloat.Pi(new(big.Float).SetPrec(prec)))
+		}
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.LogSumExp(o, xs)
+			}
+		})
+	}
+}
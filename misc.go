@@ -2,8 +2,6 @@ package bigfloat
 
 import (
 	"math/big"
-	"sync"
-	"sync/atomic"
 )
 
 // AGM sets o to the limit of the arithmetic-geometric mean progression of a
@@ -91,7 +89,7 @@ func Round(o, z *big.Float, mode big.RoundingMode) *big.Float {
 	// z has a nonzero integer part. Give o exactly enough precision to
 	// represent that integer part, then set it to z and restore its precision.
 	// But first, check that o actually needs to shrink to do this.
-	if o.Prec() <= uint(exp) {
+	if o.Prec() < uint(exp) {
 		return o.Set(z)
 	}
 	defer o.SetMode(o.Mode())
@@ -128,90 +126,27 @@ func round0away(o, z *big.Float) *big.Float {
 	return o.Set(&gzero)
 }
 
-var piCache atomic.Value
-var enablePiCache bool = true
-var piMu sync.Mutex // writers only
-
-func init() {
-	if !enablePiCache {
-		return
-	}
-	pi, _, err := new(big.Float).SetPrec(1024).Parse("3."+
-		"14159265358979323846264338327950288419716939937510"+
-		"58209749445923078164062862089986280348253421170679"+
-		"82148086513282306647093844609550582231725359408128"+
-		"48111745028410270193852110555964462294895493038196"+
-		"44288109756659334461284756482337867831652712019091"+
-		"45648566923460348610454326648213393607260249141273"+
-		"72458700660631558817488152092096282925409171536444", 10)
-	if err != nil {
-		panic(err)
-	}
-	piCache.Store(pi)
-}
-
-// loadPi returns the current cached pi value. It may panic if enablePiCache is
-// false. Use cachedPi or Pi instead; this is just a convenience function for
-// those safe wrappers.
-func loadPi() *big.Float {
-	return piCache.Load().(*big.Float)
-}
-
 // cachedPi returns the cached pi value with at least prec precision. If the pi
 // cache is enabled and has a precision of at least prec, then this does not
 // allocate. The returned value must not be modified. It is safe to call this
 // concurrently.
 func cachedPi(prec uint) *big.Float {
-	if !enablePiCache {
-		return Pi(new(big.Float).SetPrec(prec))
-	}
-	pi := piCache.Load().(*big.Float)
-	if pi.Prec() >= prec {
-		return pi
-	}
-
-	// The current cached value doesn't have enough precision. Calculate a new
-	// pi value.
-	piMu.Lock()
-	defer piMu.Unlock()
-	// It's possible another goroutine obtained a more precise pi value while
-	// we were locking piMu. Re-check the cached value.
-	pi = piCache.Load().(*big.Float)
-	if pi.Prec() >= prec {
-		return pi
-	}
-	pi = piCalc(new(big.Float).SetPrec(prec))
-	piCache.Store(pi)
-	return pi
+	return ConstPi.cached(prec)
 }
 
 // Pi sets a to π to a's precision (even if a's precision is zero) and
 // returns a.
 func Pi(a *big.Float) *big.Float {
-	prec := a.Prec()
-	if prec == 0 {
-		// Zero-precision floats represent only ±0 or ±inf.
-		return a.Set(&gzero)
-	}
-	if enablePiCache {
-		pi := loadPi()
-		if prec <= pi.Prec() {
-			return a.Set(pi)
-		}
-	}
-	piCalc(a)
-	if enablePiCache {
-		piMu.Lock()
-		defer piMu.Unlock()
-		if loadPi().Prec() < prec {
-			piCache.Store(new(big.Float).Copy(a))
-		}
-	}
-	return a
+	return ConstPi.Value(a)
 }
 
 // piCalc performs the actual computation to obtain a value for π.
 func piCalc(a *big.Float) *big.Float {
+	return piChudnovsky(a)
+}
+
+// piAGM computes π to a's precision using the Brent–Salamin AGM iteration.
+func piAGM(a *big.Float) *big.Float {
 	prec := a.Prec()
 
 	// Following R. P. Brent, Multiple-precision zero-finding
@@ -250,13 +185,34 @@ func piCalc(a *big.Float) *big.Float {
 // fOverDf needs to be a fuction returning f(t)/f'(t).
 // t must not be changed by fOverDf.
 // guess is the initial guess (and it's not preserved).
+// guess's precision is taken as the number of bits it has correct, and the
+// working precision doubles along with that number at each step. If a step
+// shows that the guess was less accurate than that, the count is reduced to
+// match, so a poor guess costs iterations rather than accuracy. Rather than
+// return an inaccurate result, newton panics if it does not converge within
+// newtonMaxIter steps.
 func newton(fOverDf func(z *big.Float) *big.Float, guess *big.Float, dPrec uint) *big.Float {
 
 	prec, guard := guess.Prec(), uint(64)
 	guess.SetPrec(prec + guard)
 
-	for prec < 2*dPrec {
-		guess.Sub(guess, fOverDf(guess))
+	for i := 0; prec < 2*dPrec; i++ {
+		if i == newtonMaxIter {
+			panic("bigfloat: newton: no convergence")
+		}
+		d := fOverDf(guess)
+		guess.Sub(guess, d)
+		// A correct step is about 2**-prec relative to the guess. If it is
+		// much larger, the guess had only as many bits correct as the step
+		// shows, and the new one has about twice that many.
+		if d.Sign() != 0 && guess.Sign() != 0 {
+			if c := guess.MantExp(nil) - d.MantExp(nil); c < int(prec)-2 {
+				prec = 1
+				if c > 1 {
+					prec = uint(c)
+				}
+			}
+		}
 		prec *= 2
 		guess.SetPrec(prec + guard)
 	}
@@ -264,6 +220,21 @@ func newton(fOverDf func(z *big.Float) *big.Float, guess *big.Float, dPrec uint)
 	return guess.SetPrec(dPrec)
 }
 
+// newtonMaxIter bounds the number of steps newton takes, in case it fails to
+// converge.
+const newtonMaxIter = 200
+
+// greatestPrec returns the greatest of the precisions of xs.
+func greatestPrec(xs ...*big.Float) uint {
+	var p uint
+	for _, x := range xs {
+		if x.Prec() > p {
+			p = x.Prec()
+		}
+	}
+	return p
+}
+
 // quicksh efficiently multiplies z by 2**n and sets o to the result. o's
 // precision and rounding mode are overwritten.
 func quicksh(o, z *big.Float, n int) *big.Float {
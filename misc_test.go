@@ -41,7 +41,7 @@ func TestAGM(t *testing.T) {
 }
 
 func TestPi(t *testing.T) {
-	enablePiCache = false
+	enableConstCache = false
 	piStr := "3.1415926535897932384626433832795028841971693993751058209749445923078164062862089986280348253421170679821480865132823066470938446095505822317253594081284811174502841027019385211055596446229489549303819644288109756659334461284756482337867831652712019091456485669234603486104543266482133936072602491412737245870066063155881748815209209628292540917153644"
 	for _, prec := range []uint{24, 53, 64, 100, 200, 300, 400, 500, 600, 700, 800, 900, 1000} {
 
@@ -54,19 +54,19 @@ func TestPi(t *testing.T) {
 			t.Errorf("Pi(%d) =\ngot  %g;\nwant %g", prec, z, want)
 		}
 	}
-	enablePiCache = true
+	enableConstCache = true
 }
 
 func TestPiConcurrent(t *testing.T) {
-	if !enablePiCache {
+	if !enableConstCache {
 		t.SkipNow()
 	}
 	const piStr = "3.1415926535897932384626433832795028841971693993751058209749445923078164062862089986280348253421170679821480865132823066470938446095505822317253594081284811174502841027019385211055596446229489549303819644288109756659334461284756482337867831652712019091456485669234603486104543266482133936072602491412737245870066063155881748815209209628292540917153644"
 	// The pi cache starts at a precision of 1024, so to make this test more
 	// meaningful, we'll cheat and set it to a zero-precision value.
-	cached := loadPi()
-	piCache.Store(new(big.Float))
-	defer piCache.Store(cached)
+	cached := ConstPi.load()
+	caches[ConstPi].Store(new(big.Float))
+	defer caches[ConstPi].Store(cached)
 	cases := []uint{24, 53, 64, 100, 200, 300, 400, 500, 600, 700, 800, 900, 1000}
 	const procs = 100
 	var wg sync.WaitGroup
@@ -87,6 +87,21 @@ func TestPiConcurrent(t *testing.T) {
 	wg.Wait()
 }
 
+func TestNewtonNoConvergence(t *testing.T) {
+	// x² + 1 has no real roots, so the iteration wanders forever.
+	f := func(x *big.Float) *big.Float {
+		d := new(big.Float).SetPrec(x.Prec()).Mul(x, x)
+		d.Add(d, &gonep)
+		return d.Quo(d, new(big.Float).Add(x, x))
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("newton on x² + 1 returned without converging")
+		}
+	}()
+	newton(f, big.NewFloat(0.5), 100)
+}
+
 func TestRound(t *testing.T) {
 	cases := []struct {
 		o, z *big.Float
@@ -285,6 +300,20 @@ func TestRound(t *testing.T) {
 				big.ToPositiveInf: big.NewFloat(4),
 			},
 		},
+		{
+			// o has exactly enough precision for the integer part, so the
+			// rounding must still follow mode rather than o's mode.
+			o: new(big.Float).SetPrec(2), op: 2,
+			z: big.NewFloat(3.5),
+			r: [...]*big.Float{
+				big.ToNearestEven: big.NewFloat(4),
+				big.ToNearestAway: big.NewFloat(4),
+				big.ToZero:        big.NewFloat(3),
+				big.AwayFromZero:  big.NewFloat(4),
+				big.ToNegativeInf: big.NewFloat(3),
+				big.ToPositiveInf: big.NewFloat(4),
+			},
+		},
 	}
 	modes := []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
 	for _, c := range cases {
@@ -319,7 +348,7 @@ func BenchmarkAGM(b *testing.B) {
 }
 
 func BenchmarkPi(b *testing.B) {
-	enablePiCache = false
+	enableConstCache = false
 	p := new(big.Float)
 	for _, prec := range []uint{1e2, 1e3, 1e4, 1e5} {
 		p.SetPrec(prec)
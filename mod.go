@@ -0,0 +1,165 @@
+package bigfloat
+
+import "math/big"
+
+// FMod sets o to the remainder of z/w truncated toward zero, z - Trunc(z/w)·w,
+// like C's fmod and math.Mod, and returns o. The result has the sign of z and
+// magnitude less than |w|. If o's precision is zero, then it is given the
+// greater of the precisions of z and w, with which the result is exact.
+// Otherwise, it is rounded according to o's rounding mode. FMod(±0, w) = ±0,
+// and FMod(z, ±Inf) = z for finite z. Panics with ErrNaN if z is infinite or
+// w is zero.
+//
+// The result is computed exactly however large the quotient z/w is, unlike
+// z - Trunc(z/w)·w, which loses all precision once z/w outgrows it.
+func FMod(o, z, w *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(z, w))
+	}
+	if z.IsInf() {
+		panic(ErrNaN{msg: "FMod: dividend is infinite"})
+	}
+	if w.Sign() == 0 {
+		panic(ErrNaN{msg: "FMod: divisor is zero"})
+	}
+	if z.Sign() == 0 || w.IsInf() || cmpAbs(z, w) < 0 {
+		return o.Set(z)
+	}
+	r, _, e := remInt(z, w, 0)
+	return setIntExp(o, r, e, z.Signbit())
+}
+
+// Mod sets o to the remainder of z/w floored toward -Inf, z - Floor(z/w)·w,
+// like Python's % operator, and returns o. The result has the sign of w and
+// magnitude less than |w|. If o's precision is zero, then it is given the
+// greater of the precisions of z and w. Unlike FMod, the result is not always
+// representable at that precision, so it is rounded according to o's rounding
+// mode, once. Mod(±0, w) is zero with the sign of w. Mod(z, ±Inf) is z for
+// finite z with the same sign as w, or w for z with the opposite sign. Panics
+// with ErrNaN if z is infinite or w is zero.
+func Mod(o, z, w *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(z, w))
+	}
+	if z.IsInf() {
+		panic(ErrNaN{msg: "Mod: dividend is infinite"})
+	}
+	if w.Sign() == 0 {
+		panic(ErrNaN{msg: "Mod: divisor is zero"})
+	}
+	if z.Sign() == 0 {
+		return signedZero(o, w)
+	}
+	if z.Signbit() == w.Signbit() {
+		return FMod(o, z, w)
+	}
+	if w.IsInf() {
+		return o.Set(w)
+	}
+	// The truncated remainder, exactly, and then w added to it with one
+	// rounding.
+	var r *big.Float
+	if cmpAbs(z, w) < 0 {
+		r = z
+	} else {
+		n, _, e := remInt(z, w, 0)
+		if n.Sign() == 0 {
+			return signedZero(o, w)
+		}
+		r = setIntExp(new(big.Float), n, e, z.Signbit())
+	}
+	return o.Add(r, w)
+}
+
+// Remainder sets o to the IEEE 754 remainder of z/w, z - n·w where n is the
+// integer nearest to z/w with ties to even, like math.Remainder, and returns
+// o. The result has magnitude at most |w|/2. If o's precision is zero, then it
+// is given the greater of the precisions of z and w, with which the result is
+// exact. Otherwise, it is rounded according to o's rounding mode. A zero
+// result has the sign of z. Remainder(z, ±Inf) = z for finite z. Panics with
+// ErrNaN if z is infinite or w is zero.
+func Remainder(o, z, w *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(z, w))
+	}
+	if z.IsInf() {
+		panic(ErrNaN{msg: "Remainder: dividend is infinite"})
+	}
+	if w.Sign() == 0 {
+		panic(ErrNaN{msg: "Remainder: divisor is zero"})
+	}
+	if z.Sign() == 0 || w.IsInf() {
+		return o.Set(z)
+	}
+	// If |z| ≤ |w|/2, then n = 0. This also keeps remInt from scaling w by a
+	// large power of 2 when z is tiny.
+	if cmpAbs(quicksh(new(big.Float), z, 1), w) <= 0 {
+		return o.Set(z)
+	}
+	// r = |z| mod 2|w| has the parity of the quotient.
+	r, wu, e := remInt(z, w, 1)
+	odd := r.Cmp(wu) >= 0
+	if odd {
+		r.Sub(r, wu)
+	}
+	// Now r = |z| mod |w|. Round the quotient up if r is more than half of
+	// |w|, or exactly half and the quotient is odd.
+	h := new(big.Int).Lsh(r, 1)
+	if c := h.Cmp(wu); c > 0 || c == 0 && odd {
+		r.Sub(r, wu)
+	}
+	return setIntExp(o, r, e, z.Signbit())
+}
+
+// remInt returns |z| mod 2**k·|w| for finite nonzero z and w with |z| ≥ |w|/2
+// as r·2**e, along with |w| = wu·2**e.
+func remInt(z, w *big.Float, k uint) (r, wu *big.Int, e int) {
+	zi, ez := intMantExp(z)
+	wu, ew := intMantExp(w)
+	m := new(big.Int)
+	if ez >= ew {
+		// |z| = zi·2**(ez-ew) in units of 2**ew, which may be an enormous
+		// number; reduce the power of 2 modulo m instead.
+		m.Lsh(wu, k)
+		p := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(ez-ew)), m)
+		r = zi.Mod(zi, m)
+		r.Mul(r, p)
+		return r.Mod(r, m), wu, ew
+	}
+	// Since |z| ≥ |w|/2, the shift is at most about z's precision.
+	wu.Lsh(wu, uint(ew-ez))
+	m.Lsh(wu, k)
+	return zi.Mod(zi, m), wu, ez
+}
+
+// intMantExp returns the integer m ≥ 0 and the exponent e such that
+// |x| = m·2**e, for finite x. m has no more bits than x's precision.
+func intMantExp(x *big.Float) (*big.Int, int) {
+	m := new(big.Float)
+	exp := x.MantExp(m)
+	p := int(x.MinPrec())
+	mi, _ := m.SetMantExp(m, p).Int(nil)
+	return mi.Abs(mi), exp - p
+}
+
+// setIntExp sets o to ±r·2**e, negated if neg, and returns o. A zero result
+// is signed according to neg.
+func setIntExp(o *big.Float, r *big.Int, e int, neg bool) *big.Float {
+	if r.Sign() == 0 {
+		o.SetInt64(0)
+	} else {
+		// SetMantExp takes the precision of its argument, so scale r
+		// exactly before rounding it into o.
+		t := new(big.Float).SetInt(r)
+		o.Set(t.SetMantExp(t, e))
+	}
+	if neg {
+		o.Neg(o)
+	}
+	return o
+}
+
+// cmpAbs compares |x| and |y|, returning -1, 0, or +1 like Cmp.
+func cmpAbs(x, y *big.Float) int {
+	return new(big.Float).Abs(x).Cmp(new(big.Float).Abs(y))
+}
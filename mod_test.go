@@ -0,0 +1,153 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestFModFloat64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	cases := [][2]float64{
+		{5, 3}, {-5, 3}, {5, -3}, {-5, -3}, {6, 3}, {-6, 3}, {0.75, 0.5},
+		{1e300, 3}, {1e300, 1e-300}, {-1e-300, 1e300}, {7, 0.1}, {1, 1},
+		{math.MaxFloat64, math.SmallestNonzeroFloat64}, {5e-324, 1},
+	}
+	for i := 0; i < 200; i++ {
+		z := math.Ldexp(r.Float64()-0.5, r.Intn(400)-200)
+		w := math.Ldexp(r.Float64()-0.5, r.Intn(400)-200)
+		cases = append(cases, [2]float64{z, w})
+	}
+	for _, c := range cases {
+		z, w := big.NewFloat(c[0]), big.NewFloat(c[1])
+		want := math.Mod(c[0], c[1])
+		got, _ := bigfloat.FMod(new(big.Float), z, w).Float64()
+		if got != want || math.Signbit(got) != math.Signbit(want) {
+			t.Errorf("FMod(%g, %g) = %g, want %g", c[0], c[1], got, want)
+		}
+		// Mod is the floored remainder; math.Mod is exact, so adding w
+		// rounds once, as Mod must.
+		if want != 0 && math.Signbit(want) != math.Signbit(c[1]) {
+			want += c[1]
+		}
+		if want == 0 {
+			want = math.Copysign(0, c[1])
+		}
+		got, _ = bigfloat.Mod(new(big.Float), z, w).Float64()
+		if got != want || math.Signbit(got) != math.Signbit(want) {
+			t.Errorf("Mod(%g, %g) = %g, want %g", c[0], c[1], got, want)
+		}
+		want = math.Remainder(c[0], c[1])
+		got, _ = bigfloat.Remainder(new(big.Float), z, w).Float64()
+		if got != want || math.Signbit(got) != math.Signbit(want) {
+			t.Errorf("Remainder(%g, %g) = %g, want %g", c[0], c[1], got, want)
+		}
+	}
+}
+
+func TestRemainderTies(t *testing.T) {
+	for _, c := range [][3]float64{
+		{5, 2, 1}, {7, 2, -1}, {-7, 2, 1}, {6, 4, -2}, {10, 4, 2}, {-10, 4, -2},
+		{2.5, 1, 0.5}, {3.5, 1, -0.5}, {-0.75, 0.5, 0.25}, {1, 4, 1}, {2, 4, 2}, {3, 4, -1},
+	} {
+		got, _ := bigfloat.Remainder(new(big.Float), big.NewFloat(c[0]), big.NewFloat(c[1])).Float64()
+		if got != c[2] {
+			t.Errorf("Remainder(%g, %g) = %g, want %g", c[0], c[1], got, c[2])
+		}
+	}
+}
+
+func TestModLargeQuotient(t *testing.T) {
+	// 10**400 mod 7 and related values computed with integers.
+	n := new(big.Int).Exp(big.NewInt(10), big.NewInt(400), nil)
+	z := new(big.Float).SetPrec(2000).SetInt(n)
+	for _, d := range []int64{7, 1000003, -13} {
+		w := new(big.Float).SetInt64(d)
+		m := new(big.Int).Rem(n, big.NewInt(d))
+		got := bigfloat.FMod(new(big.Float), z, w)
+		if want := new(big.Float).SetInt(m); got.Cmp(want) != 0 {
+			t.Errorf("FMod(1e400, %d) = %g, want %g", d, got, want)
+		}
+		// The result has the divisor's precision only, but is exact.
+		got = bigfloat.FMod(new(big.Float).SetPrec(64), z, w)
+		if want := new(big.Float).SetInt(m); got.Cmp(want) != 0 {
+			t.Errorf("FMod(1e400, %d) to 64 bits = %g, want %g", d, got, want)
+		}
+	}
+	// 2**100000 mod 3 = 1, and 2**100000 + 2**-100000 mod 3 needs the low bit.
+	z = new(big.Float).SetMantExp(big.NewFloat(1), 100000)
+	got, _ := bigfloat.FMod(new(big.Float), z, big.NewFloat(3)).Float64()
+	if got != 1 {
+		t.Errorf("FMod(2**100000, 3) = %g, want 1", got)
+	}
+	z.SetPrec(200001)
+	z.Add(z, new(big.Float).SetMantExp(big.NewFloat(1), -100000))
+	w := big.NewFloat(3)
+	want := new(big.Float).SetMantExp(big.NewFloat(1), -100000)
+	want.SetPrec(200001).Add(want, big.NewFloat(1))
+	if got := bigfloat.FMod(new(big.Float), z, w); got.Cmp(want) != 0 {
+		t.Errorf("FMod(2**100000 + 2**-100000, 3) = %g, want %g", got, want)
+	}
+	// A quotient of about 2**330 by an irrational-looking divisor, checked
+	// against the quotient computed with enough precision to be exact.
+	z = parse("1e100", 1000)
+	pi := bigfloat.Pi(new(big.Float).SetPrec(1000))
+	q := new(big.Float).SetPrec(2000).Quo(z, pi)
+	bigfloat.Trunc(q, q)
+	want = new(big.Float).SetPrec(4000).Mul(q, pi)
+	want.Sub(z, want)
+	if got := bigfloat.FMod(new(big.Float), z, pi); got.Cmp(want) != 0 {
+		t.Errorf("FMod(1e100, π) = %g, want %g", got, want)
+	}
+}
+
+func TestModSpecialValues(t *testing.T) {
+	inf, ninf := math.Inf(1), math.Inf(-1)
+	nz := math.Copysign(0, -1)
+	for _, c := range [][5]float64{
+		// z, w, FMod, Mod, Remainder
+		{0, 3, 0, 0, 0},
+		{nz, 3, nz, 0, nz},
+		{0, -3, 0, nz, 0},
+		{5, inf, 5, 5, 5},
+		{-5, inf, -5, inf, -5},
+		{5, ninf, 5, ninf, 5},
+		{-5, ninf, -5, -5, -5},
+		{-6, 3, nz, 0, nz},
+		{6, -3, 0, nz, 0},
+	} {
+		z, w := big.NewFloat(c[0]), big.NewFloat(c[1])
+		for i, f := range []func(o, z, w *big.Float) *big.Float{bigfloat.FMod, bigfloat.Mod, bigfloat.Remainder} {
+			got, _ := f(new(big.Float), z, w).Float64()
+			if want := c[2+i]; got != want || math.Signbit(got) != math.Signbit(want) {
+				t.Errorf("%s(%g, %g) = %g, want %g", [...]string{"FMod", "Mod", "Remainder"}[i], c[0], c[1], got, want)
+			}
+		}
+	}
+	for _, c := range [][2]float64{{inf, 1}, {ninf, 1}, {1, 0}, {1, nz}, {inf, inf}} {
+		z, w := big.NewFloat(c[0]), big.NewFloat(c[1])
+		expectNaN(t, fmt.Sprintf("FMod(%g, %g)", c[0], c[1]), func() { bigfloat.FMod(new(big.Float), z, w) })
+		expectNaN(t, fmt.Sprintf("Mod(%g, %g)", c[0], c[1]), func() { bigfloat.Mod(new(big.Float), z, w) })
+		expectNaN(t, fmt.Sprintf("Remainder(%g, %g)", c[0], c[1]), func() { bigfloat.Remainder(new(big.Float), z, w) })
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkFMod(b *testing.B) {
+	for _, prec := range []uint{1e2, 1e3} {
+		z := new(big.Float).SetPrec(prec).SetMantExp(big.NewFloat(1.1), 100000)
+		w := bigfloat.Pi(new(big.Float).SetPrec(prec))
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.FMod(o, z, w)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package bigfloat
+
+import "math/big"
+
+// NextUp sets o to the least value representable at o's precision that is
+// greater than x and returns o. If o's precision is zero, then it is given x's
+// precision, so that NextUp steps x up by one ulp, or 64 if x's precision is
+// also zero, as for an infinity from SetInf. NextUp(±0) is the least
+// positive big.Float, NextUp(+Inf) = +Inf, and NextUp(-Inf) is the most
+// negative finite value at o's precision. o may be the same as x.
+func NextUp(o, x *big.Float) *big.Float {
+	return nextToward(o, x, true)
+}
+
+// NextDown sets o to the greatest value representable at o's precision that
+// is less than x and returns o. If o's precision is zero, then it is given x's
+// precision, so that NextDown steps x down by one ulp, or 64 if x's precision
+// is also zero, as for an infinity from SetInf. NextDown(±0) is the
+// greatest negative big.Float, NextDown(-Inf) = -Inf, and NextDown(+Inf) is
+// the greatest finite value at o's precision. o may be the same as x.
+func NextDown(o, x *big.Float) *big.Float {
+	return nextToward(o, x, false)
+}
+
+// Nextafter sets o to the next value representable at o's precision after x
+// in the direction of y and returns o, like math.Nextafter. If x = y, then o
+// is set to y. If o's precision is zero, then it is given x's precision, or 64
+// if x's precision is also zero.
+func Nextafter(o, x, y *big.Float) *big.Float {
+	switch x.Cmp(y) {
+	case -1:
+		return NextUp(o, x)
+	case 1:
+		return NextDown(o, x)
+	}
+	nextPrec(o, x)
+	return o.Set(y)
+}
+
+// Ulp sets o to the unit in the last place of x at x's precision, the
+// distance from |x| to the next larger magnitude at that precision, and
+// returns o. The result is a power of 2, so it is exact at any precision. If
+// o's precision is zero, then it is given x's precision, or 1 if x's precision
+// is also zero. Ulp(±0) is the least positive big.Float, and Ulp(±Inf) = +Inf.
+func Ulp(o, x *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		if x.Prec() == 0 {
+			o.SetPrec(1)
+		} else {
+			o.SetPrec(x.Prec())
+		}
+	}
+	switch {
+	case x.IsInf():
+		return o.SetInf(false)
+	case x.Sign() == 0:
+		return o.SetMantExp(o.SetInt64(1), big.MinExp-1)
+	}
+	exp := x.MantExp(nil)
+	return o.SetMantExp(o.SetInt64(1), exp-int(x.Prec()))
+}
+
+// nextToward performs NextUp if up is true or NextDown otherwise.
+func nextToward(o, x *big.Float, up bool) *big.Float {
+	nextPrec(o, x)
+	p := o.Prec()
+	switch {
+	case x.IsInf():
+		if x.Signbit() == up {
+			// Stepping away from the infinity toward zero gives the
+			// largest finite value.
+			m := new(big.Float).SetPrec(p).SetInt64(1)
+			m.Sub(m, new(big.Float).SetMantExp(m, -int(p)))
+			o.SetMantExp(m, big.MaxExp)
+			if x.Signbit() {
+				o.Neg(o)
+			}
+			return o
+		}
+		return o.Set(x)
+	case x.Sign() == 0:
+		o.SetMantExp(o.SetInt64(1), big.MinExp-1)
+		if !up {
+			o.Neg(o)
+		}
+		return o
+	}
+	mode := big.ToNegativeInf
+	if up {
+		mode = big.ToPositiveInf
+	}
+	// If x isn't representable at precision p, rounding it in the direction
+	// of the step gives the answer.
+	r := new(big.Float).SetPrec(p).SetMode(mode).Set(x)
+	if r.Acc() != big.Exact {
+		return o.Set(r)
+	}
+	// Otherwise, step the mantissa of x, in [1/2, 1), by adding an amount
+	// smaller than its spacing, including the finer spacing below 1/2, and
+	// rounding in the direction of the step. Scaling it back by the exponent
+	// overflows to infinity or underflows to zero past the ends of the
+	// exponent range.
+	exp := r.MantExp(r)
+	tiny := new(big.Float).SetMantExp(&gonep, -int(p)-2)
+	if !up {
+		tiny.Neg(tiny)
+	}
+	t := new(big.Float).SetPrec(p+3).Add(r, tiny)
+	r.Set(t)
+	return o.SetMantExp(r, exp)
+}
+
+// nextPrec gives o x's precision if o's precision is zero, or 64 if x's
+// precision is also zero.
+func nextPrec(o, x *big.Float) {
+	switch {
+	case o.Prec() != 0:
+	case x.Prec() != 0:
+		o.SetPrec(x.Prec())
+	default:
+		o.SetPrec(64)
+	}
+}
@@ -0,0 +1,172 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestNextFloat64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	xs := []float64{1, -1, 0.5, -0.5, 2, 3, -3, 0.1, 1 << 52, 1<<53 - 1, 1e300, -1e-300, math.MaxFloat64 / 2, 1.5, math.Ldexp(1, -1000)}
+	for i := 0; i < 200; i++ {
+		xs = append(xs, math.Ldexp(r.Float64()-0.5, r.Intn(1000)-500))
+	}
+	for _, x := range xs {
+		z := big.NewFloat(x)
+		inf := math.Inf(1)
+		if got, _ := bigfloat.NextUp(new(big.Float), z).Float64(); got != math.Nextafter(x, inf) {
+			t.Errorf("NextUp(%g) = %g, want %g", x, got, math.Nextafter(x, inf))
+		}
+		if got, _ := bigfloat.NextDown(new(big.Float), z).Float64(); got != math.Nextafter(x, -inf) {
+			t.Errorf("NextDown(%g) = %g, want %g", x, got, math.Nextafter(x, -inf))
+		}
+		for _, y := range []float64{-inf, 0, 1, inf, x} {
+			if got, _ := bigfloat.Nextafter(new(big.Float), z, big.NewFloat(y)).Float64(); got != math.Nextafter(x, y) {
+				t.Errorf("Nextafter(%g, %g) = %g, want %g", x, y, got, math.Nextafter(x, y))
+			}
+		}
+		a := math.Abs(x)
+		if got, _ := bigfloat.Ulp(new(big.Float), z).Float64(); got != math.Nextafter(a, inf)-a {
+			t.Errorf("Ulp(%g) = %g, want %g", x, got, math.Nextafter(a, inf)-a)
+		}
+		// In place.
+		if got, _ := bigfloat.NextUp(z, z).Float64(); got != math.Nextafter(x, inf) {
+			t.Errorf("NextUp(%g) in place = %g, want %g", x, got, math.Nextafter(x, inf))
+		}
+	}
+}
+
+func TestNextPrecision(t *testing.T) {
+	// Powers of 2 have a finer spacing below than above.
+	for _, prec := range []uint{1, 2, 10, 200} {
+		p := int(prec)
+		x := new(big.Float).SetPrec(prec).SetInt64(8)
+		up := bigfloat.NextUp(new(big.Float), x)
+		if want := add2(8, 4-p); up.Prec() != prec || up.Cmp(want) != 0 {
+			t.Errorf("NextUp(8) at prec %d = %g, want %g", prec, up, want)
+		}
+		down := bigfloat.NextDown(new(big.Float), x)
+		if want := new(big.Float).Neg(add2(-8, 3-p)); down.Cmp(want) != 0 {
+			t.Errorf("NextDown(8) at prec %d = %g, want %g", prec, down, want)
+		}
+		x.Neg(x)
+		if want := add2(-8, 3-p); bigfloat.NextUp(up, x).Cmp(want) != 0 {
+			t.Errorf("NextUp(-8) at prec %d = %g, want %g", prec, up, want)
+		}
+		u := bigfloat.Ulp(new(big.Float), x)
+		if want := add2(0, 4-p); u.Cmp(want) != 0 {
+			t.Errorf("Ulp(-8) at prec %d = %g, want %g", prec, u, want)
+		}
+	}
+	// Stepping at o's precision, which is less than x's: x is between two
+	// values, so the step is to the nearer one in its direction.
+	x := parse("1.1", 200)
+	up := bigfloat.NextUp(new(big.Float).SetPrec(53), x)
+	down := bigfloat.NextDown(new(big.Float).SetPrec(53), x)
+	if u, _ := up.Float64(); u != 1.1 {
+		t.Errorf("NextUp(1.1 at 200 bits) to 53 bits = %g, want %g", up, 1.1)
+	}
+	if d, _ := down.Float64(); d != math.Nextafter(1.1, 0) {
+		t.Errorf("NextDown(1.1 at 200 bits) to 53 bits = %g, want %g", down, math.Nextafter(1.1, 0))
+	}
+	// At a higher precision than x's.
+	up = bigfloat.NextUp(new(big.Float).SetPrec(100), big.NewFloat(1))
+	want := new(big.Float).SetMantExp(big.NewFloat(1), -99)
+	want.SetPrec(100).Add(want, big.NewFloat(1))
+	if up.Cmp(want) != 0 {
+		t.Errorf("NextUp(1) to 100 bits = %g, want %g", up, want)
+	}
+	// Round trip.
+	x = bigfloat.Pi(new(big.Float).SetPrec(1000))
+	y := bigfloat.NextDown(new(big.Float), bigfloat.NextUp(new(big.Float), x))
+	if y.Cmp(x) != 0 {
+		t.Errorf("NextDown(NextUp(π)) = %g, want %g", y, x)
+	}
+	d := new(big.Float).Sub(bigfloat.NextUp(new(big.Float), x), x)
+	if u := bigfloat.Ulp(new(big.Float), x); d.Cmp(u) != 0 {
+		t.Errorf("NextUp(π) - π = %g, want Ulp(π) = %g", d, u)
+	}
+}
+
+func TestNextSpecialValues(t *testing.T) {
+	inf := big.NewFloat(math.Inf(1))
+	ninf := big.NewFloat(math.Inf(-1))
+	least := new(big.Float).SetMantExp(big.NewFloat(1), big.MinExp-1)
+	for _, z := range []*big.Float{new(big.Float).SetPrec(53), new(big.Float).Neg(new(big.Float).SetPrec(53))} {
+		if got := bigfloat.NextUp(new(big.Float), z); got.Cmp(least) != 0 {
+			t.Errorf("NextUp(%g) = %g, want %g", z, got, least)
+		}
+		if got := bigfloat.NextDown(new(big.Float), z); got.Cmp(new(big.Float).Neg(least)) != 0 {
+			t.Errorf("NextDown(%g) = %g, want -%g", z, got, least)
+		}
+		if got := bigfloat.Ulp(new(big.Float), z); got.Cmp(least) != 0 {
+			t.Errorf("Ulp(%g) = %g, want %g", z, got, least)
+		}
+	}
+	if got := bigfloat.NextDown(new(big.Float), least); got.Sign() != 0 {
+		t.Errorf("NextDown(%g) = %g, want 0", least, got)
+	}
+	if got := bigfloat.NextUp(new(big.Float), inf); !got.IsInf() || got.Signbit() {
+		t.Errorf("NextUp(+Inf) = %g, want +Inf", got)
+	}
+	if got := bigfloat.NextDown(new(big.Float), ninf); !got.IsInf() || !got.Signbit() {
+		t.Errorf("NextDown(-Inf) = %g, want -Inf", got)
+	}
+	max := bigfloat.NextDown(new(big.Float).SetPrec(53), inf)
+	if max.IsInf() || max.Sign() <= 0 || max.MantExp(nil) != big.MaxExp {
+		t.Errorf("NextDown(+Inf) = %g, want the largest finite value", max)
+	}
+	if got := bigfloat.NextUp(new(big.Float), max); !got.IsInf() || got.Signbit() {
+		t.Errorf("NextUp(%g) = %g, want +Inf", max, got)
+	}
+	if got := bigfloat.NextUp(new(big.Float).SetPrec(53), ninf); got.Cmp(new(big.Float).Neg(max)) != 0 {
+		t.Errorf("NextUp(-Inf) = %g, want %g", got, new(big.Float).Neg(max))
+	}
+	if got := bigfloat.Ulp(new(big.Float), ninf); !got.IsInf() || got.Signbit() {
+		t.Errorf("Ulp(-Inf) = %g, want +Inf", got)
+	}
+}
+
+func TestNextZeroPrecInf(t *testing.T) {
+	// SetInf gives an infinity with zero precision, so the result takes the
+	// default precision of 64 rather than collapsing to zero.
+	max := bigfloat.NextDown(new(big.Float).SetPrec(64), big.NewFloat(math.Inf(1)))
+	got := bigfloat.NextDown(new(big.Float), new(big.Float).SetInf(false))
+	if got.Prec() != 64 || got.Cmp(max) != 0 {
+		t.Errorf("NextDown(+Inf) = %g (prec %d), want %g (prec 64)", got, got.Prec(), max)
+	}
+	got = bigfloat.NextUp(new(big.Float), new(big.Float).SetInf(true))
+	if got.Prec() != 64 || got.Cmp(new(big.Float).Neg(max)) != 0 {
+		t.Errorf("NextUp(-Inf) = %g (prec %d), want -%g (prec 64)", got, got.Prec(), max)
+	}
+	got = bigfloat.Nextafter(new(big.Float), new(big.Float).SetInf(false), new(big.Float))
+	if got.Prec() != 64 || got.Cmp(max) != 0 {
+		t.Errorf("Nextafter(+Inf, 0) = %g (prec %d), want %g (prec 64)", got, got.Prec(), max)
+	}
+}
+
+// add2 returns x + 2**k exactly.
+func add2(x int64, k int) *big.Float {
+	r := new(big.Float).SetMantExp(big.NewFloat(1), k)
+	return r.SetPrec(300).Add(r, big.NewFloat(float64(x)))
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkNextUp(b *testing.B) {
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		x := bigfloat.Pi(new(big.Float).SetPrec(prec))
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.NextUp(o, x)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package bigfloat
+
+import (
+	"math/big"
+	"math/bits"
+	"runtime"
+)
+
+// PiAlgorithm selects a method for computing π.
+type PiAlgorithm int
+
+const (
+	// PiChudnovsky sums the Chudnovsky series using binary splitting, with
+	// the recursion divided among up to GOMAXPROCS goroutines. Each term of
+	// the series contributes about 47 bits. This is the algorithm Pi uses to
+	// extend the cache.
+	PiChudnovsky PiAlgorithm = iota
+	// PiBrentSalamin uses the Brent–Salamin AGM iteration, which doubles the
+	// number of correct bits each step but needs a full-precision square root
+	// in every step.
+	PiBrentSalamin
+)
+
+// String returns the name of the algorithm.
+func (alg PiAlgorithm) String() string {
+	switch alg {
+	case PiChudnovsky:
+		return "Chudnovsky"
+	case PiBrentSalamin:
+		return "Brent–Salamin"
+	default:
+		return "PiAlgorithm(invalid)"
+	}
+}
+
+// ComputePi sets a to π to a's precision using alg and returns a. Unlike Pi,
+// ComputePi always performs the full computation and neither reads nor updates
+// the cached value, so it is suitable for cross-checking the algorithms
+// against each other. Panics if alg is not a known algorithm.
+func ComputePi(a *big.Float, alg PiAlgorithm) *big.Float {
+	if a.Prec() == 0 {
+		// Zero-precision floats represent only ±0 or ±inf.
+		return a.Set(&gzero)
+	}
+	switch alg {
+	case PiChudnovsky:
+		return piChudnovsky(a)
+	case PiBrentSalamin:
+		return piAGM(a)
+	default:
+		panic("bigfloat: unknown pi algorithm " + alg.String())
+	}
+}
+
+// Constants of the Chudnovsky series
+//
+//	1/π = 12 Σ (-1)^k (6k)! (A + Bk) / ((3k)! (k!)³ C^(3k+3/2)).
+const (
+	chudA        = 13591409
+	chudB        = 545140134
+	chudC3Over24 = 640320 * 640320 * 640320 / 24
+	// chudBitsPerTerm is a lower bound on log2(C³/1728), the number of bits
+	// each term of the series contributes.
+	chudBitsPerTerm = 47
+)
+
+// piChudnovsky computes π to a's precision using the Chudnovsky series.
+func piChudnovsky(a *big.Float) *big.Float {
+	prec := a.Prec()
+	n := int64(prec/chudBitsPerTerm) + 2
+
+	// Split the recursion among goroutines until there is about one leaf per
+	// processor.
+	depth := bits.Len(uint(runtime.GOMAXPROCS(0) - 1))
+	_, q, t := chudnovskyBS(0, n, depth)
+
+	// π = 426880 √10005 Q / T
+	a.SetPrec(prec + 64)
+	r := new(big.Float).SetPrec(prec + 64).SetInt64(10005)
+	r.Sqrt(r)
+	a.SetInt(q)
+	a.Mul(a, r)
+	a.Mul(a, r.SetInt64(426880))
+	a.Quo(a, r.SetInt(t))
+	return a.SetPrec(prec)
+}
+
+// chudnovskyBS computes P(a, b), Q(a, b), and T(a, b) for the Chudnovsky
+// series using binary splitting. If depth is positive, the left half of the
+// recursion is evaluated concurrently on a new goroutine.
+func chudnovskyBS(a, b int64, depth int) (p, q, t *big.Int) {
+	if b-a == 1 {
+		if a == 0 {
+			return big.NewInt(1), big.NewInt(1), big.NewInt(chudA)
+		}
+		// P = -(6a-5)(2a-1)(6a-1)
+		p = big.NewInt(6*a - 5)
+		p.Mul(p, big.NewInt(2*a-1))
+		p.Mul(p, big.NewInt(6*a-1))
+		p.Neg(p)
+		// Q = a³ C³/24
+		q = big.NewInt(a)
+		q.Mul(q, q).Mul(q, big.NewInt(a))
+		q.Mul(q, big.NewInt(chudC3Over24))
+		// T = P (A + Ba)
+		t = big.NewInt(chudB)
+		t.Mul(t, big.NewInt(a))
+		t.Add(t, big.NewInt(chudA))
+		t.Mul(t, p)
+		return p, q, t
+	}
+
+	m := (a + b) / 2
+	var pl, ql, tl, pr, qr, tr *big.Int
+	if depth > 0 {
+		done := make(chan struct{})
+		go func() {
+			pl, ql, tl = chudnovskyBS(a, m, depth-1)
+			close(done)
+		}()
+		pr, qr, tr = chudnovskyBS(m, b, depth-1)
+		<-done
+	} else {
+		pl, ql, tl = chudnovskyBS(a, m, 0)
+		pr, qr, tr = chudnovskyBS(m, b, 0)
+	}
+
+	// P = Pl Pr, Q = Ql Qr, T = Tl Qr + Pl Tr
+	t = tl.Mul(tl, qr)
+	t.Add(t, tr.Mul(tr, pl))
+	p = pl.Mul(pl, pr)
+	q = ql.Mul(ql, qr)
+	return p, q, t
+}
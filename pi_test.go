@@ -0,0 +1,58 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestComputePi(t *testing.T) {
+	const piStr = "3.1415926535897932384626433832795028841971693993751058209749445923078164062862089986280348253421170679821480865132823066470938446095505822317253594081284811174502841027019385211055596446229489549303819644288109756659334461284756482337867831652712019091456485669234603486104543266482133936072602491412737245870066063155881748815209209628292540917153644"
+	for _, alg := range []bigfloat.PiAlgorithm{bigfloat.PiChudnovsky, bigfloat.PiBrentSalamin} {
+		for _, prec := range []uint{1, 2, 24, 53, 64, 100, 200, 300, 400, 500, 600, 700, 800, 900, 1000} {
+			want := new(big.Float).SetPrec(prec)
+			want.Parse(piStr, 10)
+
+			z := bigfloat.ComputePi(new(big.Float).SetPrec(prec), alg)
+
+			if z.Cmp(want) != 0 {
+				t.Errorf("ComputePi(%d, %v) =\ngot  %g;\nwant %g", prec, alg, z, want)
+			}
+		}
+	}
+}
+
+func TestComputePiCrossCheck(t *testing.T) {
+	// Vary GOMAXPROCS so that the Chudnovsky recursion is split to several
+	// different depths.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for _, procs := range []int{1, 2, 3, 8} {
+		runtime.GOMAXPROCS(procs)
+		for _, prec := range []uint{1001, 4096, 10000, 33333} {
+			x := bigfloat.ComputePi(new(big.Float).SetPrec(prec), bigfloat.PiChudnovsky)
+			y := bigfloat.ComputePi(new(big.Float).SetPrec(prec), bigfloat.PiBrentSalamin)
+			if x.Cmp(y) != 0 {
+				t.Errorf("GOMAXPROCS=%d, prec=%d: Chudnovsky and Brent–Salamin disagree:\nChudnovsky    %.50g\nBrent–Salamin %.50g", procs, prec, x, y)
+			}
+		}
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkComputePi(b *testing.B) {
+	for _, alg := range []bigfloat.PiAlgorithm{bigfloat.PiChudnovsky, bigfloat.PiBrentSalamin} {
+		for _, prec := range []uint{1e2, 1e3, 1e4, 1e5, 1e6} {
+			p := new(big.Float).SetPrec(prec)
+			b.Run(fmt.Sprintf("%v/%v", alg, prec), func(b *testing.B) {
+				b.ReportAllocs()
+				for n := 0; n < b.N; n++ {
+					bigfloat.ComputePi(p, alg)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,687 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// Polylog sets o to the polylogarithm
+//
+//	Li_s(z) = Σ z**k / k**s, k ≥ 1,
+//
+// or its analytic continuation, to o's precision and returns o. If o's
+// precision is zero, then it is given the greater of the precisions of s and
+// z. For z > 1, where Li_s has a branch cut, the result is the real part,
+// which is the same from either side. Li_s(±0) = ±0 for all s. Li_s(1) is
+// ζ(s) for s > 1 and +Inf otherwise, the limit from below. Li_s(-Inf) is
+// -Inf, -1, or 0 according to whether s is positive, zero, or negative, and
+// likewise Li_n(+Inf) for integers n. Li_(+Inf)(z) = z for |z| ≤ 1. Panics with
+// ErrNaN if s is -Inf, if s is +Inf and |z| > 1, or if z is +Inf and s is
+// positive but not an integer.
+func Polylog(o, s, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		if s.Prec() >= z.Prec() {
+			o.SetPrec(s.Prec())
+		} else {
+			o.SetPrec(z.Prec())
+		}
+	}
+	switch {
+	case s.IsInf():
+		if s.Signbit() {
+			panic(ErrNaN{msg: "Polylog: s is -Inf"})
+		}
+		if z.IsInf() || new(big.Float).Abs(z).Cmp(&gonep) > 0 {
+			panic(ErrNaN{msg: "Polylog: s is +Inf and |z| > 1"})
+		}
+		return o.Set(z)
+	case z.Sign() == 0:
+		return o.Set(z)
+	case z.IsInf():
+		if !z.Signbit() && !s.IsInt() && s.Sign() > 0 {
+			panic(ErrNaN{msg: "Polylog: z is +Inf and s is not an integer"})
+		}
+		switch s.Sign() {
+		case 1:
+			return o.SetInf(true)
+		case 0:
+			return o.SetInt64(-1)
+		}
+		return o.SetInt64(0)
+	case s.Cmp(&gtwop) == 0:
+		return Dilog(o, z)
+	case s.Sign() == 0:
+		// Li_0(z) = z/(1-z). The subtraction is exact where it could cancel.
+		if z.Cmp(&gonep) == 0 {
+			return o.SetInf(false)
+		}
+		y := new(big.Float).SetPrec(o.Prec()+z.Prec()+32).Sub(&gonep, z)
+		return o.Quo(z, y)
+	case z.Cmp(&gonep) == 0:
+		if s.Cmp(&gonep) > 0 {
+			return Zeta(o, s)
+		}
+		return o.SetInf(false)
+	}
+	r := ziv(o.Prec(), func(wp uint) (*big.Float, int) {
+		return polylog(s, z, wp)
+	})
+	return o.Set(r)
+}
+
+// Dilog sets o to the dilogarithm Li_2(z) to o's precision and returns o. If
+// o's precision is zero, then it is given the precision of z. As for Polylog,
+// the result for z > 1 is the real part. Li_2(±0) = ±0, Li_2(±Inf) = -Inf, and
+// the exact values Li_2(1) = π²/6, Li_2(-1) = -π²/12, Li_2(1/2) = π²/12 -
+// (log 2)²/2, and Li_2(2) = π²/4 are computed from the cached value of π.
+func Dilog(o, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	prec := o.Prec()
+	switch {
+	case z.Sign() == 0:
+		return o.Set(z)
+	case z.IsInf():
+		return o.SetInf(true)
+	case z.Cmp(&gonep) == 0:
+		return o.Set(dilogPi2(prec+32, 6))
+	case z.Cmp(&gonem) == 0:
+		return o.Set(dilogPi2(prec+32, -12))
+	case z.Cmp(&gtwop) == 0:
+		return o.Set(dilogPi2(prec+32, 4))
+	case z.Cmp(&ghalfp) == 0:
+		r := dilogPi2(prec+32, 12)
+		l := Log(new(big.Float).SetPrec(prec+32), &gtwop)
+		l.Mul(l, l)
+		return o.Sub(r, quicksh(l, l, -1))
+	}
+	r := ziv(prec, func(wp uint) (*big.Float, int) {
+		return dilog(z, wp)
+	})
+	return o.Set(r)
+}
+
+// dilogPi2 returns π²/d to precision wp.
+func dilogPi2(wp uint, d int64) *big.Float {
+	r := new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+	r.Mul(r, r)
+	return r.Quo(r, new(big.Float).SetInt64(d))
+}
+
+// dilog returns Li_2(z) to precision wp for finite z ≠ 0, along with the
+// binary exponent of the largest quantity in the computation for ziv. The
+// reflection, Landen, and inversion formulas bring z into [-1/2, 1/2], where
+// the series converges at least one bit per term.
+func dilog(z *big.Float, wp uint) (*big.Float, int) {
+	// 1-z and z-1 are exact for 1/2 ≤ z ≤ 2, where they would cancel.
+	y := new(big.Float).SetPrec(wp+z.Prec()).Sub(&gonep, z)
+	var r, u *big.Float
+	var mag int
+	switch {
+	case z.Cmp(&gtwop) > 0:
+		// Li_2(z) = π²/3 - (log z)²/2 - Li_2(1/z)
+		r, mag = dilog(new(big.Float).SetPrec(wp).Quo(&gonep, z), wp)
+		r.Neg(r)
+		u = Log(new(big.Float).SetPrec(wp), z)
+		u.Mul(u, u)
+		quicksh(u, u, -1)
+		r.Sub(r, u)
+		r.Add(r, dilogPi2(wp, 3))
+	case z.Cmp(&ghalfp) > 0:
+		// Li_2(z) = π²/6 - log z log|1-z| - Li_2(1-z)
+		r, mag = dilog(y, wp)
+		r.Neg(r)
+		u = polylogLog(z, wp)
+		u.Mul(u, Log(new(big.Float).SetPrec(wp), new(big.Float).Abs(y)))
+		r.Sub(r, u)
+		r.Add(r, dilogPi2(wp, 6))
+	case z.Cmp(&ghalfm) >= 0:
+		// Li_2(z) = Σ z**k/k²
+		v := new(big.Float)
+		return sumSeries(new(big.Float).SetPrec(wp).Set(z), wp, func(t *big.Float, k int64) {
+			// t *= z k²/(k+1)²
+			t.Mul(t, z)
+			t.Mul(t, v.SetInt64(k))
+			t.Mul(t, v)
+			t.Quo(t, v.SetInt64(k+1))
+			t.Quo(t, v)
+		})
+	case z.Cmp(&gonem) >= 0:
+		// Li_2(z) = -Li_2(z/(z-1)) - (log(1-z))²/2
+		w := new(big.Float).SetPrec(wp).Neg(y)
+		r, mag = dilog(w.Quo(z, w), wp)
+		r.Neg(r)
+		u = Log(new(big.Float).SetPrec(wp), y)
+		u.Mul(u, u)
+		r.Sub(r, quicksh(u, u, -1))
+	default:
+		// Li_2(z) = -π²/6 - (log -z)²/2 - Li_2(1/z)
+		r, mag = dilog(new(big.Float).SetPrec(wp).Quo(&gonep, z), wp)
+		r.Neg(r)
+		u = Log(new(big.Float).SetPrec(wp), new(big.Float).Neg(z))
+		u.Mul(u, u)
+		r.Sub(r, quicksh(u, u, -1))
+		r.Sub(r, dilogPi2(wp, 6))
+	}
+	// Every other quantity is at most about π²/3 in magnitude.
+	if e := u.MantExp(nil); u.Sign() != 0 && e > mag {
+		mag = e
+	}
+	if mag < 2 {
+		mag = 2
+	}
+	return r, mag
+}
+
+// polylog returns Li_s(z), or its real part for z > 1, to precision wp for
+// finite s and finite z ≠ 0, 1 along with the binary exponent of the largest
+// quantity in the computation for ziv.
+func polylog(s, z *big.Float, wp uint) (*big.Float, int) {
+	n, acc := s.Int64()
+	isInt := acc == big.Exact
+	if isInt {
+		switch n {
+		case 0:
+			y := new(big.Float).SetPrec(wp+z.Prec()).Sub(&gonep, z)
+			r := new(big.Float).SetPrec(wp).Quo(z, y)
+			return r, r.MantExp(nil)
+		case 1:
+			if z.MantExp(nil) < 0 {
+				break
+			}
+			// Li_1(z) = -log|1-z|, with 1-z exact where it could be near 1.
+			y := new(big.Float).SetPrec(wp+z.Prec()).Sub(&gonep, z)
+			r := Log(new(big.Float).SetPrec(wp), y.Abs(y))
+			if r.Sign() == 0 {
+				return r, math.MinInt32
+			}
+			mag := r.MantExp(nil)
+			if mag < 0 {
+				mag = 0
+			}
+			return r.Neg(r), mag
+		case 2:
+			return dilog(z, wp)
+		}
+	}
+	a := new(big.Float).Abs(z)
+	switch {
+	case a.Cmp(&gonep) > 0:
+		if isInt {
+			return polylogInvInt(n, z, wp)
+		}
+		return polylogInv(s, z, wp)
+	case z.Cmp(&gonem) == 0:
+		return polylogEta(s, wp)
+	case z.Cmp(&ghalfm) < 0:
+		return polylogDup(s, z, wp)
+	case a.Cmp(&ghalfp) <= 0:
+		return polylogSeries(s, z, wp)
+	}
+	// For large s, the series converges quickly even near z = 1.
+	if sf, _ := s.Float64(); sf >= float64(wp)/8 {
+		return polylogSeries(s, z, wp)
+	}
+	return polylogNearOne(s, z, wp)
+}
+
+// polylogSeries returns Li_s(z) = Σ z**k / k**s to precision wp for |z| < 1,
+// along with the binary exponent of the largest term or partial sum. The
+// powers k**-s are products of powers of primes, as in zetaPowSum.
+func polylogSeries(s, z *big.Float, wp uint) (*big.Float, int) {
+	wp += 16
+	ns := new(big.Float).Neg(s)
+	p := new(big.Float).SetPrec(wp).Set(z)
+	sum := new(big.Float).SetPrec(wp).Set(z)
+	pows := []*big.Float{nil, new(big.Float).SetPrec(wp).SetInt64(1)}
+	t := new(big.Float).SetPrec(wp)
+	mag, last := z.MantExp(nil), z.MantExp(nil)
+	for k := int64(2); ; k++ {
+		var q *big.Float
+		if f := smallestFactor(k); f < k {
+			q = new(big.Float).SetPrec(wp).Mul(pows[f], pows[k/f])
+		} else {
+			q = zetaPow(t.SetInt64(k), ns)
+		}
+		pows = append(pows, q)
+		p.Mul(p, z)
+		t.Mul(p, q)
+		if t.Sign() == 0 {
+			break
+		}
+		sum.Add(sum, t)
+		e := t.MantExp(nil)
+		if e > mag {
+			mag = e
+		}
+		if sum.Sign() != 0 && sum.MantExp(nil) > mag {
+			mag = sum.MantExp(nil)
+		}
+		// The terms are unimodal, so once they are small and decreasing,
+		// the rest are smaller still.
+		if e < mag-int(wp) && e < last {
+			break
+		}
+		last = e
+	}
+	return sum, mag
+}
+
+// polylogLog returns log z to precision wp for 1/2 < z < 2 with full relative
+// precision, which Log alone lacks near 1.
+func polylogLog(z *big.Float, wp uint) *big.Float {
+	p := wp
+	if d := new(big.Float).Sub(z, &gonep); d.Sign() != 0 && d.MantExp(nil) < 0 {
+		p += uint(-d.MantExp(nil))
+	}
+	return Log(new(big.Float).SetPrec(p), z).SetPrec(wp)
+}
+
+// polylogNearOne returns Li_s(z) to precision wp for 1/2 < z < 1 and s ≠ 0,
+// 1, along with the binary exponent of the largest quantity in the
+// computation. With L = log z, it uses
+//
+//	Li_s(z) = Γ(1-s) (-L)**(s-1) + Σ ζ(s-k) L**k/k!
+//
+// for non-integer s and
+//
+//	Li_n(z) = L**(n-1)/(n-1)! (H_(n-1) - log(-L)) + Σ ζ(n-k) L**k/k!, k ≠ n-1
+//
+// for integers n ≥ 2, where H is a harmonic number. The zeta values for s-k
+// at least 1/2 come from one zetaLadder, and the rest come from another
+// through the reflection formula ζ(s-k) = 2 (2π)**-u cos(πu/2) Γ(u) ζ(u) with
+// u = 1-s+k, whose factors all follow simple recurrences in k.
+func polylogNearOne(s, z *big.Float, wp uint) (*big.Float, int) {
+	wp2 := wp + 32
+	sf, _ := s.Float64()
+	L := polylogLog(z, wp2)
+	lf, _ := L.Float64()
+	lf = math.Log(-lf)
+	n, acc := s.Int64()
+	isInt := acc == big.Exact
+
+	// The terms with ζ(s-k) for k ≥ kp use the reflection formula.
+	var kp int64
+	if sf >= 0.5 {
+		kp = int64(math.Floor(sf-0.5)) + 1
+		if isInt {
+			// Skip ζ(1), and ζ(0) = -1/2 is a special case.
+			kp = n - 1
+		}
+	}
+	s0 := new(big.Float).SetPrec(wp2 + s.Prec()).SetInt64(1 - kp)
+	s0.Add(s0, s)
+	u0 := new(big.Float).SetPrec(wp2 + s.Prec()).SetInt64(1 + kp)
+	u0.Sub(u0, s)
+	kr := kp
+	if isInt && n >= 1 {
+		kr = n + 1
+		s0.SetInt64(2)
+		u0.SetInt64(2)
+	}
+	// Estimate the number of terms from the size of the reflected zeta
+	// values, which grow like Γ(u)/(2π)**u.
+	count := kr
+	for k := kr; ; k++ {
+		u := float64(k) + 1 - sf
+		lg, _ := math.Lgamma(u)
+		lk, _ := math.Lgamma(float64(k) + 1)
+		if (math.Ln2-u*math.Log(2*math.Pi)+lg-lk+float64(k)*lf)/math.Ln2 < -float64(wp2) {
+			count = k + 1
+			break
+		}
+	}
+	zp := zetaLadder(s0, int(kp), wp2)
+	zr := zetaLadder(u0, int(count-kr), wp2)
+
+	// The singular term.
+	var r *big.Float
+	if isInt && n >= 1 {
+		// H_(n-1) - log(-L), times L**(n-1)/(n-1)!
+		h := new(big.Float).SetPrec(wp2)
+		t := new(big.Float).SetPrec(wp2)
+		for k := int64(1); k < n; k++ {
+			h.Add(h, t.Quo(&gonep, t.SetInt64(k)))
+		}
+		r = Log(new(big.Float).SetPrec(wp2), t.Neg(L))
+		r.Sub(h, r)
+		for k := int64(1); k < n; k++ {
+			r.Mul(r, L)
+			r.Quo(r, t.SetInt64(k))
+		}
+	} else {
+		w := new(big.Float).SetPrec(reflectPrec(s, wp2)).Sub(&gonep, s)
+		r = Gamma(new(big.Float).SetPrec(wp2), w)
+		w.Neg(w)
+		t := new(big.Float).SetPrec(wp2).Neg(L)
+		r.Mul(r, Pow(t, t, w))
+	}
+	mag := r.MantExp(nil)
+
+	// Reflection factors: g = 2 (2π)**-u Γ(u), with c = cos(πu/2) and
+	// sn = sin(πu/2).
+	tau := new(big.Float).SetPrec(wp2).Set(cachedPi(wp2))
+	quicksh(tau, tau, 1)
+	g := new(big.Float).SetPrec(wp2)
+	var c, sn *big.Float
+	if count > kr {
+		g = Gamma(g, u0)
+		lt := Log(new(big.Float).SetPrec(wp2+32), tau)
+		lt.Mul(lt, u0)
+		g.Quo(g, expIntExp(lt, wp2))
+		quicksh(g, g, 1)
+		h := quicksh(new(big.Float), u0, -1)
+		c = cosPi(new(big.Float).SetPrec(wp2), h)
+		sn = sinPi(new(big.Float).SetPrec(wp2), h)
+	}
+	u := new(big.Float).SetPrec(wp2).Set(u0)
+
+	t := new(big.Float).SetPrec(wp2).SetInt64(1) // L**k/k!
+	v := new(big.Float).SetPrec(wp2)
+	for k := int64(0); k < count; k++ {
+		if k > 0 {
+			t.Mul(t, L)
+			t.Quo(t, v.SetInt64(k))
+		}
+		switch {
+		case k < kp:
+			v.Mul(zp[kp-1-k], t)
+		case isInt && n >= 1 && k < kr:
+			if k == n-1 {
+				continue
+			}
+			// ζ(0) = -1/2
+			v.Neg(quicksh(v, t, -1))
+		default:
+			v.Mul(g, c)
+			v.Mul(v, zr[k-kr])
+			v.Mul(v, t)
+			// Step u to u+1.
+			g.Mul(g, u)
+			g.Quo(g, tau)
+			c, sn = sn.Neg(sn), c
+			u.Add(u, &gonep)
+		}
+		r.Add(r, v)
+		if v.Sign() != 0 && v.MantExp(nil) > mag {
+			mag = v.MantExp(nil)
+		}
+	}
+	if r.Sign() != 0 && r.MantExp(nil) > mag {
+		mag = r.MantExp(nil)
+	}
+	return r, mag
+}
+
+// polylogEta returns Li_s(-1) = -η(s) = (2**(1-s) - 1) ζ(s) to precision wp
+// for s ≠ 1, along with the binary exponent of the largest quantity in the
+// computation.
+func polylogEta(s *big.Float, wp uint) (*big.Float, int) {
+	z := Zeta(new(big.Float).SetPrec(wp), s)
+	if z.Sign() == 0 {
+		return z, math.MinInt32
+	}
+	w := new(big.Float).SetPrec(reflectPrec(s, wp)).Sub(&gonep, s)
+	f := Pow(new(big.Float).SetPrec(wp), new(big.Float).SetPrec(wp).SetInt64(2), w)
+	f.Sub(f, &gonep)
+	// The subtraction cancels for s near 1, where ζ(s) is large.
+	lost := 0
+	if e := f.MantExp(nil); e < 0 {
+		lost = -e
+	}
+	r := f.Mul(f, z)
+	return r, r.MantExp(nil) + lost
+}
+
+// polylogDup returns Li_s(z) to precision wp for -1 < z < -1/2, along with
+// the binary exponent of the largest quantity in the computation, using the
+// duplication formula
+//
+//	Li_s(z) = 2**(1-s) Li_s(z²) - Li_s(-z).
+func polylogDup(s, z *big.Float, wp uint) (*big.Float, int) {
+	z2 := new(big.Float).SetPrec(2*z.Prec()).Mul(z, z)
+	a, amag := polylog(s, z2, wp)
+	b, bmag := polylog(s, new(big.Float).Neg(z), wp)
+	w := new(big.Float).SetPrec(reflectPrec(s, wp)).Sub(&gonep, s)
+	f := Pow(new(big.Float).SetPrec(wp), new(big.Float).SetPrec(wp).SetInt64(2), w)
+	a.Mul(a, f)
+	amag += f.MantExp(nil)
+	a.Sub(a, b)
+	if bmag > amag {
+		amag = bmag
+	}
+	return a, amag
+}
+
+// polylogInvInt returns Li_n(z), or its real part for z > 1, to precision wp
+// for an integer n and finite |z| > 1, along with the binary exponent of the
+// largest quantity in the computation. With L = log|z|, the inversion formula
+// is
+//
+//	Li_n(z) = -(-1)**n Li_n(1/z) - 2 Σ c_j L**(n-2j)/(n-2j)!, 0 ≤ j ≤ n/2,
+//
+// with c_j = η(2j) for z < 0 and c_j = -ζ(2j) for z > 1, so c_0 = 1/2.
+func polylogInvInt(n int64, z *big.Float, wp uint) (*big.Float, int) {
+	wp2 := wp + 16
+	s := new(big.Float).SetInt64(n)
+	r, mag := polylog(s, new(big.Float).SetPrec(wp2).Quo(&gonep, z), wp2)
+	if n%2 == 0 {
+		r.Neg(r)
+	}
+	if n < 0 {
+		return r, mag
+	}
+	L := Log(new(big.Float).SetPrec(wp2), new(big.Float).Abs(z))
+	// t = L**(n-2j)/(n-2j)!, computed from j = n/2 upward.
+	t := new(big.Float).SetPrec(wp2).SetInt64(1)
+	v := new(big.Float).SetPrec(wp2)
+	for k := int64(1); k <= n%2; k++ {
+		t.Mul(t, L)
+	}
+	c := new(big.Float).SetPrec(wp2)
+	for j := n / 2; j >= 0; j-- {
+		if j == 0 {
+			c.Set(&ghalfp)
+		} else {
+			Zeta(c, v.SetInt64(2*j))
+			if z.Signbit() {
+				// η(2j) = (1 - 2**(1-2j)) ζ(2j)
+				c.Sub(c, quicksh(v, c, int(1-2*j)))
+			} else {
+				c.Neg(c)
+			}
+		}
+		v.Mul(c, t)
+		quicksh(v, v, 1)
+		r.Sub(r, v)
+		if e := v.MantExp(nil); v.Sign() != 0 && e > mag {
+			mag = e
+		}
+		// Step t from L**m/m! to L**(m+2)/(m+2)!, m = n-2j.
+		m := n - 2*j
+		t.Mul(t, L)
+		t.Quo(t, v.SetInt64(m+1))
+		t.Mul(t, L)
+		t.Quo(t, v.SetInt64(m+2))
+	}
+	if r.Sign() != 0 && r.MantExp(nil) > mag {
+		mag = r.MantExp(nil)
+	}
+	return r, mag
+}
+
+// polylogInv returns Li_s(z), or its real part for z > 1, to precision wp for
+// non-integer s and finite |z| > 1, along with the binary exponent of the
+// largest quantity in the computation. It uses the real part of Jonquière's
+// inversion formula
+//
+//	Li_s(z) + exp(iπs) Li_s(1/z) = (2π)**s exp(iπs/2)/Γ(s) ζ(1-s, a),
+//
+// where a = 1/2 - i log(-z)/2π for z < 0 and a = 1 - i log(z)/2π for z > 1.
+// The choice of side of the branch cut affects only the imaginary part.
+func polylogInv(s, z *big.Float, wp uint) (*big.Float, int) {
+	wp2 := wp + 32
+	r, mag := polylog(s, new(big.Float).SetPrec(wp2).Quo(&gonep, z), wp2)
+	r.Mul(r, cosPi(new(big.Float).SetPrec(wp2), s))
+	r.Neg(r)
+
+	tau := new(big.Float).SetPrec(wp2).Set(cachedPi(wp2))
+	quicksh(tau, tau, 1)
+	ai := Log(new(big.Float).SetPrec(wp2), new(big.Float).Abs(z))
+	ai.Quo(ai, tau)
+	ai.Neg(ai)
+	ar := &gonep
+	if z.Signbit() {
+		ar = &ghalfp
+	}
+	sig := new(big.Float).SetPrec(reflectPrec(s, wp2)).Sub(&gonep, s)
+	hr, hi, hmag := hurwitzComplex(sig, ar, ai, wp2)
+
+	// (2π)**s / Γ(s) (cos(πs/2) hr - sin(πs/2) hi)
+	h := quicksh(new(big.Float), s, -1)
+	c := cosPi(new(big.Float).SetPrec(wp2), h)
+	sn := sinPi(new(big.Float).SetPrec(wp2), h)
+	hr.Mul(hr, c)
+	hr.Sub(hr, sn.Mul(sn, hi))
+	lt := Log(new(big.Float).SetPrec(wp2+32), tau)
+	lt.Mul(lt, s)
+	f := expIntExp(lt, wp2)
+	f.Quo(f, Gamma(new(big.Float).SetPrec(wp2), s))
+	hr.Mul(hr, f)
+	hmag += f.MantExp(nil)
+	if hmag > mag {
+		mag = hmag
+	}
+	r.Add(r, hr)
+	if r.Sign() != 0 && r.MantExp(nil) > mag {
+		mag = r.MantExp(nil)
+	}
+	return r, mag
+}
+
+// hurwitzComplex returns the real and imaginary parts of ζ(σ, a) to precision
+// wp for finite real σ ≠ 1 and complex a = ar + i ai with ar > 0, along with
+// the binary exponent of the largest quantity in the computation, using the
+// Euler-Maclaurin formula as in hurwitzEM.
+func hurwitzComplex(sig, ar, ai *big.Float, wp uint) (re, im *big.Float, mag int) {
+	wp += 16
+	sf, _ := sig.Float64()
+	arf, _ := ar.Float64()
+	aif, _ := ai.Float64()
+	// Choose n so that |n+a| is large enough for the correction terms to
+	// converge to wp bits.
+	var n int64
+	lim := float64(wp)/5 + math.Abs(sf)/4 + 1
+	if d := lim*lim - aif*aif; d > 0 && math.Sqrt(d) > arf {
+		n = int64(math.Ceil(math.Sqrt(d) - arf))
+		wp += uint(bits.Len64(uint64(n)))
+	}
+	ns := new(big.Float).SetPrec(wp).Neg(sig)
+	re = new(big.Float).SetPrec(wp)
+	im = new(big.Float).SetPrec(wp)
+	mag = math.MinInt32
+	acc := func(xr, xi *big.Float) {
+		re.Add(re, xr)
+		im.Add(im, xi)
+		for _, x := range [...]*big.Float{xr, xi, re, im} {
+			if x.Sign() != 0 && x.MantExp(nil) > mag {
+				mag = x.MantExp(nil)
+			}
+		}
+	}
+	yr := new(big.Float).SetPrec(wp)
+	for k := int64(0); k < n; k++ {
+		yr.SetInt64(k)
+		yr.Add(yr, ar)
+		acc(cpow(yr, ai, ns, wp))
+	}
+	yr.SetInt64(n)
+	yr.Add(yr, ar)
+	yi := new(big.Float).SetPrec(wp).Set(ai)
+	wr, wi := cpow(yr, yi, ns, wp)
+
+	// y**(1-σ)/(σ-1) = w y/(σ-1)
+	tr, ti := cmul(wr, wi, yr, yi)
+	d := new(big.Float).SetPrec(wp).Sub(sig, &gonep)
+	acc(tr.Quo(tr, d), ti.Quo(ti, d))
+	acc(quicksh(new(big.Float), wr, -1), quicksh(new(big.Float), wi, -1))
+
+	// 1/y and 1/y²
+	d.Mul(yr, yr)
+	d.Add(d, new(big.Float).SetPrec(wp).Mul(yi, yi))
+	vr := new(big.Float).SetPrec(wp).Quo(yr, d)
+	vi := new(big.Float).SetPrec(wp).Quo(yi, d)
+	vi.Neg(vi)
+	v2r, v2i := cmul(vr, vi, vr, vi)
+
+	// f = σ(σ+1)...(σ+2j-2) / (2j)!, starting from j = 1
+	f := quicksh(new(big.Float), sig, -1).SetPrec(wp)
+	pr, pi := cmul(wr, wi, vr, vi)
+	u := new(big.Float).SetPrec(wp)
+	b := bernoulli(int(wp/7) + 2)
+	for j := 1; ; j++ {
+		if j >= len(b) {
+			b = bernoulli(2 * j)
+		}
+		u.SetRat(b[j])
+		u.Mul(u, f)
+		tr := new(big.Float).SetPrec(wp).Mul(pr, u)
+		ti := new(big.Float).SetPrec(wp).Mul(pi, u)
+		acc(tr, ti)
+		e := tr.MantExp(nil)
+		if ti.MantExp(nil) > e {
+			e = ti.MantExp(nil)
+		}
+		if u.Sign() == 0 || (tr.Sign() == 0 && ti.Sign() == 0) || e < mag-int(wp) {
+			break
+		}
+		pr, pi = cmul(pr, pi, v2r, v2i)
+		u.SetInt64(int64(2*j - 1))
+		f.Mul(f, u.Add(u, sig))
+		u.SetInt64(int64(2 * j))
+		f.Mul(f, u.Add(u, sig))
+		f.Quo(f, u.SetInt64(int64((2*j+1)*(2*j+2))))
+	}
+	return re, im, mag
+}
+
+// cmul returns the real and imaginary parts of (ar + i ai)(br + i bi) at the
+// precision of ar.
+func cmul(ar, ai, br, bi *big.Float) (re, im *big.Float) {
+	p := ar.Prec()
+	re = new(big.Float).SetPrec(p).Mul(ar, br)
+	re.Sub(re, new(big.Float).SetPrec(p).Mul(ai, bi))
+	im = new(big.Float).SetPrec(p).Mul(ar, bi)
+	im.Add(im, new(big.Float).SetPrec(p).Mul(ai, br))
+	return re, im
+}
+
+// cpow returns the real and imaginary parts of (xr + i xi)**w to precision wp
+// for finite real w and xr > 0.
+func cpow(xr, xi, w *big.Float, wp uint) (re, im *big.Float) {
+	// (xr + i xi)**w = exp(w log|x|) (cos(wθ) + i sin(wθ)), θ = arg x. The
+	// absolute error in w log|x| and wθ becomes relative error in the result.
+	p := wp + 16
+	if e := w.MantExp(nil); e > 0 {
+		p += uint(e)
+	}
+	if e := xr.MantExp(nil); e > 0 {
+		p += uint(bits.Len(uint(e)))
+	}
+	m := new(big.Float).SetPrec(p).Mul(xr, xr)
+	m.Add(m, new(big.Float).SetPrec(p).Mul(xi, xi))
+	Log(m, m)
+	quicksh(m, m, -1)
+	m.Mul(m, w)
+	m = expIntExp(m, wp)
+	th := new(big.Float).SetPrec(p).Quo(xi, xr)
+	th = atan(th.Abs(th), p)
+	if xi.Signbit() {
+		th.Neg(th)
+	}
+	th.Mul(th, w)
+	sn, c := sinCos(th, wp)
+	return c.Mul(c, m), sn.Mul(sn, m)
+}
@@ -0,0 +1,252 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestPolylogValues(t *testing.T) {
+	const prec = 200
+	wp := uint(prec + 64)
+	pi := bigfloat.Pi(new(big.Float).SetPrec(wp))
+	pi2 := new(big.Float).Mul(pi, pi)
+	ln2 := bigfloat.Log(new(big.Float).SetPrec(wp), big.NewFloat(2))
+
+	// Li_3(1/2) = 7/8 ζ(3) - π² log 2/12 + (log 2)³/6
+	want := bigfloat.Zeta(new(big.Float).SetPrec(wp), big.NewFloat(3))
+	want.Mul(want, big.NewFloat(0.875))
+	u := new(big.Float).Mul(pi2, ln2)
+	want.Sub(want, u.Quo(u, big.NewFloat(12)))
+	u.Mul(ln2, ln2)
+	u.Mul(u, ln2)
+	want.Add(want, u.Quo(u, big.NewFloat(6)))
+	got := bigfloat.Polylog(new(big.Float).SetPrec(prec), big.NewFloat(3), big.NewFloat(0.5))
+	if !closeTo(got, want, prec-2) {
+		t.Errorf("Li_3(1/2):\ngot  %g\nwant %g", got, want)
+	}
+
+	// Li_s(-1) = (2**(1-s) - 1) ζ(s)
+	for _, s := range []float64{-2.5, 0.5, 3, 7.5} {
+		want := bigfloat.Zeta(new(big.Float).SetPrec(wp), big.NewFloat(s))
+		u := bigfloat.Pow(new(big.Float).SetPrec(wp), new(big.Float).SetPrec(wp).SetInt64(2), big.NewFloat(1-s))
+		want.Mul(want, u.Sub(u, big.NewFloat(1)))
+		got := bigfloat.Polylog(new(big.Float).SetPrec(prec), big.NewFloat(s), big.NewFloat(-1))
+		if !closeTo(got, want, prec-2) {
+			t.Errorf("Li_%g(-1):\ngot  %g\nwant %g", s, got, want)
+		}
+	}
+
+	// Li_1(z) = -log(1-z), Li_-1(z) = z/(1-z)², Li_-2(z) = z(1+z)/(1-z)³
+	for _, zf := range []float64{0.25, 0.75, -0.75, -3, 5} {
+		z := big.NewFloat(zf)
+		y := new(big.Float).SetPrec(wp).Sub(big.NewFloat(1), z)
+		want := bigfloat.Log(new(big.Float).SetPrec(wp), new(big.Float).Abs(y))
+		want.Neg(want)
+		got := bigfloat.Polylog(new(big.Float).SetPrec(prec), big.NewFloat(1), z)
+		if !closeTo(got, want, prec-2) {
+			t.Errorf("Li_1(%g):\ngot  %g\nwant %g", zf, got, want)
+		}
+		want.Quo(z, y)
+		want.Quo(want, y)
+		got = bigfloat.Polylog(new(big.Float).SetPrec(prec), big.NewFloat(-1), z)
+		if !closeTo(got, want, prec-2) {
+			t.Errorf("Li_-1(%g):\ngot  %g\nwant %g", zf, got, want)
+		}
+		want.Mul(want, u.Add(z, big.NewFloat(1)))
+		want.Quo(want, y)
+		got = bigfloat.Polylog(new(big.Float).SetPrec(prec), big.NewFloat(-2), z)
+		if !closeTo(got, want, prec-2) {
+			t.Errorf("Li_-2(%g):\ngot  %g\nwant %g", zf, got, want)
+		}
+	}
+}
+
+func TestPolylogSeries(t *testing.T) {
+	// Near z = 1, Polylog uses an expansion in log z rather than the
+	// defining series, so compare against the series summed directly.
+	const prec = 100
+	z := big.NewFloat(0.875)
+	for _, s := range []float64{-2.5, -1, 0.5, 1.5, 3, 4.25} {
+		ns := big.NewFloat(-s)
+		want := new(big.Float).SetPrec(prec + 64)
+		p := new(big.Float).SetPrec(prec + 64).SetInt64(1)
+		for k := int64(1); k < 1000; k++ {
+			p.Mul(p, z)
+			u := bigfloat.Pow(new(big.Float).SetPrec(prec+64), new(big.Float).SetPrec(prec+64).SetInt64(k), ns)
+			want.Add(want, u.Mul(u, p))
+		}
+		got := bigfloat.Polylog(new(big.Float).SetPrec(prec), big.NewFloat(s), z)
+		if !closeTo(got, want, prec-2) {
+			t.Errorf("Li_%g(%g):\ngot  %g\nwant %g", s, z, got, want)
+		}
+	}
+}
+
+func TestPolylogInversion(t *testing.T) {
+	// For |z| > 1, integer orders use the inversion formula with Bernoulli
+	// numbers, and others use Jonquière's formula with a complex Hurwitz
+	// zeta function. Orders within 2**-180 of an integer must agree with
+	// the integer.
+	const prec = 150
+	eps := new(big.Float).SetMantExp(big.NewFloat(1), -180)
+	for _, n := range []int64{-3, 2, 3, 4} {
+		s := new(big.Float).SetPrec(300).SetInt64(n)
+		s.Add(s, eps)
+		for _, zf := range []float64{-1e10, -7, -1.5, 1.25, 3, 12.5, 1e10} {
+			z := big.NewFloat(zf)
+			want := bigfloat.Polylog(new(big.Float).SetPrec(prec), big.NewFloat(float64(n)), z)
+			got := bigfloat.Polylog(new(big.Float).SetPrec(prec), s, z)
+			if !closeTo(got, want, prec-4) {
+				t.Errorf("Li_%d(%g):\ngot  %g\nwant %g", n, zf, got, want)
+			}
+		}
+	}
+}
+
+func TestPolylogPrecision(t *testing.T) {
+	for _, s := range []float64{-4.5, -2, 0.5, 3, 5.5, 40} {
+		for _, zf := range []float64{-20, -1.25, -0.75, 0.125, 0.625, 0.99, 2.5} {
+			z := big.NewFloat(zf)
+			want := bigfloat.Polylog(new(big.Float).SetPrec(500), big.NewFloat(s), z)
+			for _, prec := range []uint{53, 200} {
+				got := bigfloat.Polylog(new(big.Float).SetPrec(prec), big.NewFloat(s), z)
+				if !closeTo(got, want, prec-2) {
+					t.Errorf("Li_%g(%g) at prec %d:\ngot  %g\nwant %g", s, zf, prec, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestPolylogSpecialValues(t *testing.T) {
+	inf, negz := math.Inf(1), math.Copysign(0, -1)
+	for _, test := range []struct {
+		s, z float64
+		want float64
+	}{
+		{3, 0, 0},
+		{3, negz, negz},
+		{-2.5, negz, negz},
+		{0, 0.75, 3},
+		{0, -1, -0.5},
+		{0.5, 1, inf},
+		{-2, 1, inf},
+		{-2, -1, 0},
+		{inf, 0.5, 0.5},
+		{inf, -1, -1},
+		{2.5, -inf, -inf},
+		{0, -inf, -1},
+		{-0.5, -inf, 0},
+		{3, inf, -inf},
+		{0, inf, -1},
+		{-3, inf, 0},
+	} {
+		x64, acc := bigfloat.Polylog(new(big.Float).SetPrec(53), big.NewFloat(test.s), big.NewFloat(test.z)).Float64()
+		if x64 != test.want || math.Signbit(x64) != math.Signbit(test.want) || acc != big.Exact {
+			t.Errorf("Polylog(%g, %g) = %g (%s), want %g (Exact)", test.s, test.z, x64, acc, test.want)
+		}
+	}
+	// Li_s(1) = ζ(s) for s > 1.
+	got := bigfloat.Polylog(new(big.Float).SetPrec(100), big.NewFloat(3), big.NewFloat(1))
+	want := bigfloat.Zeta(new(big.Float).SetPrec(164), big.NewFloat(3))
+	if !closeTo(got, want, 98) {
+		t.Errorf("Li_3(1) = %g, want %g", got, want)
+	}
+	for _, test := range []struct {
+		s, z float64
+	}{
+		{math.Inf(-1), 0.5},
+		{inf, 1.5},
+		{inf, -inf},
+		{2.5, inf},
+	} {
+		expectNaN(t, fmt.Sprintf("Polylog(%g, %g)", test.s, test.z), func() {
+			bigfloat.Polylog(new(big.Float), big.NewFloat(test.s), big.NewFloat(test.z))
+		})
+	}
+}
+
+func TestDilog(t *testing.T) {
+	const prec = 200
+	pi := bigfloat.Pi(new(big.Float).SetPrec(prec + 64))
+	pi2 := new(big.Float).Mul(pi, pi)
+	ln2 := bigfloat.Log(new(big.Float).SetPrec(prec+64), big.NewFloat(2))
+	half := new(big.Float).Quo(pi2, big.NewFloat(12))
+	half.Sub(half, new(big.Float).Quo(new(big.Float).Mul(ln2, ln2), big.NewFloat(2)))
+	for _, test := range []struct {
+		z    float64
+		want *big.Float
+	}{
+		{1, new(big.Float).Quo(pi2, big.NewFloat(6))},
+		{-1, new(big.Float).Quo(pi2, big.NewFloat(-12))},
+		{2, new(big.Float).Quo(pi2, big.NewFloat(4))},
+		{0.5, half},
+		{1.5, parse("2.374395270272480200677499763071638423965", 200)},
+	} {
+		got := bigfloat.Dilog(new(big.Float).SetPrec(prec), big.NewFloat(test.z))
+		bits := uint(prec - 1)
+		if test.want.Prec() == 200 {
+			bits = 128
+		}
+		if !closeTo(got, test.want, bits) {
+			t.Errorf("Li_2(%g):\ngot  %g\nwant %g", test.z, got, test.want)
+		}
+	}
+	// Every transformation ends in the series for |z| ≤ 1/2, so compare the
+	// dilogarithm at several precisions across each of them.
+	for _, zf := range []float64{-1e6, -3, -1, -0.75, -0.25, 0.3, 0.6, 0.9999, 1.0001, 1.75, 4, 1e20} {
+		z := big.NewFloat(zf)
+		want := bigfloat.Dilog(new(big.Float).SetPrec(600), z)
+		for _, prec := range []uint{53, 300} {
+			got := bigfloat.Dilog(new(big.Float).SetPrec(prec), z)
+			if !closeTo(got, want, prec-2) {
+				t.Errorf("Li_2(%g) at prec %d:\ngot  %g\nwant %g", zf, prec, got, want)
+			}
+		}
+	}
+	for _, test := range []struct {
+		z, want float64
+	}{
+		{0, 0},
+		{math.Copysign(0, -1), math.Copysign(0, -1)},
+		{math.Inf(1), math.Inf(-1)},
+		{math.Inf(-1), math.Inf(-1)},
+	} {
+		x64, acc := bigfloat.Dilog(new(big.Float), big.NewFloat(test.z)).Float64()
+		if x64 != test.want || math.Signbit(x64) != math.Signbit(test.want) || acc != big.Exact {
+			t.Errorf("Dilog(%g) = %g (%s), want %g (Exact)", test.z, x64, acc, test.want)
+		}
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkPolylog(b *testing.B) {
+	s, z := big.NewFloat(2.5), big.NewFloat(0.75)
+	for _, prec := range []uint{1e2, 1e3} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Polylog(o, s, z)
+			}
+		})
+	}
+}
+
+func BenchmarkDilog(b *testing.B) {
+	z := big.NewFloat(0.75)
+	for _, prec := range []uint{1e2, 1e3} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Dilog(o, z)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// Cbrt sets o to the cube root of z to o's precision and returns o. Unlike
+// Pow(z, 1/3), it is defined for negative z, with Cbrt(-z) = -Cbrt(z), and it
+// is exact for perfect cubes whose roots are representable at o's precision.
+// If o's precision is zero, then it is given the precision of z.
+func Cbrt(o, z *big.Float) *big.Float {
+	return root(o, z, 3, "Cbrt")
+}
+
+// Root sets o to the real n-th root of z to o's precision and returns o. For
+// odd n, the root of a negative z is -Root(-z). The result is exact if the
+// root is representable at o's precision. Root(±0) = ±0 and Root(+Inf) =
+// +Inf, and Root(-Inf) = -Inf for odd n. If o's precision is zero, then it is
+// given the precision of z. Panics with ErrNaN if n is even and z is negative,
+// including -Inf, and panics if n is not positive.
+func Root(o, z *big.Float, n int) *big.Float {
+	return root(o, z, n, "Root")
+}
+
+// root performs Root, naming the function as name in panics.
+func root(o, z *big.Float, n int, name string) *big.Float {
+	if n <= 0 {
+		panic("bigfloat: " + name + ": n is not positive")
+	}
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	if z.Sign() < 0 && n%2 == 0 {
+		panic(ErrNaN{msg: name + ": even root of negative number"})
+	}
+	if z.Sign() == 0 || z.IsInf() || n == 1 {
+		return o.Set(z)
+	}
+	a := new(big.Float).Abs(z)
+	var r *big.Float
+	if n == 2 {
+		r = new(big.Float).SetPrec(o.Prec() + 32).Sqrt(a)
+	} else {
+		// Estimate the root from log2 |z| = log2 m + e, whose float64 value
+		// loses bits to the size of e.
+		m := new(big.Float)
+		e := a.MantExp(m)
+		mf, _ := m.Float64()
+		lg := (math.Log2(mf) + float64(e)) / float64(n)
+		ip := math.Floor(lg)
+		guess := new(big.Float).SetMantExp(big.NewFloat(math.Exp2(lg-ip)), int(ip))
+		p := 48 - bits.Len64(uint64(math.Abs(ip)))
+		if p < 4 {
+			p = 4
+		}
+		guess.SetPrec(uint(p))
+		// f(t)/f'(t) = (t**n - a)/(n t**(n-1)) = (t - a/t**(n-1))/n
+		nf := new(big.Float).SetInt64(int64(n))
+		f := func(t *big.Float) *big.Float {
+			u := powInt(t, n-1)
+			u.Quo(a, u)
+			u.Sub(t, u)
+			return u.Quo(u, nf)
+		}
+		r = newton(f, guess, o.Prec()+32)
+	}
+	// If the root rounds to a value whose n-th power is exactly |z|, that
+	// value is the answer. Its power has about n times as many bits as it
+	// does, so only a few candidates need the check.
+	c := new(big.Float).SetPrec(o.Prec()).Set(r)
+	switch k := c.MinPrec(); {
+	case k == 1:
+		// c is a power of 2, and so must be |z|.
+		if a.MinPrec() == 1 && int64(a.MantExp(nil))-1 == int64(n)*int64(c.MantExp(nil)-1) {
+			r = c
+		}
+	case uint(n)*(k-1)+1 <= a.MinPrec():
+		if rootPow(c, n).Cmp(a) == 0 {
+			r = c
+		}
+	}
+	o.Set(r)
+	if z.Signbit() {
+		o.Neg(o)
+	}
+	return o
+}
+
+// rootPow returns c**n exactly for n ≥ 1.
+func rootPow(c *big.Float, n int) *big.Float {
+	p := uint(n) * c.MinPrec()
+	r := new(big.Float).SetPrec(p).SetInt64(1)
+	b := new(big.Float).SetPrec(p).Set(c)
+	for {
+		if n&1 != 0 {
+			r.Mul(r, b)
+		}
+		n >>= 1
+		if n == 0 {
+			return r
+		}
+		b.Mul(b, b)
+	}
+}
@@ -0,0 +1,141 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestCbrtFloat64(t *testing.T) {
+	for _, x := range []float64{1, 2, 3, 0.5, 10, 1e-10, 1e100, 123.456, -2, -1e-300, 7e300} {
+		got, _ := bigfloat.Cbrt(new(big.Float).SetPrec(53), big.NewFloat(x)).Float64()
+		if want := math.Cbrt(x); math.Abs(got-want) > math.Abs(want)*0x1p-52 {
+			t.Errorf("Cbrt(%g) = %g, want %g", x, got, want)
+		}
+	}
+}
+
+func TestRootExact(t *testing.T) {
+	cases := []struct {
+		z, want string
+		n       int
+		prec    uint
+	}{
+		{"27", "3", 3, 53},
+		{"-8", "-2", 3, 53},
+		{"-8", "-2", 3, 2},
+		{"1e30", "1e10", 3, 100},
+		{"0.015625", "0.5", 6, 53},
+		{"2", "2", 1, 53},
+		{"16", "4", 2, 53},
+		{"-243", "-3", 5, 8},
+		{"10000000000000000000000000000000000000000", "10", 40, 200},
+	}
+	for _, c := range cases {
+		z := parse(c.z, 200)
+		want := parse(c.want, 200)
+		got := bigfloat.Root(new(big.Float).SetPrec(c.prec), z, c.n)
+		if got.Cmp(want) != 0 || got.Acc() != big.Exact {
+			t.Errorf("Root(%s, %d) at prec %d = %g (%v), want %s exactly", c.z, c.n, c.prec, got, got.Acc(), c.want)
+		}
+	}
+	// A power of two far outside float64's range.
+	z := new(big.Float).SetMantExp(big.NewFloat(1), 3000000)
+	got := bigfloat.Root(new(big.Float).SetPrec(64), z, 5)
+	if want := new(big.Float).SetMantExp(big.NewFloat(1), 600000); got.Cmp(want) != 0 {
+		t.Errorf("Root(2**3000000, 5) = %v, want 2**600000", got.Text('p', 0))
+	}
+	// Large n with a power of two.
+	z = new(big.Float).SetMantExp(big.NewFloat(1), -1000000)
+	got = bigfloat.Root(new(big.Float).SetPrec(64), z, 1000000)
+	if got.Cmp(big.NewFloat(0.5)) != 0 {
+		t.Errorf("Root(2**-1000000, 1000000) = %g, want 0.5", got)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	for _, prec := range []uint{100, 1000} {
+		for _, n := range []int{3, 4, 7, 12} {
+			for _, s := range []string{"2", "0.1", "3.14159", "1e-50", "12345678901234567890"} {
+				z := parse(s, prec)
+				r := bigfloat.Root(new(big.Float).SetPrec(prec), z, n)
+				if r.Prec() != prec {
+					t.Errorf("Root(%s, %d) has precision %d, want %d", s, n, r.Prec(), prec)
+				}
+				// r**n = z within a few bits, since n is small.
+				p := new(big.Float).SetPrec(prec * 2).SetInt64(1)
+				for i := 0; i < n; i++ {
+					p.Mul(p, r)
+				}
+				if !closeTo(p, z, 5) {
+					t.Errorf("Root(%s, %d)**%d = %g, want %g", s, n, n, p, z)
+				}
+			}
+		}
+	}
+	// Odd roots of negative numbers.
+	z := parse("-2", 300)
+	r := bigfloat.Cbrt(new(big.Float), z)
+	want := bigfloat.Cbrt(new(big.Float), parse("2", 300))
+	if r.Prec() != 300 || r.Cmp(want.Neg(want)) != 0 {
+		t.Errorf("Cbrt(-2) = %g, want %g", r, want)
+	}
+	// In place.
+	x := big.NewFloat(64)
+	if got := bigfloat.Root(x, x, 6); got.Cmp(big.NewFloat(2)) != 0 {
+		t.Errorf("Root(64, 6) in place = %g, want 2", got)
+	}
+}
+
+func TestRootSpecialValues(t *testing.T) {
+	inf := big.NewFloat(math.Inf(1))
+	ninf := big.NewFloat(math.Inf(-1))
+	nzero := new(big.Float).Neg(new(big.Float).SetPrec(53))
+	for _, n := range []int{1, 2, 3, 4} {
+		if got := bigfloat.Root(new(big.Float), new(big.Float).SetPrec(53), n); got.Sign() != 0 || got.Signbit() {
+			t.Errorf("Root(+0, %d) = %g, want +0", n, got)
+		}
+		if got := bigfloat.Root(new(big.Float), nzero, n); got.Sign() != 0 || !got.Signbit() {
+			t.Errorf("Root(-0, %d) = %g, want -0", n, got)
+		}
+		if got := bigfloat.Root(new(big.Float), inf, n); !got.IsInf() || got.Signbit() {
+			t.Errorf("Root(+Inf, %d) = %g, want +Inf", n, got)
+		}
+	}
+	if got := bigfloat.Cbrt(new(big.Float), ninf); !got.IsInf() || !got.Signbit() {
+		t.Errorf("Cbrt(-Inf) = %g, want -Inf", got)
+	}
+	if got := bigfloat.Root(new(big.Float), big.NewFloat(-5), 1); got.Cmp(big.NewFloat(-5)) != 0 {
+		t.Errorf("Root(-5, 1) = %g, want -5", got)
+	}
+	expectNaN(t, "Root(-1, 2)", func() { bigfloat.Root(new(big.Float), big.NewFloat(-1), 2) })
+	expectNaN(t, "Root(-Inf, 4)", func() { bigfloat.Root(new(big.Float), ninf, 4) })
+	for _, n := range []int{0, -3} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Root(2, %d) didn't panic", n)
+				}
+			}()
+			bigfloat.Root(new(big.Float), big.NewFloat(2), n)
+		}()
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkCbrt(b *testing.B) {
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		z := bigfloat.Pi(new(big.Float).SetPrec(prec))
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Cbrt(o, z)
+			}
+		})
+	}
+}
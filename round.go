@@ -0,0 +1,362 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"sync"
+)
+
+// Floor sets o to the greatest integer no greater than z and returns o. It
+// is Round with mode ToNegativeInf, with the same handling of o's precision
+// and of infinities. If o and z are the same, Floor does not allocate.
+func Floor(o, z *big.Float) *big.Float {
+	return Round(o, z, big.ToNegativeInf)
+}
+
+// Ceil sets o to the least integer no less than z and returns o. It is Round
+// with mode ToPositiveInf, with the same handling of o's precision and of
+// infinities. If o and z are the same, Ceil does not allocate.
+func Ceil(o, z *big.Float) *big.Float {
+	return Round(o, z, big.ToPositiveInf)
+}
+
+// Trunc sets o to the integer part of z and returns o. It is Round with mode
+// ToZero, with the same handling of o's precision and of infinities. If o and
+// z are the same, Trunc does not allocate.
+func Trunc(o, z *big.Float) *big.Float {
+	return Round(o, z, big.ToZero)
+}
+
+// Frac sets o to the fractional part of z, z - Trunc(z), which has the sign
+// of z, and returns o. If o's precision is zero, then it is given z's
+// precision, which suffices to hold the result exactly. Panics with ErrNaN if
+// z is infinite. If o and z are the same, Frac does not allocate.
+func Frac(o, z *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	if z.IsInf() {
+		panic(ErrNaN{msg: "Frac: argument is infinite"})
+	}
+	if z.IsInt() {
+		return signedZero(o, z)
+	}
+	exp := z.MantExp(nil)
+	if exp <= 0 {
+		return o.Set(z)
+	}
+	t := truncScratch(z, exp)
+	defer scratch.Put(t)
+	if o != z {
+		return o.Sub(z, t)
+	}
+	// Subtracting into an operand allocates, so form the exact difference
+	// separately.
+	u := scratch.Get().(*big.Float)
+	defer scratch.Put(u)
+	return o.Set(u.SetPrec(z.Prec()).Sub(z, t))
+}
+
+// Modf sets i to the integer part of z and f to its fractional part, both
+// with the sign of z, and returns them, like math.Modf. If i's or f's
+// precision is zero, then it is given z's precision. z may be the same as i
+// or f. Panics with ErrNaN if z is infinite. If z is the same as i or f, Modf
+// does not allocate.
+func Modf(i, f, z *big.Float) (*big.Float, *big.Float) {
+	if z.IsInf() {
+		panic(ErrNaN{msg: "Modf: argument is infinite"})
+	}
+	if i.Prec() == 0 {
+		i.SetPrec(z.Prec())
+	}
+	if f.Prec() == 0 {
+		f.SetPrec(z.Prec())
+	}
+	// Each case reads z before writing whichever of i and f may be z.
+	if z.IsInt() {
+		i.Set(z)
+		return i, signedZero(f, i)
+	}
+	exp := z.MantExp(nil)
+	if exp <= 0 {
+		f.Set(z)
+		return signedZero(i, f), f
+	}
+	// Compute the integer part exactly first, so that the fractional part is
+	// exact regardless of i's precision.
+	t := truncScratch(z, exp)
+	defer scratch.Put(t)
+	if f != z {
+		f.Sub(z, t)
+	} else {
+		u := scratch.Get().(*big.Float)
+		defer scratch.Put(u)
+		f.Set(u.SetPrec(z.Prec()).Sub(z, t))
+	}
+	return i.Set(t), f
+}
+
+// scratch holds temporaries for functions which promise not to allocate.
+var scratch = sync.Pool{New: func() interface{} { return new(big.Float) }}
+
+// truncScratch returns Trunc(z) exactly in a value from scratch, given the
+// exponent exp > 0 of z.
+func truncScratch(z *big.Float, exp int) *big.Float {
+	t := scratch.Get().(*big.Float)
+	return t.SetMode(big.ToZero).SetPrec(uint(exp)).Set(z)
+}
+
+// signedZero sets o to zero with the sign of z and returns o.
+func signedZero(o, z *big.Float) *big.Float {
+	o.SetInt64(0)
+	if z.Signbit() {
+		o.Neg(o)
+	}
+	return o
+}
+
+// FloorInt sets o to the greatest integer no greater than z and returns o.
+// Panics with ErrNaN if z is infinite.
+func FloorInt(o *big.Int, z *big.Float) *big.Int {
+	if z.IsInf() {
+		panic(ErrNaN{msg: "FloorInt: argument is infinite"})
+	}
+	if _, acc := z.Int(o); acc == big.Above {
+		o.Sub(o, intOne)
+	}
+	return o
+}
+
+// CeilInt sets o to the least integer no less than z and returns o. Panics
+// with ErrNaN if z is infinite.
+func CeilInt(o *big.Int, z *big.Float) *big.Int {
+	if z.IsInf() {
+		panic(ErrNaN{msg: "CeilInt: argument is infinite"})
+	}
+	if _, acc := z.Int(o); acc == big.Below {
+		o.Add(o, intOne)
+	}
+	return o
+}
+
+// RoundInt sets o to z rounded to an integer as constrained by mode and
+// returns o. Panics with ErrNaN if z is infinite.
+func RoundInt(o *big.Int, z *big.Float, mode big.RoundingMode) *big.Int {
+	switch mode {
+	case big.ToNegativeInf:
+		return FloorInt(o, z)
+	case big.ToPositiveInf:
+		return CeilInt(o, z)
+	}
+	if z.IsInf() {
+		panic(ErrNaN{msg: "RoundInt: argument is infinite"})
+	}
+	_, acc := z.Int(o)
+	switch mode {
+	case big.ToZero:
+	case big.AwayFromZero:
+		switch acc {
+		case big.Below:
+			o.Add(o, intOne)
+		case big.Above:
+			o.Sub(o, intOne)
+		}
+	case big.ToNearestEven, big.ToNearestAway:
+		if acc != big.Exact {
+			// z's precision is enough to round it to an integer exactly.
+			r := Round(new(big.Float).SetPrec(z.Prec()), z, mode)
+			r.Int(o)
+		}
+	default:
+		panic("bigfloat: unknown rounding mode " + mode.String())
+	}
+	return o
+}
+
+// intOne is 1 as a big.Int. It is never modified.
+var intOne = big.NewInt(1)
+
+// RoundPlaces sets o to z rounded to places digits after the radix point in
+// the given base, i.e. to a multiple of base**-places, as constrained by mode,
+// and returns o. A negative places rounds to a multiple of base**(-places),
+// e.g. places = -2 in base 10 rounds to hundreds. If o's precision is zero,
+// then it is given z's precision. The rounded value generally is not exact in
+// binary, so it is then rounded to o's precision according to o's rounding
+// mode. As a special case, if z is zero or infinite, o is set to z.
+//
+// Decimal fractions like 2.675 usually have no exact binary representation,
+// so z is only the nearest binary value to the number it is meant to denote,
+// which can lie on either side of it. To give the answers that decimal
+// arithmetic would, RoundPlaces treats z as denoting the nearest multiple of
+// half a unit in the last place, base**-places/2, if that is the only such
+// multiple which rounds to z at z's precision, and z's exact value otherwise.
+// Thus 2.675 rounds to 2.68 under ToNearestEven and 0.29 stays 0.29 under
+// ToZero, even though the binary value of each is slightly less than its
+// decimal value. Even so, the directed modes never give a result on the wrong
+// side of z's exact value.
+//
+// Panics if base is not between 2 and 36 inclusive.
+func RoundPlaces(o, z *big.Float, places, base int, mode big.RoundingMode) *big.Float {
+	if base < 2 || base > 36 {
+		panic("bigfloat: RoundPlaces: base out of range")
+	}
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	if z.Sign() == 0 || z.IsInf() {
+		return o.Set(z)
+	}
+	x, _ := z.Rat(nil)
+	return roundPlaces(o, z, x, places, base, mode)
+}
+
+// RoundSignificant sets o to z rounded to digits significant digits in the
+// given base as constrained by mode and returns o. It is RoundPlaces with
+// places chosen so that the leading digit of z is the first of digits. If o's
+// precision is zero, then it is given z's precision. As a special case, if z
+// is zero or infinite, o is set to z. Panics if digits is not positive or if
+// base is not between 2 and 36 inclusive.
+func RoundSignificant(o, z *big.Float, digits, base int, mode big.RoundingMode) *big.Float {
+	if base < 2 || base > 36 {
+		panic("bigfloat: RoundSignificant: base out of range")
+	}
+	if digits <= 0 {
+		panic("bigfloat: RoundSignificant: digit count is not positive")
+	}
+	if o.Prec() == 0 {
+		o.SetPrec(z.Prec())
+	}
+	if z.Sign() == 0 || z.IsInf() {
+		return o.Set(z)
+	}
+	x, _ := z.Rat(nil)
+	// Find k = ⌊log_base |z|⌋ from an estimate corrected exactly.
+	ax := new(big.Rat).Abs(x)
+	m := new(big.Float)
+	e := z.MantExp(m)
+	mf, _ := m.Float64()
+	k := int(math.Floor((float64(e) + math.Log2(math.Abs(mf))) / math.Log2(float64(base))))
+	for ratPow(base, k).Cmp(ax) > 0 {
+		k--
+	}
+	for ratPow(base, k+1).Cmp(ax) <= 0 {
+		k++
+	}
+	return roundPlaces(o, z, x, digits-1-k, base, mode)
+}
+
+// roundPlaces performs RoundPlaces for the nonzero finite z with exact value
+// x.
+func roundPlaces(o, z *big.Float, x *big.Rat, places, base int, mode big.RoundingMode) *big.Float {
+	// Scale x so that the rounding is to an integer.
+	unit := ratPow(base, -places)
+	v := new(big.Rat).Quo(x, unit)
+	if c := denotedHalf(z, v, unit); c != nil {
+		q := roundRat(c, mode)
+		o.SetRat(q.Mul(q, unit))
+		// z stands for c, but a directed mode must still not cross z itself.
+		if !wrongSide(o, x, mode) {
+			return o
+		}
+	}
+	q := roundRat(v, mode)
+	return o.SetRat(q.Mul(q, unit))
+}
+
+// denotedHalf returns the multiple of 1/2 nearest to v if it is the only such
+// multiple which, scaled by unit, rounds to z at z's precision. Otherwise, z is
+// not specific enough to denote any particular multiple, and denotedHalf
+// returns nil.
+func denotedHalf(z *big.Float, v, unit *big.Rat) *big.Rat {
+	h := new(big.Rat).Add(new(big.Rat).Add(v, v), big.NewRat(1, 2))
+	hn := new(big.Int).Div(h.Num(), h.Denom())
+	c := new(big.Rat).SetFrac(hn, big.NewInt(2))
+	half := big.NewRat(1, 2)
+	if !roundsTo(z, c, unit) ||
+		roundsTo(z, new(big.Rat).Sub(c, half), unit) ||
+		roundsTo(z, new(big.Rat).Add(c, half), unit) {
+		return nil
+	}
+	return c
+}
+
+// roundsTo reports whether c·unit rounds to z at z's precision.
+func roundsTo(z *big.Float, c, unit *big.Rat) bool {
+	d := new(big.Rat).Mul(c, unit)
+	return new(big.Float).SetPrec(z.Prec()).SetRat(d).Cmp(z) == 0
+}
+
+// wrongSide reports whether o lies on the side of x that mode excludes.
+func wrongSide(o *big.Float, x *big.Rat, mode big.RoundingMode) bool {
+	r, _ := o.Rat(nil)
+	c := r.Cmp(x)
+	if x.Sign() < 0 {
+		switch mode {
+		case big.ToZero:
+			mode = big.ToPositiveInf
+		case big.AwayFromZero:
+			mode = big.ToNegativeInf
+		}
+	} else {
+		switch mode {
+		case big.ToZero:
+			mode = big.ToNegativeInf
+		case big.AwayFromZero:
+			mode = big.ToPositiveInf
+		}
+	}
+	switch mode {
+	case big.ToNegativeInf:
+		return c > 0
+	case big.ToPositiveInf:
+		return c < 0
+	}
+	return false
+}
+
+// roundRat rounds v to an integer according to mode and returns it in a new
+// Rat.
+func roundRat(v *big.Rat, mode big.RoundingMode) *big.Rat {
+	neg := v.Sign() < 0
+	num := new(big.Int).Abs(v.Num())
+	q, r := num.QuoRem(num, v.Denom(), new(big.Int))
+	// Reduce the directed modes to those for the magnitude.
+	switch {
+	case mode == big.ToNegativeInf && !neg, mode == big.ToPositiveInf && neg:
+		mode = big.ToZero
+	case mode == big.ToNegativeInf, mode == big.ToPositiveInf:
+		mode = big.AwayFromZero
+	}
+	var up bool
+	switch mode {
+	case big.ToZero:
+	case big.AwayFromZero:
+		up = r.Sign() != 0
+	case big.ToNearestEven, big.ToNearestAway:
+		switch r.Lsh(r, 1).Cmp(v.Denom()) {
+		case 1:
+			up = true
+		case 0:
+			up = mode == big.ToNearestAway || q.Bit(0) == 1
+		}
+	default:
+		panic("bigfloat: unknown rounding mode " + mode.String())
+	}
+	if up {
+		q.Add(q, big.NewInt(1))
+	}
+	if neg {
+		q.Neg(q)
+	}
+	return new(big.Rat).SetInt(q)
+}
+
+// ratPow returns base**k for any integer k.
+func ratPow(base, k int) *big.Rat {
+	b := big.NewInt(int64(base))
+	if k < 0 {
+		b.Exp(b, big.NewInt(int64(-k)), nil)
+		return new(big.Rat).SetFrac(big.NewInt(1), b)
+	}
+	return new(big.Rat).SetInt(b.Exp(b, big.NewInt(int64(k)), nil))
+}
@@ -0,0 +1,368 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestFloorCeilTrunc(t *testing.T) {
+	for _, test := range []struct {
+		z                  float64
+		floor, ceil, trunc float64
+	}{
+		{0, 0, 0, 0},
+		{1, 1, 1, 1},
+		{0.25, 0, 1, 0},
+		{-0.25, -1, 0, 0},
+		{2.5, 2, 3, 2},
+		{-2.5, -3, -2, -2},
+		{1e20 + 65536, 1e20 + 65536, 1e20 + 65536, 1e20 + 65536},
+		{4503599627370495.5, 4503599627370495, 4503599627370496, 4503599627370495},
+		{math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(1)},
+		{math.Inf(-1), math.Inf(-1), math.Inf(-1), math.Inf(-1)},
+	} {
+		z := big.NewFloat(test.z)
+		if got, _ := bigfloat.Floor(new(big.Float), z).Float64(); got != test.floor {
+			t.Errorf("Floor(%g) = %g, want %g", test.z, got, test.floor)
+		}
+		if got, _ := bigfloat.Ceil(new(big.Float), z).Float64(); got != test.ceil {
+			t.Errorf("Ceil(%g) = %g, want %g", test.z, got, test.ceil)
+		}
+		if got, _ := bigfloat.Trunc(new(big.Float), z).Float64(); got != test.trunc {
+			t.Errorf("Trunc(%g) = %g, want %g", test.z, got, test.trunc)
+		}
+		// In place.
+		if got, _ := bigfloat.Floor(z, z).Float64(); got != test.floor {
+			t.Errorf("Floor(%g) in place = %g, want %g", test.z, got, test.floor)
+		}
+	}
+	// Exactly enough precision for the result.
+	z := big.NewFloat(6.75)
+	if got := bigfloat.Floor(new(big.Float).SetPrec(3), z); got.Cmp(big.NewFloat(6)) != 0 {
+		t.Errorf("Floor(6.75) to 3 bits = %g, want 6", got)
+	}
+	z = parse("123456789.987654321", 200)
+	allocs := testing.AllocsPerRun(10, func() {
+		bigfloat.Trunc(z, z)
+		bigfloat.Floor(z, z)
+		bigfloat.Ceil(z, z)
+	})
+	if allocs != 0 {
+		t.Errorf("in-place Trunc, Floor, and Ceil allocate %v times", allocs)
+	}
+}
+
+func TestFracModf(t *testing.T) {
+	for _, z := range []float64{0, math.Copysign(0, -1), 1, -3, 0.25, -0.25, 2.75, -2.75, 1e20, 4503599627370495.5, 1e-300} {
+		wantI, wantF := math.Modf(z)
+		i, f := bigfloat.Modf(new(big.Float), new(big.Float), big.NewFloat(z))
+		i64, _ := i.Float64()
+		f64, _ := f.Float64()
+		if i64 != wantI || math.Signbit(i64) != math.Signbit(wantI) || f64 != wantF || math.Signbit(f64) != math.Signbit(wantF) {
+			t.Errorf("Modf(%g) = %g, %g; want %g, %g", z, i64, f64, wantI, wantF)
+		}
+		f64, _ = bigfloat.Frac(new(big.Float), big.NewFloat(z)).Float64()
+		if f64 != wantF || math.Signbit(f64) != math.Signbit(wantF) {
+			t.Errorf("Frac(%g) = %g, want %g", z, f64, wantF)
+		}
+		// Aliased with the argument.
+		zz := big.NewFloat(z)
+		if f64, _ = bigfloat.Frac(zz, zz).Float64(); f64 != wantF {
+			t.Errorf("Frac(%g) in place = %g, want %g", z, f64, wantF)
+		}
+		zz = big.NewFloat(z)
+		i, f = bigfloat.Modf(zz, new(big.Float), zz)
+		i64, _ = i.Float64()
+		f64, _ = f.Float64()
+		if i64 != wantI || f64 != wantF {
+			t.Errorf("Modf(%g) in place = %g, %g; want %g, %g", z, i64, f64, wantI, wantF)
+		}
+	}
+	// The fractional part is exact even when the integer part is rounded.
+	z := parse("12345.0625", 100)
+	i, f := bigfloat.Modf(new(big.Float).SetPrec(4), new(big.Float), z)
+	if i.Cmp(big.NewFloat(12288)) != 0 || f.Cmp(big.NewFloat(0.0625)) != 0 {
+		t.Errorf("Modf(12345.0625) with 4-bit integer part = %g, %g; want 12288, 0.0625", i, f)
+	}
+	// In place, neither allocates.
+	z = parse("123456789.987654321", 200)
+	x, y := new(big.Float).SetPrec(200), new(big.Float).SetPrec(200)
+	allocs := testing.AllocsPerRun(10, func() {
+		bigfloat.Frac(x.Set(z), x)
+		bigfloat.Modf(x.Set(z), y, x)
+		bigfloat.Modf(y, x.Set(z), x)
+	})
+	if allocs != 0 {
+		t.Errorf("in-place Frac and Modf allocate %v times", allocs)
+	}
+	i, f = bigfloat.Modf(x.Set(z), y, x)
+	if want := parse("123456789", 200); i.Cmp(want) != 0 {
+		t.Errorf("Modf(123456789.987654321) in place: integer part %g, want %g", i, want)
+	}
+	if want := new(big.Float).SetPrec(200).Sub(z, i); f.Cmp(want) != 0 {
+		t.Errorf("Modf(123456789.987654321) in place: fractional part %g, want %g", f, want)
+	}
+	i, f = bigfloat.Modf(y, x.Set(z), x)
+	if want := new(big.Float).SetPrec(200).Sub(z, i); i.Cmp(parse("123456789", 200)) != 0 || f.Cmp(want) != 0 {
+		t.Errorf("Modf(123456789.987654321) into z's fractional part = %g, %g; want 123456789, %g", i, f, want)
+	}
+	if got, want := bigfloat.Frac(x.Set(z), x), new(big.Float).SetPrec(200).Sub(z, i); got.Cmp(want) != 0 {
+		t.Errorf("Frac(123456789.987654321) in place = %g, want %g", got, want)
+	}
+	expectNaN(t, "Frac(Inf)", func() { bigfloat.Frac(new(big.Float), big.NewFloat(math.Inf(1))) })
+	expectNaN(t, "Modf(-Inf)", func() { bigfloat.Modf(new(big.Float), new(big.Float), big.NewFloat(math.Inf(-1))) })
+}
+
+func TestRoundInt(t *testing.T) {
+	modes := []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
+	for _, test := range []struct {
+		z    string
+		want [6]int64
+	}{
+		{"0", [...]int64{0, 0, 0, 0, 0, 0}},
+		{"7", [...]int64{7, 7, 7, 7, 7, 7}},
+		{"-7", [...]int64{-7, -7, -7, -7, -7, -7}},
+		{"2.5", [...]int64{2, 3, 2, 3, 2, 3}},
+		{"-2.5", [...]int64{-2, -3, -2, -3, -3, -2}},
+		{"3.5", [...]int64{4, 4, 3, 4, 3, 4}},
+		{"0.25", [...]int64{0, 0, 0, 1, 0, 1}},
+		{"-0.75", [...]int64{-1, -1, 0, -1, -1, 0}},
+		{"-1.0000001", [...]int64{-1, -1, -1, -2, -2, -1}},
+	} {
+		z := parse(test.z, 100)
+		for _, mode := range modes {
+			got := bigfloat.RoundInt(new(big.Int), z, mode)
+			if got.Cmp(big.NewInt(test.want[mode])) != 0 {
+				t.Errorf("RoundInt(%s, %v) = %v, want %d", test.z, mode, got, test.want[mode])
+			}
+		}
+		if got := bigfloat.FloorInt(new(big.Int), z); got.Cmp(big.NewInt(test.want[big.ToNegativeInf])) != 0 {
+			t.Errorf("FloorInt(%s) = %v, want %d", test.z, got, test.want[big.ToNegativeInf])
+		}
+		if got := bigfloat.CeilInt(new(big.Int), z); got.Cmp(big.NewInt(test.want[big.ToPositiveInf])) != 0 {
+			t.Errorf("CeilInt(%s) = %v, want %d", test.z, got, test.want[big.ToPositiveInf])
+		}
+	}
+	// Large values.
+	z := parse("1e40", 200)
+	z.Add(z, big.NewFloat(-0.5))
+	want, _ := new(big.Int).SetString("9999999999999999999999999999999999999999", 10)
+	if got := bigfloat.FloorInt(new(big.Int), z); got.Cmp(want) != 0 {
+		t.Errorf("FloorInt(1e40 - 0.5) = %v, want %v", got, want)
+	}
+	want.Add(want, big.NewInt(1))
+	if got := bigfloat.RoundInt(new(big.Int), z, big.ToNearestEven); got.Cmp(want) != 0 {
+		t.Errorf("RoundInt(1e40 - 0.5) = %v, want %v", got, want)
+	}
+	expectNaN(t, "FloorInt(Inf)", func() { bigfloat.FloorInt(new(big.Int), big.NewFloat(math.Inf(1))) })
+	expectNaN(t, "CeilInt(-Inf)", func() { bigfloat.CeilInt(new(big.Int), big.NewFloat(math.Inf(-1))) })
+	expectNaN(t, "RoundInt(Inf)", func() { bigfloat.RoundInt(new(big.Int), big.NewFloat(math.Inf(1)), big.ToZero) })
+}
+
+func TestRoundPlaces(t *testing.T) {
+	for _, test := range []struct {
+		z      float64
+		places int
+		base   int
+		mode   big.RoundingMode
+		want   string
+	}{
+		// Ties which are exact in decimal but not in binary.
+		{2.675, 2, 10, big.ToNearestEven, "2.68"},
+		{2.665, 2, 10, big.ToNearestEven, "2.66"},
+		{2.665, 2, 10, big.ToNearestAway, "2.67"},
+		{1.005, 2, 10, big.ToNearestAway, "1.01"},
+		{1.005, 2, 10, big.ToNearestEven, "1"},
+		{-2.675, 2, 10, big.ToNearestEven, "-2.68"},
+		{0.125, 2, 10, big.ToNearestEven, "0.12"},
+		{0.375, 2, 10, big.ToNearestEven, "0.38"},
+		// Values just below a multiple in binary are not truncated.
+		{0.29, 2, 10, big.ToZero, "0.29"},
+		{0.29, 2, 10, big.ToPositiveInf, "0.29"},
+		{-0.29, 2, 10, big.ToNegativeInf, "-0.29"},
+		{4.35, 1, 10, big.ToNegativeInf, "4.3"},
+		// Values not near a tie.
+		{2.674, 2, 10, big.ToNearestEven, "2.67"},
+		{2.6749, 2, 10, big.ToNearestAway, "2.67"},
+		{2.671, 2, 10, big.ToZero, "2.67"},
+		{2.671, 2, 10, big.AwayFromZero, "2.68"},
+		{-2.671, 2, 10, big.ToNegativeInf, "-2.68"},
+		{-2.671, 2, 10, big.ToPositiveInf, "-2.67"},
+		{-2.671, 2, 10, big.ToZero, "-2.67"},
+		{0.004, 2, 10, big.ToNearestEven, "0"},
+		{0.004, 2, 10, big.AwayFromZero, "0.01"},
+		// Negative places.
+		{1250, -2, 10, big.ToNearestEven, "1200"},
+		{1350, -2, 10, big.ToNearestEven, "1400"},
+		{1349.99, -2, 10, big.ToNearestAway, "1300"},
+		{-1250, -2, 10, big.ToNearestAway, "-1300"},
+		{999, -3, 10, big.ToNearestEven, "1000"},
+		// Other bases.
+		{0.4375, 2, 16, big.ToNearestEven, "0.4375"},
+		{0.40625, 1, 16, big.ToNearestEven, "0.375"},
+		{0.8125, 2, 2, big.ToNearestEven, "0.75"},
+		{2.0 / 3, 2, 3, big.ToNearestEven, "0.666666666666666666666666666666666666666666"},
+		{1.5, 0, 10, big.ToNearestEven, "2"},
+		{2.5, 0, 10, big.ToNearestEven, "2"},
+	} {
+		z := big.NewFloat(test.z)
+		want := parse(test.want, 53)
+		got := bigfloat.RoundPlaces(new(big.Float), z, test.places, test.base, test.mode)
+		if got.Cmp(want) != 0 {
+			t.Errorf("RoundPlaces(%g, %d, %d, %v) = %g, want %g", test.z, test.places, test.base, test.mode, got, want)
+		}
+		if got.Prec() != 53 {
+			t.Errorf("RoundPlaces(%g, %d, %d, %v) has precision %d, want 53", test.z, test.places, test.base, test.mode, got.Prec())
+		}
+	}
+}
+
+func TestRoundPlacesPrecision(t *testing.T) {
+	// At high precision, a value just below a tie is distinguishable from it.
+	z := parse("2.67499999999999999999", 200)
+	got := bigfloat.RoundPlaces(new(big.Float), z, 2, 10, big.ToNearestAway)
+	if want := parse("2.67", 200); got.Cmp(want) != 0 {
+		t.Errorf("RoundPlaces(2.67499999999999999999, 2) = %g, want %g", got, want)
+	}
+	// The same digits in float64 are the tie.
+	got = bigfloat.RoundPlaces(new(big.Float), big.NewFloat(2.67499999999999999999), 2, 10, big.ToNearestAway)
+	if want := parse("2.68", 53); got.Cmp(want) != 0 {
+		t.Errorf("RoundPlaces(float64(2.67499999999999999999), 2) = %g, want %g", got, want)
+	}
+	// The result is rounded to o's precision.
+	z = parse("2.675", 300)
+	got = bigfloat.RoundPlaces(new(big.Float).SetPrec(250), z, 2, 10, big.ToNearestEven)
+	if want := parse("2.68", 250); got.Cmp(want) != 0 || got.Prec() != 250 {
+		t.Errorf("RoundPlaces(2.675, 2) to 250 bits = %g (prec %d), want %g", got, got.Prec(), want)
+	}
+	got = bigfloat.RoundPlaces(new(big.Float).SetPrec(20).SetMode(big.ToZero), z, 2, 10, big.ToNearestEven)
+	if want := new(big.Float).SetPrec(20).SetMode(big.ToZero).Set(parse("2.68", 100)); got.Cmp(want) != 0 {
+		t.Errorf("RoundPlaces(2.675, 2) to 20 bits = %g, want %g", got, want)
+	}
+}
+
+func TestRoundPlacesFineUnit(t *testing.T) {
+	// When many multiples of half a unit round to z, z denotes none of them in
+	// particular, and there is no tie.
+	z := big.NewFloat(0.6760883075538685)
+	got := bigfloat.RoundPlaces(new(big.Float).SetPrec(100), z, 20, 10, big.ToNearestAway)
+	if want := parse("0.67608830755386850608", 100); got.Cmp(want) != 0 {
+		t.Errorf("RoundPlaces(%g, 20, ToNearestAway) = %.22f, want %.22f", z, got, want)
+	}
+	z = big.NewFloat(0.020346177533865816)
+	got = bigfloat.RoundPlaces(new(big.Float).SetPrec(100), z, 20, 10, big.ToPositiveInf)
+	if want := parse("0.02034617753386581616", 100); got.Cmp(want) != 0 {
+		t.Errorf("RoundPlaces(%g, 20, ToPositiveInf) = %.22f, want %.22f", z, got, want)
+	}
+	if got := bigfloat.RoundPlaces(new(big.Float), z, 20, 10, big.ToPositiveInf); got.Cmp(z) < 0 {
+		t.Errorf("RoundPlaces(%g, 20, ToPositiveInf) = %.22f is less than z", z, got)
+	}
+	// Likewise when z's precision is coarser than the unit.
+	z = new(big.Float).SetPrec(8).SetFloat64(2.671875)
+	for _, mode := range []big.RoundingMode{big.ToPositiveInf, big.AwayFromZero} {
+		got := bigfloat.RoundPlaces(new(big.Float).SetPrec(100), z, 2, 10, mode)
+		if want := parse("2.68", 100); got.Cmp(want) != 0 {
+			t.Errorf("RoundPlaces(%g at prec 8, 2, %v) = %g, want %g", z, mode, got, want)
+		}
+		if got := bigfloat.RoundPlaces(new(big.Float), z, 2, 10, mode); got.Cmp(z) < 0 {
+			t.Errorf("RoundPlaces(%g at prec 8, 2, %v) = %g is less than z", z, mode, got)
+		}
+	}
+	for _, mode := range []big.RoundingMode{big.ToNegativeInf, big.ToZero} {
+		got := bigfloat.RoundPlaces(new(big.Float).SetPrec(100), z, 2, 10, mode)
+		if want := parse("2.67", 100); got.Cmp(want) != 0 {
+			t.Errorf("RoundPlaces(%g at prec 8, 2, %v) = %g, want %g", z, mode, got, want)
+		}
+	}
+	// A value z denotes is still not allowed to put a directed mode on the
+	// wrong side of z's exact value.
+	z = big.NewFloat(0.29)
+	got = bigfloat.RoundPlaces(new(big.Float).SetPrec(100), z, 2, 10, big.ToZero)
+	if want := parse("0.28", 100); got.Cmp(want) != 0 {
+		t.Errorf("RoundPlaces(0.29, 2, ToZero) to 100 bits = %g, want %g", got, want)
+	}
+}
+
+func TestRoundSignificant(t *testing.T) {
+	for _, test := range []struct {
+		z      float64
+		digits int
+		base   int
+		mode   big.RoundingMode
+		want   string
+	}{
+		{123456.789, 6, 10, big.ToNearestEven, "123457"},
+		{123456.789, 2, 10, big.ToNearestEven, "120000"},
+		{123456.789, 12, 10, big.ToNearestEven, "123456.789"},
+		{0.000123456, 3, 10, big.ToNearestEven, "0.000123"},
+		{-0.000123456, 3, 10, big.ToNegativeInf, "-0.000124"},
+		{9.9996, 4, 10, big.ToNearestEven, "10"},
+		{1234.5, 4, 10, big.ToNearestEven, "1234"},
+		{1235.5, 4, 10, big.ToNearestEven, "1236"},
+		{0.1, 1, 10, big.ToZero, "0.1"},
+		{0.01, 1, 10, big.ToNearestEven, "0.01"},
+		{1000, 1, 10, big.ToZero, "1000"},
+		{999.5, 3, 10, big.ToNearestAway, "1000"},
+		{1e300, 3, 10, big.ToNearestEven, "1e300"},
+		{1.23456e-300, 3, 10, big.ToNearestEven, "1.23e-300"},
+		{255.5, 2, 16, big.ToNearestEven, "256"},
+		{0.3, 3, 2, big.ToNearestEven, "0.3125"},
+	} {
+		z := big.NewFloat(test.z)
+		want := parse(test.want, 53)
+		got := bigfloat.RoundSignificant(new(big.Float), z, test.digits, test.base, test.mode)
+		if got.Cmp(want) != 0 {
+			t.Errorf("RoundSignificant(%g, %d, %d, %v) = %g, want %g", test.z, test.digits, test.base, test.mode, got, want)
+		}
+	}
+}
+
+func TestRoundPlacesSpecialValues(t *testing.T) {
+	for _, z := range []float64{0, math.Copysign(0, -1), math.Inf(1), math.Inf(-1)} {
+		got := bigfloat.RoundPlaces(new(big.Float), big.NewFloat(z), 2, 10, big.ToNearestEven)
+		if f, _ := got.Float64(); f != z || math.Signbit(f) != math.Signbit(z) {
+			t.Errorf("RoundPlaces(%g, 2) = %g, want %g", z, got, z)
+		}
+		got = bigfloat.RoundSignificant(new(big.Float), big.NewFloat(z), 2, 10, big.ToNearestEven)
+		if f, _ := got.Float64(); f != z || math.Signbit(f) != math.Signbit(z) {
+			t.Errorf("RoundSignificant(%g, 2) = %g, want %g", z, got, z)
+		}
+	}
+	for _, test := range []struct {
+		name string
+		f    func()
+	}{
+		{"RoundPlaces base 1", func() { bigfloat.RoundPlaces(new(big.Float), big.NewFloat(1), 2, 1, big.ToNearestEven) }},
+		{"RoundPlaces base 37", func() { bigfloat.RoundPlaces(new(big.Float), big.NewFloat(1), 2, 37, big.ToNearestEven) }},
+		{"RoundSignificant digits 0", func() { bigfloat.RoundSignificant(new(big.Float), big.NewFloat(1), 0, 10, big.ToNearestEven) }},
+		{"RoundSignificant base 0", func() { bigfloat.RoundSignificant(new(big.Float), big.NewFloat(1), 2, 0, big.ToNearestEven) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", test.name)
+				}
+			}()
+			test.f()
+		}()
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkRoundPlaces(b *testing.B) {
+	for _, prec := range []uint{53, 1e3} {
+		z := parse("12345.6789012345678901234567890123456789", prec)
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.RoundPlaces(o, z, 2, 10, big.ToNearestEven)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+)
+
+// sumSeries returns the sum of a convergent series to precision wp along with
+// the binary exponent of the largest term or partial sum, so that the
+// absolute error in the sum is about 2**(mag - wp). The first term is t, which
+// sumSeries modifies; next(t, k) must update t from term k-1 to term k in
+// place. The terms must be unimodal in magnitude, as are those of any
+// hypergeometric series, so that the sum ends once a term falls below
+// 2**(mag - wp) or is exactly zero.
+func sumSeries(t *big.Float, wp uint, next func(t *big.Float, k int64)) (*big.Float, int) {
+	s := new(big.Float).SetPrec(wp).Set(t)
+	if t.Sign() == 0 {
+		return s, math.MinInt32
+	}
+	mag := t.MantExp(nil)
+	for k := int64(1); ; k++ {
+		next(t, k)
+		if t.Sign() == 0 {
+			return s, mag
+		}
+		s.Add(s, t)
+		e := t.MantExp(nil)
+		if e > mag {
+			mag = e
+		}
+		if s.Sign() != 0 && s.MantExp(nil) > mag {
+			mag = s.MantExp(nil)
+		}
+		if e < mag-int(wp) {
+			return s, mag
+		}
+	}
+}
+
+// sumAsymp returns the sum of an asymptotic series to precision wp, with t
+// and next as for sumSeries. It adds terms while they decrease in magnitude.
+// If a term falls below 2**-wp relative to the partial sum, it returns the sum
+// and true; if the terms start to grow first, the series cannot reach
+// precision wp, and it returns nil and false.
+func sumAsymp(t *big.Float, wp uint, next func(t *big.Float, k int64)) (*big.Float, bool) {
+	s := new(big.Float).SetPrec(wp).Set(t)
+	last := t.MantExp(nil)
+	for k := int64(1); ; k++ {
+		next(t, k)
+		if t.Sign() == 0 {
+			return s, true
+		}
+		e := t.MantExp(nil)
+		if e > last {
+			return nil, false
+		}
+		last = e
+		s.Add(s, t)
+		if e < s.MantExp(nil)-int(wp) {
+			return s, true
+		}
+	}
+}
+
+// ziv evaluates f at increasing working precisions until the result is
+// accurate to prec bits and returns it. f(wp) must return its result along
+// with the binary exponent of the largest quantity in its computation, so that
+// the absolute error in the result is about 2**(mag - wp), or mag =
+// math.MinInt32 if the result is exactly zero, including when it underflows.
+//
+// A zero result with any other mag means that cancellation may have consumed
+// every bit, so ziv doubles the working precision and tries again. After
+// zivMaxDoublings such retries, it accepts the zero as exact.
+func ziv(prec uint, f func(wp uint) (*big.Float, int)) *big.Float {
+	wp := prec + 32
+	zeros := 0
+	for {
+		r, mag := f(wp)
+		if r.Sign() == 0 {
+			if mag == math.MinInt32 || zeros == zivMaxDoublings {
+				return r
+			}
+			zeros++
+			wp *= 2
+			continue
+		}
+		lost := mag - r.MantExp(nil)
+		if r.IsInf() || int(wp)-lost >= int(prec)+16 {
+			return r
+		}
+		wp = prec + 32 + uint(lost)
+	}
+}
+
+// zivMaxDoublings is the number of times ziv doubles the working precision
+// on a zero result before taking it as exact.
+const zivMaxDoublings = 6
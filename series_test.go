@@ -0,0 +1,111 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestSumSeries(t *testing.T) {
+	// e = Σ 1/k!
+	const prec = 300
+	v := new(big.Float)
+	s, mag := sumSeries(new(big.Float).SetPrec(prec).SetInt64(1), prec, func(t *big.Float, k int64) {
+		t.Quo(t, v.SetInt64(k))
+	})
+	want := new(big.Float).SetPrec(prec).Set(ConstE.cached(prec))
+	if d := new(big.Float).Sub(s, want); d.Sign() != 0 && d.MantExp(nil) > -prec+4 {
+		t.Errorf("Σ 1/k! = %g, want %g", s, want)
+	}
+	if mag != 2 {
+		t.Errorf("Σ 1/k! magnitude = %d, want 2", mag)
+	}
+	// exp(-20) = Σ (-20)**k/k! cancels; the magnitude reports the loss.
+	x := big.NewFloat(-20)
+	s, mag = sumSeries(new(big.Float).SetPrec(prec).SetInt64(1), prec, func(t *big.Float, k int64) {
+		t.Mul(t, x)
+		t.Quo(t, v.SetInt64(k))
+	})
+	if lost := mag - s.MantExp(nil); lost < 50 || lost > 60 {
+		t.Errorf("Σ (-20)**k/k! lost %d bits, want about 55", lost)
+	}
+	// A terminating series ends at its first zero term: (1+1)**3.
+	s, _ = sumSeries(new(big.Float).SetPrec(prec).SetInt64(1), prec, func(t *big.Float, k int64) {
+		t.Mul(t, v.SetInt64(4-k))
+		t.Quo(t, v.SetInt64(k))
+	})
+	if s.Cmp(big.NewFloat(8)) != 0 {
+		t.Errorf("Σ C(3, k) = %g, want 8", s)
+	}
+	if _, mag := sumSeries(new(big.Float), prec, nil); mag != math.MinInt32 {
+		t.Errorf("empty series magnitude = %d, want MinInt32", mag)
+	}
+}
+
+func TestSumAsymp(t *testing.T) {
+	// Σ (-1)**k k!/x**k is the asymptotic series for x exp(x) E₁(x). Its
+	// smallest term is about exp(-x), so it reaches 100 bits for x = 100 but
+	// not for x = 10.
+	v := new(big.Float)
+	for _, test := range []struct {
+		x  float64
+		ok bool
+	}{
+		{100, true},
+		{10, false},
+	} {
+		x := big.NewFloat(test.x)
+		_, ok := sumAsymp(new(big.Float).SetPrec(100).SetInt64(1), 100, func(t *big.Float, k int64) {
+			t.Mul(t, v.SetInt64(-k))
+			t.Quo(t, x)
+		})
+		if ok != test.ok {
+			t.Errorf("asymptotic series at %g: got ok=%t, want %t", test.x, ok, test.ok)
+		}
+	}
+}
+
+func TestZiv(t *testing.T) {
+	// 1 - (1 - 2**-200) loses 200 bits, which ziv must recover.
+	var tries int
+	r := ziv(100, func(wp uint) (*big.Float, int) {
+		tries++
+		e := new(big.Float).SetMantExp(big.NewFloat(1), -200)
+		u := new(big.Float).SetPrec(wp).Sub(&gonep, e)
+		return u.Sub(&gonep, u), 1
+	})
+	want := new(big.Float).SetMantExp(big.NewFloat(1), -200)
+	if r.Cmp(want) != 0 {
+		t.Errorf("ziv result %g, want %g", r, want)
+	}
+	if tries < 2 {
+		t.Errorf("ziv made %d tries, expected a retry", tries)
+	}
+}
+
+func TestZivZero(t *testing.T) {
+	// An underflow is returned at once.
+	var tries int
+	r := ziv(100, func(wp uint) (*big.Float, int) {
+		tries++
+		return new(big.Float).SetPrec(wp), math.MinInt32
+	})
+	if r.Sign() != 0 || tries != 1 {
+		t.Errorf("ziv on underflow returned %g after %d tries, want 0 after 1", r, tries)
+	}
+	// An exact zero which looks like cancellation is retried a bounded
+	// number of times.
+	tries = 0
+	var last uint
+	r = ziv(100, func(wp uint) (*big.Float, int) {
+		tries++
+		last = wp
+		return new(big.Float).SetPrec(wp), 1
+	})
+	if r.Sign() != 0 || tries != zivMaxDoublings+1 {
+		t.Errorf("ziv on exact zero returned %g after %d tries, want 0 after %d", r, tries, zivMaxDoublings+1)
+	}
+	if want := uint(100+32) << zivMaxDoublings; last != want {
+		t.Errorf("ziv on exact zero reached precision %d, want %d", last, want)
+	}
+}
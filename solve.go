@@ -0,0 +1,369 @@
+package bigfloat
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrNoConvergence is the error, possibly wrapped, that Newton, Halley, and
+// Brent return when they do not find a root to the requested precision within
+// their iteration limits or when an iterate leaves the domain they can handle.
+var ErrNoConvergence = errors.New("bigfloat: root finder did not converge")
+
+// ErrNotBracketed is the error Brent returns, possibly wrapped, when the
+// function does not have opposite signs at the ends of the initial interval.
+var ErrNotBracketed = errors.New("bigfloat: root is not bracketed")
+
+// RootStats describes the work a root finder did.
+type RootStats struct {
+	// Iterations is the number of steps taken, each of which evaluates the
+	// function once.
+	Iterations int
+	// Step is an estimate of the absolute error in the result: the
+	// magnitude of the last correction for Newton and Halley, or half the
+	// width of the final bracket for Brent. It is nil if the root finder
+	// stopped before taking any step.
+	Step *big.Float
+	// Converged reports whether the result is accurate to the precision of
+	// the output. It is true exactly when the error is nil.
+	Converged bool
+}
+
+// rootGuard is the number of guard bits root finders use beyond the output
+// precision.
+const rootGuard = 32
+
+// Newton sets o to a root of f near x0 using Newton's method and returns o
+// along with statistics about the iteration. f(y, dy, x) must set y to f(x)
+// and dy to f'(x), each to its own precision, without modifying x.
+//
+// The working precision starts low and grows with the number of correct bits,
+// which doubles at each step once the iteration converges, so most steps are
+// much cheaper than the last. Newton stops when a step at full working
+// precision changes the estimate by less than half an ulp of o. If that does
+// not happen within maxIter steps, or if f'(x) is zero or the estimate becomes
+// infinite, then Newton returns an error wrapping ErrNoConvergence and sets o
+// to the last estimate. If maxIter is not positive, a default limit is used.
+// If o's precision is zero, then it is given x0's precision, or 64 if x0's
+// precision is also zero.
+func Newton(o *big.Float, f func(y, dy, x *big.Float), x0 *big.Float, maxIter int) (*big.Float, RootStats, error) {
+	y, dy := new(big.Float), new(big.Float)
+	return rootPolish(o, x0, maxIter, 2, "Newton", func(d, x *big.Float) error {
+		wp := x.Prec()
+		f(y.SetPrec(wp), dy.SetPrec(wp), x)
+		if y.Sign() == 0 {
+			d.SetInt64(0)
+			return nil
+		}
+		if dy.Sign() == 0 {
+			return fmt.Errorf("%w: Newton: derivative is zero", ErrNoConvergence)
+		}
+		d.SetPrec(wp).Quo(y, dy)
+		return nil
+	})
+}
+
+// Halley sets o to a root of f near x0 using Halley's method and returns o
+// along with statistics about the iteration. f(y, dy, d2y, x) must set y to
+// f(x), dy to f'(x), and d2y to f″(x), each to its own precision, without
+// modifying x.
+//
+// Halley behaves like Newton, except that the number of correct bits triples
+// at each step once the iteration converges. It returns an error wrapping
+// ErrNoConvergence under the same conditions, with the denominator
+// 2f'(x)² - f(x)f″(x) in place of f'(x).
+func Halley(o *big.Float, f func(y, dy, d2y, x *big.Float), x0 *big.Float, maxIter int) (*big.Float, RootStats, error) {
+	y, dy, d2y := new(big.Float), new(big.Float), new(big.Float)
+	t := new(big.Float)
+	return rootPolish(o, x0, maxIter, 3, "Halley", func(d, x *big.Float) error {
+		wp := x.Prec()
+		f(y.SetPrec(wp), dy.SetPrec(wp), d2y.SetPrec(wp), x)
+		if y.Sign() == 0 {
+			d.SetInt64(0)
+			return nil
+		}
+		// d = 2 y y' / (2 y'² - y y'')
+		t.SetPrec(wp).Mul(y, d2y)
+		d.SetPrec(wp).Mul(dy, dy)
+		d.Sub(d.Add(d, d), t)
+		if d.Sign() == 0 {
+			return fmt.Errorf("%w: Halley: denominator is zero", ErrNoConvergence)
+		}
+		t.Mul(y, dy)
+		d.Quo(t.Add(t, t), d)
+		return nil
+	})
+}
+
+// rootPolish performs Newton or Halley, where step sets d to the correction
+// to subtract from x at x's precision and order is the order of convergence.
+func rootPolish(o, x0 *big.Float, maxIter, order int, name string, step func(d, x *big.Float) error) (*big.Float, RootStats, error) {
+	if o.Prec() == 0 {
+		if x0.Prec() == 0 {
+			o.SetPrec(64)
+		} else {
+			o.SetPrec(x0.Prec())
+		}
+	}
+	if maxIter <= 0 {
+		maxIter = newtonMaxIter
+	}
+	p := o.Prec()
+	if x0.IsInf() {
+		return o.Set(x0), RootStats{}, fmt.Errorf("%w: %s: initial estimate is infinite", ErrNoConvergence, name)
+	}
+	full := p + rootGuard
+	// good estimates the number of correct bits in x. It starts small so that
+	// a poor guess doesn't cost steps at full precision.
+	good := uint(1)
+	x := new(big.Float).SetPrec(full).Set(x0)
+	d := new(big.Float)
+	var st RootStats
+	for st.Iterations < maxIter {
+		wp := good * uint(order)
+		if wp > p {
+			wp = p
+		}
+		wp += rootGuard
+		x.SetPrec(wp)
+		st.Iterations++
+		if err := step(d, x); err != nil {
+			st.Step = new(big.Float).SetPrec(p).Abs(d)
+			return o.Set(x), st, err
+		}
+		x.Sub(x, d)
+		if x.IsInf() {
+			st.Step = new(big.Float).SetPrec(p).Abs(d)
+			return o.Set(x), st, fmt.Errorf("%w: %s: iterate is infinite", ErrNoConvergence, name)
+		}
+		// The step is about the error in the previous estimate, so the
+		// number of bits it leaves unchanged is the number that were correct.
+		c := int(wp)
+		if d.Sign() != 0 && x.Sign() != 0 {
+			c = x.MantExp(nil) - d.MantExp(nil)
+		}
+		if wp == full && (d.Sign() == 0 || c >= int(p)+1) {
+			st.Step = new(big.Float).SetPrec(p).Abs(d)
+			st.Converged = true
+			return o.Set(x), st, nil
+		}
+		good = 1
+		if c > 1 {
+			good = uint(c)
+		}
+		if good > wp {
+			good = wp
+		}
+	}
+	st.Step = new(big.Float).SetPrec(p).Abs(d)
+	return o.Set(x), st, fmt.Errorf("%w: %s: no convergence after %d iterations", ErrNoConvergence, name, maxIter)
+}
+
+// Brent sets o to a root of f in the interval between a and b using Brent's
+// method and returns o along with statistics about the iteration. f(y, x) must
+// set y to f(x) to y's precision without modifying x. f(a) and f(b) must have
+// opposite signs, or one of them must be zero, or else Brent returns an error
+// wrapping ErrNotBracketed.
+//
+// Brent keeps a bracket around a sign change of f, combining inverse quadratic
+// interpolation and secant steps with bisection so that it converges
+// superlinearly for smooth functions and never more slowly than bisection.
+// Bisection between values of very different magnitudes splits their binary
+// exponents instead, so that roots near zero are found quickly. Brent stops
+// when the bracket is no wider than an ulp of o. If that does not happen
+// within maxIter steps, then Brent returns an error wrapping ErrNoConvergence
+// and sets o to the best estimate. If maxIter is not positive, then a default
+// limit proportional to o's precision is used. If o's precision is zero, then
+// it is given the greater of the precisions of a and b, or 64 if both are
+// zero. f is always evaluated at o's precision plus some guard bits.
+func Brent(o *big.Float, f func(y, x *big.Float), a, b *big.Float, maxIter int) (*big.Float, RootStats, error) {
+	if o.Prec() == 0 {
+		if p := greatestPrec(a, b); p != 0 {
+			o.SetPrec(p)
+		} else {
+			o.SetPrec(64)
+		}
+	}
+	p := o.Prec()
+	if maxIter <= 0 {
+		maxIter = 4*int(p) + 256
+	}
+	wp := p + rootGuard
+	if a.IsInf() || b.IsInf() {
+		return o.Set(a), RootStats{}, fmt.Errorf("%w: Brent: interval is not finite", ErrNotBracketed)
+	}
+	nf := func() *big.Float { return new(big.Float).SetPrec(wp) }
+	xa, xb := nf().Set(a), nf().Set(b)
+	fa, fb := nf(), nf()
+	f(fa, xa)
+	f(fb, xb)
+	st := RootStats{Iterations: 2}
+	switch {
+	case fa.Sign() == 0:
+		st.Step, st.Converged = new(big.Float), true
+		return o.Set(xa), st, nil
+	case fb.Sign() == 0:
+		st.Step, st.Converged = new(big.Float), true
+		return o.Set(xb), st, nil
+	case fa.Sign() == fb.Sign():
+		return o.Set(xb), st, fmt.Errorf("%w: Brent: f(a) and f(b) have the same sign", ErrNotBracketed)
+	}
+	// xb is the best estimate, and the root is between it and xc. xa is the
+	// previous value of xb. e is the step before last.
+	xc, fc := nf().Set(xa), nf().Set(fa)
+	d := nf().Sub(xb, xa)
+	e := nf().Set(d)
+	m, tol := nf(), nf()
+	s, q, r, t := nf(), nf(), nf(), nf()
+	for ; st.Iterations < maxIter; st.Iterations++ {
+		if fb.Sign() == fc.Sign() {
+			xc.Set(xa)
+			fc.Set(fa)
+			d.Sub(xb, xa)
+			e.Set(d)
+		}
+		if cmpAbs(fc, fb) < 0 {
+			xa.Set(xb)
+			xb.Set(xc)
+			xc.Set(xa)
+			fa.Set(fb)
+			fb.Set(fc)
+			fc.Set(fa)
+		}
+		brentTol(tol, xb, xc, p)
+		m.Sub(xc, xb)
+		m.SetMantExp(m, -1)
+		if fb.Sign() == 0 || cmpAbs(m, tol) <= 0 {
+			st.Step, st.Converged = new(big.Float).SetPrec(p).Abs(m), true
+			return o.Set(xb), st, nil
+		}
+		bisect := true
+		if cmpAbs(e, tol) >= 0 && cmpAbs(fa, fb) > 0 {
+			// Interpolate, with s/q the step.
+			t.Quo(fb, fa)
+			if xa.Cmp(xc) == 0 {
+				// Secant.
+				s.Mul(m, t)
+				s.Add(s, s)
+				q.Sub(&gonep, t)
+			} else {
+				// Inverse quadratic.
+				q.Quo(fa, fc)
+				r.Quo(fb, fc)
+				// s = t (2m q (q - r) - (xb - xa)(r - 1))
+				s.Sub(q, r)
+				s.Mul(s, q)
+				s.Mul(s, m)
+				s.Add(s, s)
+				u := nf().Sub(xb, xa)
+				u.Mul(u, r.Sub(r, &gonep))
+				s.Sub(s, u)
+				s.Mul(s, t)
+				// q = (q - 1)(r - 1)(t - 1)
+				q.Sub(q, &gonep)
+				q.Mul(q, r)
+				q.Mul(q, t.Sub(t, &gonep))
+			}
+			if s.Sign() > 0 {
+				q.Neg(q)
+			} else {
+				s.Neg(s)
+			}
+			// Accept the step if 2s < min(3mq - |tol q|, |e q|).
+			lim := nf().Mul(m, q)
+			lim.Mul(lim, big.NewFloat(3))
+			u := nf().Mul(tol, q)
+			lim.Sub(lim, u.Abs(u))
+			if v := nf().Mul(e, q); v.Abs(v).Cmp(lim) < 0 {
+				lim.Set(v)
+			}
+			if u.Add(s, s).Cmp(lim) < 0 {
+				e.Set(d)
+				d.Quo(s, q)
+				bisect = false
+			}
+		}
+		if bisect {
+			brentBisect(d, xb, xc, m)
+			e.Set(d)
+		}
+		xa.Set(xb)
+		fa.Set(fb)
+		if cmpAbs(d, tol) > 0 {
+			xb.Add(xb, d)
+		} else if m.Sign() > 0 {
+			xb.Add(xb, tol)
+		} else {
+			xb.Sub(xb, tol)
+		}
+		f(fb, xb)
+	}
+	st.Step = new(big.Float).SetPrec(p).Sub(xc, xb)
+	st.Step.Abs(st.Step).SetMantExp(st.Step, -1)
+	return o.Set(xb), st, fmt.Errorf("%w: Brent: no convergence after %d iterations", ErrNoConvergence, maxIter)
+}
+
+// brentTol sets tol to half an ulp of xb at precision p. If xb is zero, then
+// it uses the point that brentBisect would split toward zero from xc instead,
+// so that the tolerance shrinks along with the bracket.
+func brentTol(tol, xb, xc *big.Float, p uint) {
+	e := int64(xb.MantExp(nil))
+	if xb.Sign() == 0 {
+		e = brentGallop(xc)
+	}
+	e -= int64(p)
+	if e < big.MinExp {
+		e = big.MinExp
+	}
+	tol.SetMantExp(&ghalfp, int(e))
+}
+
+// brentBisect sets d to the step from xb to the point that splits the
+// bracket between xb and xc, where m = (xc - xb)/2. When the ends differ
+// greatly in magnitude, the split is at the mean of their exponents. When one
+// end is zero, the split is below the other by about as many binades as that
+// end is from 1, and at least 64, so that the distance doubles with each
+// split toward zero. big.Float arithmetic costs time in proportion to the
+// difference in exponents of its operands, so this finds tiny roots much more
+// cheaply than splitting the exponent range at once.
+func brentBisect(d, xb, xc, m *big.Float) {
+	if xb.Sign() == -xc.Sign() && xb.Sign() != 0 {
+		// The bracket contains zero, which is the natural split.
+		d.Neg(xb)
+		return
+	}
+	s := xb.Sign() + xc.Sign()
+	var k int64
+	switch {
+	case xb.Sign() == 0:
+		k = brentGallop(xc)
+	case xc.Sign() == 0:
+		k = brentGallop(xb)
+	default:
+		eb, ec := int64(xb.MantExp(nil)), int64(xc.MantExp(nil))
+		if eb-ec <= 2 && ec-eb <= 2 {
+			d.Set(m)
+			return
+		}
+		k = (eb + ec) >> 1
+	}
+	x := new(big.Float).SetMantExp(big.NewFloat(float64(s)), int(k))
+	d.Sub(x, xb)
+}
+
+// brentGallop returns the exponent at which to split the bracket between zero
+// and the nonzero x.
+func brentGallop(x *big.Float) int64 {
+	e := int64(x.MantExp(nil))
+	k := e - 64
+	if e > 0 {
+		k -= e
+	} else {
+		k += e
+	}
+	if k < big.MinExp {
+		k = big.MinExp
+	}
+	return k
+}
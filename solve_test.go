@@ -0,0 +1,215 @@
+package bigfloat_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+// cubic sets y, dy, and d2y to x³ - 2x - 5 and its derivatives, each at its
+// own precision.
+func cubic(y, dy, d2y, x *big.Float) {
+	x2 := new(big.Float).SetPrec(y.Prec()).Mul(x, x)
+	y.Mul(x2, x)
+	y.Sub(y, new(big.Float).SetPrec(y.Prec()).Add(x, x))
+	y.Sub(y, big.NewFloat(5))
+	if dy != nil {
+		dy.Mul(x2, big.NewFloat(3))
+		dy.Sub(dy, big.NewFloat(2))
+	}
+	if d2y != nil {
+		d2y.Mul(x, big.NewFloat(6))
+	}
+}
+
+// cubicRoot is the real root of x³ - 2x - 5, computed with Cardano's formula.
+func cubicRoot(prec uint) *big.Float {
+	// x = ∛(5/2 + √(643/108)) + ∛(5/2 - √(643/108))
+	wp := prec + 64
+	s := new(big.Float).SetPrec(wp).SetInt64(643)
+	s.Quo(s, big.NewFloat(108))
+	s.Sqrt(s)
+	h := new(big.Float).SetPrec(wp).SetFloat64(2.5)
+	u := new(big.Float).SetPrec(wp).Add(h, s)
+	v := new(big.Float).SetPrec(wp).Sub(h, s)
+	u = bigfloat.Cbrt(u, u)
+	v = bigfloat.Cbrt(v, v)
+	return u.Add(u, v)
+}
+
+func TestNewton(t *testing.T) {
+	for _, prec := range []uint{53, 1000, 10000} {
+		want := cubicRoot(prec)
+		f := func(y, dy, x *big.Float) { cubic(y, dy, nil, x) }
+		got, st, err := bigfloat.Newton(new(big.Float).SetPrec(prec), f, big.NewFloat(2), 0)
+		if err != nil || !st.Converged {
+			t.Errorf("Newton(x³-2x-5) at prec %d: %v, stats %+v", prec, err, st)
+			continue
+		}
+		if !closeTo(got, want, prec-2) {
+			t.Errorf("Newton(x³-2x-5) at prec %d = %g, want %g", prec, got, want)
+		}
+		if st.Iterations > 30 {
+			t.Errorf("Newton(x³-2x-5) at prec %d took %d iterations", prec, st.Iterations)
+		}
+	}
+	// An exact root stops immediately at full precision.
+	lin := func(y, dy, x *big.Float) {
+		y.Sub(x, big.NewFloat(3))
+		dy.SetInt64(1)
+	}
+	got, st, err := bigfloat.Newton(new(big.Float).SetPrec(100), lin, big.NewFloat(3), 0)
+	if err != nil || got.Cmp(big.NewFloat(3)) != 0 || st.Step.Sign() != 0 {
+		t.Errorf("Newton(x-3) from 3 = %g, stats %+v, error %v; want exactly 3", got, st, err)
+	}
+	// Precision defaults to the initial estimate's.
+	got, _, err = bigfloat.Newton(new(big.Float), lin, new(big.Float).SetPrec(200).SetInt64(1), 0)
+	if err != nil || got.Prec() != 200 || got.Cmp(big.NewFloat(3)) != 0 {
+		t.Errorf("Newton(x-3) = %g with precision %d, error %v; want 3 with precision 200", got, got.Prec(), err)
+	}
+}
+
+func TestNewtonErrors(t *testing.T) {
+	// x² + 1 has no real roots.
+	f := func(y, dy, x *big.Float) {
+		y.Mul(x, x)
+		y.Add(y, big.NewFloat(1))
+		dy.Add(x, x)
+	}
+	got, st, err := bigfloat.Newton(new(big.Float).SetPrec(100), f, big.NewFloat(0.5), 50)
+	if !errors.Is(err, bigfloat.ErrNoConvergence) || st.Converged || st.Iterations != 50 {
+		t.Errorf("Newton(x²+1) = %g, stats %+v, error %v; want ErrNoConvergence after 50 iterations", got, st, err)
+	}
+	// A zero derivative.
+	f = func(y, dy, x *big.Float) {
+		y.Mul(x, x)
+		y.Sub(y, big.NewFloat(1))
+		dy.Add(x, x)
+	}
+	if _, _, err := bigfloat.Newton(new(big.Float).SetPrec(100), f, new(big.Float), 0); !errors.Is(err, bigfloat.ErrNoConvergence) {
+		t.Errorf("Newton(x²-1) from 0: error %v, want ErrNoConvergence", err)
+	}
+	if _, _, err := bigfloat.Newton(new(big.Float).SetPrec(100), f, new(big.Float).SetInf(false), 0); !errors.Is(err, bigfloat.ErrNoConvergence) {
+		t.Errorf("Newton(x²-1) from +Inf: error %v, want ErrNoConvergence", err)
+	}
+}
+
+func TestHalley(t *testing.T) {
+	for _, prec := range []uint{53, 1000, 10000} {
+		want := cubicRoot(prec)
+		got, st, err := bigfloat.Halley(new(big.Float).SetPrec(prec), cubic, big.NewFloat(2), 0)
+		if err != nil || !st.Converged {
+			t.Errorf("Halley(x³-2x-5) at prec %d: %v, stats %+v", prec, err, st)
+			continue
+		}
+		if !closeTo(got, want, prec-2) {
+			t.Errorf("Halley(x³-2x-5) at prec %d = %g, want %g", prec, got, want)
+		}
+		_, nst, _ := bigfloat.Newton(new(big.Float).SetPrec(prec), func(y, dy, x *big.Float) { cubic(y, dy, nil, x) }, big.NewFloat(2), 0)
+		if st.Iterations > nst.Iterations {
+			t.Errorf("Halley(x³-2x-5) at prec %d took %d iterations, more than Newton's %d", prec, st.Iterations, nst.Iterations)
+		}
+	}
+	// A constant function makes the denominator zero.
+	f := func(y, dy, d2y, x *big.Float) {
+		y.SetInt64(1)
+		dy.SetInt64(0)
+		d2y.SetInt64(0)
+	}
+	if _, _, err := bigfloat.Halley(new(big.Float).SetPrec(100), f, big.NewFloat(1), 0); !errors.Is(err, bigfloat.ErrNoConvergence) {
+		t.Errorf("Halley(1): error %v, want ErrNoConvergence", err)
+	}
+}
+
+func TestBrent(t *testing.T) {
+	f := func(y, x *big.Float) { cubic(y, nil, nil, x) }
+	for _, prec := range []uint{53, 1000} {
+		want := cubicRoot(prec)
+		got, st, err := bigfloat.Brent(new(big.Float).SetPrec(prec), f, big.NewFloat(2), big.NewFloat(3), 0)
+		if err != nil || !st.Converged {
+			t.Errorf("Brent(x³-2x-5) at prec %d: %v, stats %+v", prec, err, st)
+			continue
+		}
+		if !closeTo(got, want, prec-2) {
+			t.Errorf("Brent(x³-2x-5) at prec %d = %g, want %g", prec, got, want)
+		}
+		if st.Iterations > int(prec) {
+			t.Errorf("Brent(x³-2x-5) at prec %d took %d iterations", prec, st.Iterations)
+		}
+	}
+	// A step function is found to within an ulp by bisection.
+	third := new(big.Float).SetPrec(200).Quo(big.NewFloat(1), big.NewFloat(3))
+	step := func(y, x *big.Float) {
+		y.SetInt64(int64(x.Cmp(third)))
+	}
+	got, st, err := bigfloat.Brent(new(big.Float).SetPrec(100), step, big.NewFloat(0), big.NewFloat(1), 0)
+	if err != nil || !closeTo(got, third, 98) {
+		t.Errorf("Brent(step at 1/3) = %g, stats %+v, error %v; want %g", got, st, err, third)
+	}
+	// Roots at or near zero.
+	tiny := new(big.Float).SetMantExp(big.NewFloat(1), -100000)
+	near := func(y, x *big.Float) { y.Sub(x, tiny) }
+	got, st, err = bigfloat.Brent(new(big.Float).SetPrec(100), near, big.NewFloat(-1), big.NewFloat(1), 0)
+	if err != nil || !closeTo(got, tiny, 98) || st.Iterations > 500 {
+		t.Errorf("Brent(x - 2**-100000) = %v, stats %+v, error %v; want 2**-100000", got.Text('p', 0), st, err)
+	}
+	cube := func(y, x *big.Float) { y.Mul(x, x).Mul(y, x) }
+	got, _, err = bigfloat.Brent(new(big.Float).SetPrec(100), cube, big.NewFloat(-1), big.NewFloat(2), 0)
+	if err != nil || got.Sign() != 0 {
+		t.Errorf("Brent(x³) = %v, error %v; want 0", got.Text('p', 0), err)
+	}
+	// A root at an end of the interval.
+	got, _, err = bigfloat.Brent(new(big.Float), cube, new(big.Float).SetPrec(80), big.NewFloat(2), 0)
+	if err != nil || got.Sign() != 0 || got.Prec() != 80 {
+		t.Errorf("Brent(x³) on [0, 2] = %g with precision %d, error %v; want 0 with precision 80", got, got.Prec(), err)
+	}
+}
+
+func TestBrentErrors(t *testing.T) {
+	f := func(y, x *big.Float) { cubic(y, nil, nil, x) }
+	if _, _, err := bigfloat.Brent(new(big.Float).SetPrec(100), f, big.NewFloat(3), big.NewFloat(4), 0); !errors.Is(err, bigfloat.ErrNotBracketed) {
+		t.Errorf("Brent(x³-2x-5) on [3, 4]: error %v, want ErrNotBracketed", err)
+	}
+	inf := new(big.Float).SetInf(false)
+	if _, _, err := bigfloat.Brent(new(big.Float).SetPrec(100), f, big.NewFloat(2), inf, 0); !errors.Is(err, bigfloat.ErrNotBracketed) {
+		t.Errorf("Brent(x³-2x-5) on [2, Inf]: error %v, want ErrNotBracketed", err)
+	}
+	got, st, err := bigfloat.Brent(new(big.Float).SetPrec(1000), f, big.NewFloat(2), big.NewFloat(3), 5)
+	if !errors.Is(err, bigfloat.ErrNoConvergence) || st.Converged || st.Iterations != 5 {
+		t.Errorf("Brent(x³-2x-5) with 5 iterations = %g, stats %+v, error %v; want ErrNoConvergence", got, st, err)
+	}
+	if got.Cmp(big.NewFloat(2)) < 0 || got.Cmp(big.NewFloat(3)) > 0 || st.Step == nil || st.Step.Sign() <= 0 {
+		t.Errorf("Brent(x³-2x-5) with 5 iterations = %g, step %v; want an estimate in [2, 3]", got, st.Step)
+	}
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkNewton(b *testing.B) {
+	f := func(y, dy, x *big.Float) { cubic(y, dy, nil, x) }
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Newton(o, f, big.NewFloat(2), 0)
+			}
+		})
+	}
+}
+
+func BenchmarkBrent(b *testing.B) {
+	f := func(y, x *big.Float) { cubic(y, nil, nil, x) }
+	for _, prec := range []uint{1e2, 1e3} {
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Brent(o, f, big.NewFloat(2), big.NewFloat(3), 0)
+			}
+		})
+	}
+}
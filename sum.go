@@ -0,0 +1,130 @@
+package bigfloat
+
+import (
+	"math/big"
+	"math/bits"
+	"sort"
+)
+
+// Sum sets o to the sum of xs rounded once to o's precision according to o's
+// rounding mode and returns o. The result is the same regardless of the order
+// or magnitudes of the elements. If o's precision is zero, then it is given
+// the greatest of the precisions of the elements of xs. As in IEEE 754, an
+// exact zero sum is +0 unless o rounds toward -Inf or every term is -0, and
+// the sum of an empty slice is +0. Panics with ErrNaN if xs contains
+// infinities with opposite signs. o may be an element of xs.
+func Sum(o *big.Float, xs []*big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(greatestPrec(xs...))
+	}
+	return sumTerms(o, xs, "Sum")
+}
+
+// Dot sets o to the dot product Σ xs[i]·ys[i], computed exactly and rounded
+// once to o's precision according to o's rounding mode, and returns o. If o's
+// precision is zero, then it is given the greatest of the precisions of the
+// elements of xs and ys. Panics with ErrNaN if any product is 0·Inf or if the
+// products include infinities with opposite signs, and panics if xs and ys
+// have different lengths. o may be an element of xs or ys.
+func Dot(o *big.Float, xs, ys []*big.Float) *big.Float {
+	if len(xs) != len(ys) {
+		panic("bigfloat: Dot: slices have different lengths")
+	}
+	if o.Prec() == 0 {
+		p := greatestPrec(xs...)
+		if q := greatestPrec(ys...); q > p {
+			p = q
+		}
+		o.SetPrec(p)
+	}
+	ts := make([]*big.Float, len(xs))
+	for i, x := range xs {
+		y := ys[i]
+		if x.IsInf() && y.Sign() == 0 || x.Sign() == 0 && y.IsInf() {
+			panic(ErrNaN{msg: "Dot: zero times infinity"})
+		}
+		ts[i] = fmaMul(x, y)
+	}
+	return sumTerms(o, ts, "Dot")
+}
+
+// sumTerms sets o to the correctly rounded sum of ts, naming the function as
+// name in panics.
+func sumTerms(o *big.Float, ts []*big.Float, name string) *big.Float {
+	nz := make([]*big.Float, 0, len(ts))
+	var inf *big.Float
+	pos, neg := false, false
+	for _, t := range ts {
+		switch {
+		case t.IsInf():
+			if inf != nil && inf.Signbit() != t.Signbit() {
+				panic(ErrNaN{msg: name + ": sum of opposite infinities"})
+			}
+			inf = t
+		case t.Sign() == 0:
+			if t.Signbit() {
+				neg = true
+			} else {
+				pos = true
+			}
+		default:
+			nz = append(nz, t)
+		}
+	}
+	switch {
+	case inf != nil:
+		return o.SetInf(inf.Signbit())
+	case len(nz) == 0:
+		o.SetInt64(0)
+		if neg && (!pos || o.Mode() == big.ToNegativeInf) {
+			o.Neg(o)
+		}
+		return o
+	}
+	sort.Slice(nz, func(i, j int) bool { return nz[i].MantExp(nil) > nz[j].MantExp(nil) })
+	return sumSorted(o, nz)
+}
+
+// sumSorted sets o to the correctly rounded sum of the finite nonzero ts,
+// which are sorted by decreasing exponent.
+//
+// The terms are split at the first gap in their exponents large enough that
+// everything below it cannot reach the rounding boundaries of o near the exact
+// sum A of everything above it. Then only the sign of the lower part matters,
+// unless A is zero and the lower part is the entire sum. Without such a gap,
+// the exact sum of all terms has a modest precision.
+func sumSorted(o *big.Float, ts []*big.Float) *big.Float {
+	nb := bits.Len(uint(len(ts)))
+	gap := int(o.Prec()) + nb + 4
+	top := ts[0].MantExp(nil)
+	lo := lowBit(ts[0])
+	k := 1
+	for ; k < len(ts); k++ {
+		if ts[k].MantExp(nil) <= lo-gap {
+			break
+		}
+		if b := lowBit(ts[k]); b < lo {
+			lo = b
+		}
+	}
+	a := new(big.Float).SetPrec(uint(top+nb-lo) + 2).SetMode(o.Mode())
+	for _, t := range ts[:k] {
+		a.Add(a, t)
+	}
+	if k == len(ts) {
+		return o.Set(a)
+	}
+	if a.Sign() == 0 {
+		return sumSorted(o, ts[k:])
+	}
+	// The lower part has magnitude less than 2**(lo-gap+nb). Any value of
+	// that size and the same sign rounds the same way when added to a.
+	d := sumSorted(new(big.Float).SetPrec(2), ts[k:])
+	if d.Sign() == 0 {
+		return o.Set(a)
+	}
+	e := lo - gap + nb
+	d.SetMantExp(d.SetInt64(int64(d.Sign())), e)
+	s := new(big.Float).SetPrec(uint(a.MantExp(nil)-e)+2).Add(a, d)
+	return o.Set(s)
+}
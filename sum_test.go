@@ -0,0 +1,210 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+// refSum returns the sum of the finite xs rounded to prec bits under mode,
+// using exact integer arithmetic scaled by the lowest bit among them.
+func refSum(xs []*big.Float, prec uint, mode big.RoundingMode) *big.Float {
+	lo := 0
+	for _, x := range xs {
+		if x.Sign() != 0 {
+			if b := x.MantExp(nil) - int(x.MinPrec()); b < lo {
+				lo = b
+			}
+		}
+	}
+	s := new(big.Int)
+	for _, x := range xs {
+		n, _ := new(big.Float).SetMantExp(x, -lo).Int(nil)
+		s.Add(s, n)
+	}
+	r := new(big.Float).SetPrec(prec).SetMode(mode).SetInt(s)
+	return r.SetMantExp(r, lo)
+}
+
+func TestSumExhaustive(t *testing.T) {
+	xs := smallFloats()
+	for _, mode := range allModes {
+		for _, x := range xs {
+			for _, y := range xs {
+				v := []*big.Float{x, y}
+				want := refSum(v, 3, mode)
+				got := bigfloat.Sum(new(big.Float).SetPrec(3).SetMode(mode), v)
+				if got.Cmp(want) != 0 {
+					t.Errorf("Sum(%g, %g) under %v = %g, want %g", x, y, mode, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSumRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		xs := make([]*big.Float, 1+r.Intn(20))
+		// Narrow exponent ranges make cancellation likely, and wide ones
+		// separate the terms into several groups.
+		spread := []int{4, 100, 1000000}[r.Intn(3)]
+		for j := range xs {
+			xs[j] = new(big.Float).SetPrec(uint(1 + r.Intn(100))).SetFloat64(r.NormFloat64())
+			xs[j].SetMantExp(xs[j], r.Intn(spread)-spread/2)
+			if j > 0 && r.Intn(4) == 0 {
+				// The negation of an earlier term, possibly rounded.
+				xs[j].SetPrec(uint(1 + r.Intn(100))).Neg(xs[r.Intn(j)])
+			}
+		}
+		prec := uint(1 + r.Intn(200))
+		mode := allModes[r.Intn(len(allModes))]
+		want := refSum(xs, prec, mode)
+		got := bigfloat.Sum(new(big.Float).SetPrec(prec).SetMode(mode), xs)
+		if got.Cmp(want) != 0 {
+			t.Errorf("case %d: Sum under %v at prec %d = %v, want %v", i, mode, prec, got.Text('p', 0), want.Text('p', 0))
+		}
+		// The order doesn't matter.
+		r.Shuffle(len(xs), func(i, j int) { xs[i], xs[j] = xs[j], xs[i] })
+		if got2 := bigfloat.Sum(new(big.Float).SetPrec(prec).SetMode(mode), xs); got2.Cmp(got) != 0 {
+			t.Errorf("case %d: shuffled Sum = %v, want %v", i, got2.Text('p', 0), got.Text('p', 0))
+		}
+	}
+}
+
+func TestSum(t *testing.T) {
+	pow2 := func(k int) *big.Float { return new(big.Float).SetMantExp(big.NewFloat(1), k) }
+	one, tiny := big.NewFloat(1), pow2(-1000000)
+	ntiny := new(big.Float).Neg(tiny)
+	cases := []struct {
+		xs   []*big.Float
+		mode big.RoundingMode
+		want *big.Float
+	}{
+		{[]*big.Float{one, tiny}, big.ToPositiveInf, bigfloat.NextUp(new(big.Float), one)},
+		{[]*big.Float{one, tiny}, big.ToNearestEven, one},
+		{[]*big.Float{one, ntiny}, big.ToZero, bigfloat.NextDown(new(big.Float), one)},
+		{[]*big.Float{tiny, one, ntiny}, big.ToPositiveInf, one},
+		{[]*big.Float{pow2(1000000), one, new(big.Float).Neg(pow2(1000000))}, big.ToNearestEven, one},
+		{[]*big.Float{pow2(1000000), tiny, new(big.Float).Neg(pow2(1000000))}, big.ToNearestEven, tiny},
+		// 0.1 + 0.2 - 0.3 in float64 terms is not zero.
+		{[]*big.Float{big.NewFloat(0.1), big.NewFloat(0.2), big.NewFloat(-0.3)}, big.ToNearestEven, pow2(-55)},
+	}
+	for _, c := range cases {
+		got := bigfloat.Sum(new(big.Float).SetPrec(53).SetMode(c.mode), c.xs)
+		if got.Cmp(c.want) != 0 {
+			t.Errorf("Sum(%v) under %v = %v, want %v", c.xs, c.mode, got.Text('p', 0), c.want.Text('p', 0))
+		}
+	}
+	// Precision defaults to the greatest of the elements'.
+	xs := []*big.Float{big.NewFloat(1), new(big.Float).SetPrec(200).SetInt64(2)}
+	if got := bigfloat.Sum(new(big.Float), xs); got.Prec() != 200 || got.Cmp(big.NewFloat(3)) != 0 {
+		t.Errorf("Sum(1, 2) = %g with precision %d, want 3 with precision 200", got, got.Prec())
+	}
+	// Aliasing.
+	xs = []*big.Float{big.NewFloat(1), big.NewFloat(2), big.NewFloat(3)}
+	if got := bigfloat.Sum(xs[1], xs); got.Cmp(big.NewFloat(6)) != 0 {
+		t.Errorf("Sum(1, 2, 3) in place = %g, want 6", got)
+	}
+}
+
+func TestSumSpecialValues(t *testing.T) {
+	inf := big.NewFloat(math.Inf(1))
+	ninf := big.NewFloat(math.Inf(-1))
+	pz := new(big.Float).SetPrec(53)
+	nz := new(big.Float).Neg(pz)
+	if got := bigfloat.Sum(new(big.Float), []*big.Float{big.NewFloat(1), ninf, ninf}); !got.IsInf() || !got.Signbit() {
+		t.Errorf("Sum(1, -Inf, -Inf) = %g, want -Inf", got)
+	}
+	expectNaN(t, "Sum(Inf, -Inf)", func() { bigfloat.Sum(new(big.Float), []*big.Float{inf, big.NewFloat(1), ninf}) })
+	zeros := []struct {
+		xs   []*big.Float
+		mode big.RoundingMode
+		neg  bool
+	}{
+		{nil, big.ToNegativeInf, false},
+		{[]*big.Float{nz, nz}, big.ToNearestEven, true},
+		{[]*big.Float{nz, pz}, big.ToNearestEven, false},
+		{[]*big.Float{nz, pz}, big.ToNegativeInf, true},
+		{[]*big.Float{pz, pz}, big.ToNegativeInf, false},
+		{[]*big.Float{big.NewFloat(1), big.NewFloat(-1)}, big.ToNearestEven, false},
+		{[]*big.Float{big.NewFloat(1), big.NewFloat(-1)}, big.ToNegativeInf, true},
+	}
+	for _, c := range zeros {
+		got := bigfloat.Sum(new(big.Float).SetPrec(53).SetMode(c.mode), c.xs)
+		if got.Sign() != 0 || got.Signbit() != c.neg {
+			t.Errorf("Sum(%v) under %v = %g, want zero with sign bit %t", c.xs, c.mode, got, c.neg)
+		}
+	}
+}
+
+func TestDot(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 100; i++ {
+		n := r.Intn(10)
+		xs, ys := make([]*big.Float, n), make([]*big.Float, n)
+		prods := make([]*big.Float, n)
+		for j := range xs {
+			xs[j] = new(big.Float).SetPrec(uint(1 + r.Intn(100))).SetFloat64(r.NormFloat64())
+			ys[j] = new(big.Float).SetPrec(uint(1 + r.Intn(100))).SetFloat64(r.NormFloat64())
+			ys[j].SetMantExp(ys[j], r.Intn(400)-200)
+			prods[j] = new(big.Float).SetPrec(xs[j].Prec()+ys[j].Prec()).Mul(xs[j], ys[j])
+		}
+		mode := allModes[r.Intn(len(allModes))]
+		want := refSum(prods, 64, mode)
+		got := bigfloat.Dot(new(big.Float).SetPrec(64).SetMode(mode), xs, ys)
+		if got.Cmp(want) != 0 {
+			t.Errorf("case %d: Dot under %v = %g, want %g", i, mode, got, want)
+		}
+	}
+	// Products that cancel except for their low parts.
+	x := new(big.Float).SetMantExp(big.NewFloat(1), -60)
+	x.SetPrec(61)
+	y := new(big.Float).Neg(x)
+	x.Add(x, big.NewFloat(1))
+	y.Add(y, big.NewFloat(1))
+	got := bigfloat.Dot(new(big.Float).SetPrec(53), []*big.Float{x, big.NewFloat(1)}, []*big.Float{y, big.NewFloat(-1)})
+	if want := new(big.Float).SetMantExp(big.NewFloat(-1), -120); got.Cmp(want) != 0 {
+		t.Errorf("Dot((1+2**-60, 1), (1-2**-60, -1)) = %g, want %g", got, want)
+	}
+	inf := big.NewFloat(math.Inf(1))
+	if got := bigfloat.Dot(new(big.Float), []*big.Float{inf, big.NewFloat(1)}, []*big.Float{big.NewFloat(-2), big.NewFloat(1)}); !got.IsInf() || !got.Signbit() {
+		t.Errorf("Dot((Inf, 1), (-2, 1)) = %g, want -Inf", got)
+	}
+	expectNaN(t, "Dot((Inf), (0))", func() { bigfloat.Dot(new(big.Float), []*big.Float{inf}, []*big.Float{new(big.Float)}) })
+	expectNaN(t, "Dot((Inf, Inf), (1, -1))", func() {
+		bigfloat.Dot(new(big.Float), []*big.Float{inf, inf}, []*big.Float{big.NewFloat(1), big.NewFloat(-1)})
+	})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Dot with different lengths didn't panic")
+			}
+		}()
+		bigfloat.Dot(new(big.Float), []*big.Float{inf}, nil)
+	}()
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkSum(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	for _, prec := range []uint{1e2, 1e3, 1e4} {
+		xs := make([]*big.Float, 1000)
+		for i := range xs {
+			xs[i] = new(big.Float).SetPrec(prec).SetFloat64(r.NormFloat64())
+			xs[i].Quo(xs[i], bigfloat.Pi(new(big.Float).SetPrec(prec)))
+		}
+		o := new(big.Float).SetPrec(prec)
+		b.Run(fmt.Sprintf("%v", prec), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				bigfloat.Sum(o, xs)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+)
+
+// sinPi sets o to sin(πx) to o's precision and returns o. If o's precision is
+// zero, then it is given the precision of x. The reduction of x modulo 2 is
+// exact, so the result has full relative precision even near the zeros of
+// sin(πx). Panics with ErrNaN if x is infinite.
+func sinPi(o, x *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(x.Prec())
+	}
+	if x.IsInf() {
+		panic(ErrNaN{msg: "sinPi: argument is infinite"})
+	}
+	prec := o.Prec()
+
+	// Reduce |x| modulo 2. The bits of the result are a subset of those of
+	// x, so the subtraction is exact.
+	neg := x.Signbit()
+	t := new(big.Float).Abs(x)
+	k := quicksh(new(big.Float), t, -1)
+	Round(k, k, big.ToNegativeInf)
+	t.Sub(t, quicksh(k, k, 1))
+	// Use sin(π(t+1)) = -sin(πt) and sin(π(1-t)) = sin(πt) to bring t into
+	// [0, 1/2]. Again, both subtractions are exact.
+	if t.Cmp(&gonep) >= 0 {
+		t.Sub(t, &gonep)
+		neg = !neg
+	}
+	if t.Cmp(&ghalfp) > 0 {
+		t.Sub(&gonep, t)
+	}
+	if t.Sign() == 0 {
+		return o.SetInt64(0)
+	}
+
+	wp := prec + 64
+	y := new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+	y.Mul(y, t)
+	sinReduced(o, y)
+	if neg {
+		o.Neg(o)
+	}
+	return o
+}
+
+// cosPi sets o to cos(πx) to o's precision and returns o. If o's precision is
+// zero, then it is given the precision of x. As with sinPi, the result has full
+// relative precision near the zeros of cos(πx). Panics with ErrNaN if x is
+// infinite.
+func cosPi(o, x *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(x.Prec())
+	}
+	if x.IsInf() {
+		panic(ErrNaN{msg: "cosPi: argument is infinite"})
+	}
+	if x.Sign() == 0 || x.MantExp(nil) < -1 {
+		// |x| < 1/4, so cos(πx) > 0.7 and we can use the versine directly.
+		wp := o.Prec() + 64
+		y := new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+		y.Mul(y, x)
+		v := versin(y, y)
+		return o.Sub(&gonep, v)
+	}
+	// cos(πx) = sin(π(|x| + 1/2)). Since |x| ≥ 1/4, the addition is exact
+	// with two more bits than x spans.
+	p := x.Prec()
+	if e := x.MantExp(nil); e > 0 && uint(e) > p {
+		p = uint(e)
+	}
+	t := new(big.Float).SetPrec(p + 2).Abs(x)
+	t.Add(t, &ghalfp)
+	return sinPi(o, t)
+}
+
+// sinReduced sets o to sin(y) to o's precision for 0 ≤ y ≤ π/2 and returns o.
+func sinReduced(o, y *big.Float) *big.Float {
+	if y.Sign() == 0 {
+		return o.SetInt64(0)
+	}
+	prec := o.Prec()
+	v := versin(new(big.Float).SetPrec(prec+32), y)
+	// sin(y) = √(v (2 - v))
+	w := new(big.Float).SetPrec(prec+32).Sub(&gtwop, v)
+	v.Mul(v, w)
+	return o.Sqrt(v)
+}
+
+// versin sets o to the versine 1 - cos(y) to o's precision for |y| ≤ π/2 and
+// returns o. The result has full relative precision even for small y.
+func versin(o, y *big.Float) *big.Float {
+	if y.Sign() == 0 {
+		return o.SetInt64(0)
+	}
+	prec := o.Prec()
+	// Halve y s times, sum the Taylor series of the versine at the reduced
+	// argument, and then undo the halving using
+	//    versin(2a) = 2 versin(a) (2 - versin(a)),
+	// which, unlike the double-angle formula for cosine, involves no
+	// cancellation.
+	s := int(math.Sqrt(float64(prec))) / 2
+	wp := prec + uint(s) + 32
+	a := quicksh(new(big.Float), y, -s).SetPrec(wp)
+	a.Mul(a, a)
+	// versin(a) = Σ (-1)**(k+1) a**2k / (2k)!
+	term := quicksh(new(big.Float).SetPrec(wp), a, -1)
+	u := new(big.Float).SetPrec(wp).Set(term)
+	for k := int64(1); term.MantExp(nil)-u.MantExp(nil) > -int(wp); k++ {
+		term.Mul(term, a)
+		term.Quo(term, new(big.Float).SetInt64((2*k+1)*(2*k+2)))
+		term.Neg(term)
+		u.Add(u, term)
+	}
+	w := new(big.Float).SetPrec(wp)
+	for i := 0; i < s; i++ {
+		w.Sub(&gtwop, u)
+		u.Mul(u, w)
+		quicksh(u, u, 1)
+	}
+	return o.Set(u)
+}
+
+// sinCos returns sin(x) and cos(x) to precision wp for finite x. The results
+// have full absolute precision, and sin(x) also has full relative precision
+// near the zeros of sin(x).
+func sinCos(x *big.Float, wp uint) (s, c *big.Float) {
+	r, m := reducePi(x, wp)
+	neg := r.Signbit()
+	r.Abs(r)
+	s = sinReduced(new(big.Float).SetPrec(wp), r)
+	// cos(r) = sin(π/2 - r)
+	c = new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+	quicksh(c, c, -1)
+	c.Sub(c, r)
+	sinReduced(c, c.Abs(c))
+	if neg {
+		s.Neg(s)
+	}
+	// sin(mπ + r) = (-1)**m sin(r), and likewise for cos.
+	if !quicksh(m, m, -1).IsInt() {
+		s.Neg(s)
+		c.Neg(c)
+	}
+	return s, c
+}
+
+// atan returns arctan(t) to precision wp for t ≥ 0, including +Inf. The
+// result has full relative precision.
+func atan(t *big.Float, wp uint) *big.Float {
+	switch {
+	case t.Sign() == 0:
+		return new(big.Float).SetPrec(wp)
+	case t.IsInf():
+		r := new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+		return quicksh(r, r, -1)
+	case t.Cmp(&gonep) > 0:
+		// arctan t = π/2 - arctan(1/t), and the result is at least π/4.
+		u := new(big.Float).SetPrec(wp+2).Quo(&gonep, t)
+		r := new(big.Float).SetPrec(wp + 2).Set(cachedPi(wp + 2))
+		quicksh(r, r, -1)
+		r.Sub(r, atan(u, wp+2))
+		return r.SetPrec(wp)
+	case t.MantExp(nil) < -int(wp)/2:
+		// arctan t = t - t³/3 + ..., so t is already correct to wp bits.
+		return new(big.Float).SetPrec(wp).Set(t)
+	}
+	guess := new(big.Float).SetPrec(53)
+	if tf, _ := t.Float64(); t.MantExp(nil) > -100 {
+		guess.SetFloat64(math.Atan(tf))
+	} else {
+		guess.Set(t)
+	}
+	// Solve sin θ - t cos θ = 0, whose derivative is cos θ + t sin θ.
+	return newton(func(θ *big.Float) *big.Float {
+		s, c := sinCos(θ, θ.Prec())
+		d := new(big.Float).SetPrec(θ.Prec()).Mul(t, s)
+		d.Add(d, c)
+		s.Sub(s, c.Mul(c, t))
+		return s.Quo(s, d)
+	}, guess, wp)
+}
@@ -0,0 +1,100 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestSinPi(t *testing.T) {
+	for _, x := range []float64{0.125, 0.25, 0.5, 0.75, 1, 1.25, 1.9, 2.5, -0.25, -1.5, -3.1, 1e6 + 0.5, 12345.678} {
+		s, _ := sinPi(new(big.Float).SetPrec(53), big.NewFloat(x)).Float64()
+		want := math.Sin(math.Pi * math.Mod(x, 2))
+		if math.Abs(s-want) > 1e-15 {
+			t.Errorf("sinPi(%g) = %g, want %g", x, s, want)
+		}
+	}
+	// Near an integer n, sin(πx) ≈ (-1)**n π (x-n), which must be accurate
+	// to full relative precision.
+	const prec = 200
+	eps := new(big.Float).SetMantExp(big.NewFloat(1), -150)
+	for _, n := range []int64{1, 2, -7, 1 << 40} {
+		x := new(big.Float).SetPrec(300).SetInt64(n)
+		x.Add(x, eps)
+		s := sinPi(new(big.Float).SetPrec(prec), x)
+		want := new(big.Float).SetPrec(prec).Mul(cachedPi(prec), eps)
+		if n%2 != 0 {
+			want.Neg(want)
+		}
+		d := new(big.Float).Sub(s, want)
+		if d.Sign() != 0 && d.MantExp(nil)-want.MantExp(nil) > -prec+2 {
+			t.Errorf("sinPi(%d + 2**-150) = %g, want %g", n, s, want)
+		}
+	}
+}
+
+func TestCosPi(t *testing.T) {
+	for _, x := range []float64{0, 0.125, 0.25, 0.5, 0.75, 1, 1.25, 1.9, 2.5, -0.2, -1.5, -3.1, 1e6 + 0.25, 12345.678} {
+		c, _ := cosPi(new(big.Float).SetPrec(53), big.NewFloat(x)).Float64()
+		want := math.Cos(math.Pi * math.Mod(x, 2))
+		if math.Abs(c-want) > 1e-15 {
+			t.Errorf("cosPi(%g) = %g, want %g", x, c, want)
+		}
+	}
+	// Near a half-integer n+1/2, cos(πx) ≈ (-1)**(n+1) π (x-n-1/2).
+	const prec = 200
+	eps := new(big.Float).SetMantExp(big.NewFloat(1), -150)
+	for _, n := range []int64{0, 1, -4, 1 << 40} {
+		x := new(big.Float).SetPrec(300).SetInt64(n)
+		x.Add(x, &ghalfp)
+		x.Add(x, eps)
+		c := cosPi(new(big.Float).SetPrec(prec), x)
+		want := new(big.Float).SetPrec(prec).Mul(cachedPi(prec), eps)
+		if n%2 == 0 {
+			want.Neg(want)
+		}
+		d := new(big.Float).Sub(c, want)
+		if d.Sign() != 0 && d.MantExp(nil)-want.MantExp(nil) > -prec+2 {
+			t.Errorf("cosPi(%d + 1/2 + 2**-150) = %g, want %g", n, c, want)
+		}
+	}
+}
+
+func TestSinCos(t *testing.T) {
+	for _, x := range []float64{0, 0.5, 1, 1.5707963267948966, 2, 3.141592653589793, -4, 10, 355, 1e6, -1e15} {
+		s, c := sinCos(big.NewFloat(x), 53)
+		s64, _ := s.Float64()
+		c64, _ := c.Float64()
+		if want := math.Sin(x); math.Abs(s64-want) > 1e-15 {
+			t.Errorf("sin(%g) = %g, want %g", x, s64, want)
+		}
+		if want := math.Cos(x); math.Abs(c64-want) > 1e-15 {
+			t.Errorf("cos(%g) = %g, want %g", x, c64, want)
+		}
+	}
+	// 355 is very close to 113π, so sin(355) is small and must still have
+	// full relative precision.
+	s, _ := sinCos(big.NewFloat(355), 200)
+	want, _ := sinCos(big.NewFloat(355), 400)
+	d := new(big.Float).Sub(s, want)
+	if d.Sign() != 0 && d.MantExp(nil)-want.MantExp(nil) > -200+2 {
+		t.Errorf("sin(355) = %g, want %g", s, want)
+	}
+}
+
+func TestAtan(t *testing.T) {
+	for _, x := range []float64{0, 1e-30, 1e-5, 0.25, 1, 3, 1e8, math.Inf(1)} {
+		a, _ := atan(big.NewFloat(x), 53).Float64()
+		if want := math.Atan(x); math.Abs(a-want) > 1e-15*want {
+			t.Errorf("atan(%g) = %g, want %g", x, a, want)
+		}
+	}
+	// atan(1) = π/4 to full precision.
+	const prec = 500
+	a := atan(big.NewFloat(1), prec)
+	want := quicksh(new(big.Float), cachedPi(prec+10), -2)
+	d := new(big.Float).Sub(a, want)
+	if d.Sign() != 0 && d.MantExp(nil)-want.MantExp(nil) > -prec+2 {
+		t.Errorf("atan(1) = %g, want %g", a, want)
+	}
+}
@@ -0,0 +1,412 @@
+package bigfloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// zetaExactMax is the largest magnitude of an integer s for which Zeta uses
+// the exact values in terms of Bernoulli numbers.
+const zetaExactMax = 1000
+
+// Zeta sets o to the Riemann zeta function ζ(s) to o's precision and returns
+// o. If o's precision is zero, then it is given the precision of s.
+// Zeta(+Inf) = 1, Zeta(0) = -1/2, and Zeta is exactly zero at the negative
+// even integers. For integers up to 1000 in magnitude, the result is the exact
+// value given by the Bernoulli numbers, either a rational or a rational
+// multiple of π**s, rounded to o's precision. Panics with ErrNaN if s is 1,
+// where the limits from either side differ, or if s is -Inf.
+func Zeta(o, s *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		o.SetPrec(s.Prec())
+	}
+	switch {
+	case s.IsInf():
+		if s.Signbit() {
+			panic(ErrNaN{msg: "Zeta: argument is -Inf"})
+		}
+		return o.Set(&gonep)
+	case s.Sign() == 0:
+		return o.Set(&ghalfm)
+	case s.Cmp(&gonep) == 0:
+		panic(ErrNaN{msg: "Zeta: argument is the pole at 1"})
+	case s.IsInt():
+		if s.Signbit() && quicksh(new(big.Float), s, -1).IsInt() {
+			// Trivial zero.
+			return o.SetInt64(0)
+		}
+		if n, acc := s.Int64(); acc == big.Exact && -zetaExactMax <= n && n <= zetaExactMax {
+			return zetaInt(o, n)
+		}
+	}
+	if s.Cmp(&ghalfp) >= 0 {
+		return hurwitz(o, s, &gonep)
+	}
+	prec := o.Prec()
+
+	// ζ(s) = 2**s π**(s-1) sin(πs/2) Γ(1-s) ζ(1-s)
+	// Each factor has full relative precision, but the absolute error in the
+	// exponent of (2π)**s / π becomes relative error in the result.
+	wp := prec + 64
+	if e := s.MantExp(nil); e > 0 {
+		wp += uint(e) + 3
+	}
+	w := new(big.Float).SetPrec(reflectPrec(s, wp)).Sub(&gonep, s)
+	r := Zeta(new(big.Float).SetPrec(wp), w)
+	r.Mul(r, Gamma(new(big.Float).SetPrec(wp), w))
+	r.Mul(r, sinPi(new(big.Float).SetPrec(wp), quicksh(new(big.Float), s, -1)))
+	pi := new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+	lpi := Log(new(big.Float).SetPrec(wp), pi)
+	t := quicksh(new(big.Float), pi, 1)
+	Log(t, t)
+	t.Mul(t, s)
+	t.Sub(t, lpi)
+	r.Mul(r, Exp(t, t))
+	return o.Set(r)
+}
+
+// zetaInt sets o to ζ(n) for an integer n ≠ 0, 1 which is not a negative even
+// integer, with |n| ≤ zetaExactMax.
+func zetaInt(o *big.Float, n int64) *big.Float {
+	if n < 0 {
+		// ζ(-n) = -B_(n+1)/(n+1) for odd n
+		b := bernoulli(int(1-n) / 2)
+		q := new(big.Rat).SetFrac64(1, n-1)
+		return o.SetRat(q.Mul(q, b[(1-n)/2]))
+	}
+	if n%2 == 1 {
+		// Odd positive integers have no closed form.
+		return hurwitz(o, new(big.Float).SetInt64(n), &gonep)
+	}
+	// ζ(2k) = (-1)**(k+1) B_2k (2π)**2k / (2 (2k)!)
+	k := int(n / 2)
+	q := new(big.Rat).Abs(bernoulli(k)[k])
+	d := new(big.Int).MulRange(1, n)
+	q.Mul(q, new(big.Rat).SetFrac(new(big.Int).Lsh(big.NewInt(1), uint(n-1)), d))
+	wp := o.Prec() + 32 + uint(bits.Len64(uint64(n)))
+	r := new(big.Float).SetPrec(wp).Set(cachedPi(wp))
+	r = powInt(r, int(n))
+	r.Mul(r, new(big.Float).SetPrec(wp).SetRat(q))
+	return o.Set(r)
+}
+
+// HurwitzZeta sets o to the Hurwitz zeta function
+//
+//	ζ(s, a) = Σ 1/(k+a)**s, k = 0, 1, 2, ...,
+//
+// or its analytic continuation for s < 1, to o's precision and returns o. If
+// o's precision is zero, then it is given the larger of s's and a's precision.
+// HurwitzZeta(o, s, 1) is the same as Zeta(o, s). HurwitzZeta(+Inf, a) is +Inf,
+// 1, or 0 according to whether a is less than, equal to, or greater than 1.
+// Panics with ErrNaN if s is 1 or -Inf, or if a is not positive and finite.
+func HurwitzZeta(o, s, a *big.Float) *big.Float {
+	if o.Prec() == 0 {
+		if s.Prec() >= a.Prec() {
+			o.SetPrec(s.Prec())
+		} else {
+			o.SetPrec(a.Prec())
+		}
+	}
+	switch {
+	case a.Sign() <= 0 || a.IsInf():
+		panic(ErrNaN{msg: "HurwitzZeta: a is not positive and finite"})
+	case a.Cmp(&gonep) == 0:
+		return Zeta(o, s)
+	case s.IsInf():
+		if s.Signbit() {
+			panic(ErrNaN{msg: "HurwitzZeta: argument is -Inf"})
+		}
+		if a.Cmp(&gonep) < 0 {
+			return o.SetInf(false)
+		}
+		return o.SetInt64(0)
+	case s.Sign() == 0:
+		// ζ(0, a) = 1/2 - a
+		return o.Sub(&ghalfp, a)
+	case s.Cmp(&gonep) == 0:
+		panic(ErrNaN{msg: "HurwitzZeta: argument is the pole at 1"})
+	case a.Cmp(&ghalfp) == 0:
+		// ζ(s, 1/2) = (2**s - 1) ζ(s). This also covers the only zeros of
+		// ζ(s, a) at negative integers s and rational a other than 1.
+		wp := o.Prec() + 64
+		if e := s.MantExp(nil); e < 0 {
+			// 2**s - 1 ≈ s log 2 for small s.
+			wp += uint(-e)
+		} else {
+			wp += uint(e)
+		}
+		z := Zeta(new(big.Float).SetPrec(wp), s)
+		if z.Sign() == 0 {
+			return o.SetInt64(0)
+		}
+		t := new(big.Float).SetPrec(wp).Set(s)
+		t = Pow(new(big.Float).SetPrec(wp), new(big.Float).SetPrec(wp).SetInt64(2), t)
+		t.Sub(t, &gonep)
+		return o.Mul(z, t)
+	}
+	return hurwitz(o, s, a)
+}
+
+// hurwitz sets o to ζ(s, a) for finite s ≠ 1 and finite a > 0, such that the
+// result is not exactly zero, and returns o.
+func hurwitz(o, s, a *big.Float) *big.Float {
+	prec := o.Prec()
+	// As in Polygamma, retry with more precision if the result is much
+	// smaller than the sums we compute it from, which happens for s < 1.
+	r := ziv(prec, func(wp uint) (*big.Float, int) {
+		return hurwitzEM(new(big.Float).SetPrec(wp), s, a)
+	})
+	return o.Set(r)
+}
+
+// hurwitzEM sets o to ζ(s, a) for finite s ≠ 1 and finite a > 0 using the
+// Euler-Maclaurin formula
+//
+//	ζ(s, a) = Σ 1/(k+a)**s + y**(1-s)/(s-1) + 1/(2y**s) + Σ B_2j s(s+1)...(s+2j-2) / ((2j)! y**(s+2j-1)),
+//
+// where the first sum is over 0 ≤ k < n and y = n+a, and returns o along with
+// the binary exponent of the largest quantity in the computation. The
+// absolute error in o is about 2**(mag - o.Prec()).
+func hurwitzEM(o, s, a *big.Float) (*big.Float, int) {
+	wp := o.Prec() + 16
+	sf, _ := s.Float64()
+	af, _ := a.Float64()
+	// Choose n so that the correction terms decrease past 2**-wp before they
+	// start to diverge. For large s, the terms of the defining series fall
+	// off so quickly that it is cheaper to sum them directly.
+	var n int64
+	lim := float64(wp)/5 + math.Abs(sf)/4 + 1
+	if af < lim {
+		if sf > 1 && af*(math.Exp2(float64(wp)/sf)-1)+2 < lim-af {
+			return hurwitzDirect(o, s, a)
+		}
+		n = int64(math.Ceil(lim - af))
+		wp += uint(bits.Len64(uint64(n)))
+	}
+	ns := new(big.Float).SetPrec(wp).Neg(s)
+	sum := new(big.Float).SetPrec(wp)
+	t := new(big.Float).SetPrec(wp)
+	if a.Cmp(&gonep) == 0 {
+		zetaPowSum(sum, ns, n)
+	} else {
+		for k := int64(0); k < n; k++ {
+			t.SetInt64(k)
+			t.Add(t, a)
+			sum.Add(sum, zetaPow(t, ns))
+		}
+	}
+	y := new(big.Float).SetPrec(wp).SetInt64(n)
+	y.Add(y, a)
+	ys := zetaPow(y, ns)
+
+	r := new(big.Float).SetPrec(wp).Sub(s, &gonep)
+	r.Quo(ys, r)
+	r.Mul(r, y)
+	mag := r.MantExp(nil)
+	if e := sum.MantExp(nil); sum.Sign() != 0 && e > mag {
+		mag = e
+	}
+	r.Add(r, sum)
+	r.Add(r, quicksh(t, ys, -1))
+	if e := r.MantExp(nil); e > mag {
+		mag = e
+	}
+
+	// f = s(s+1)...(s+2j-2) / (2j)!, starting from j = 1
+	f := quicksh(new(big.Float), s, -1).SetPrec(wp)
+	pw := new(big.Float).SetPrec(wp).Quo(ys, y)
+	y2 := new(big.Float).SetPrec(wp).Mul(y, y)
+	u := new(big.Float).SetPrec(wp)
+	b := bernoulli(int(wp/7) + 2)
+	for j := 1; ; j++ {
+		if j >= len(b) {
+			b = bernoulli(2 * j)
+		}
+		t.SetRat(b[j])
+		t.Mul(t, f)
+		t.Mul(t, pw)
+		r.Add(r, t)
+		// f is exactly zero once the rising factorial reaches zero, i.e.
+		// when s is an integer no greater than 2-2j.
+		if t.Sign() == 0 || t.MantExp(nil) < mag-int(wp) {
+			break
+		}
+		pw.Quo(pw, y2)
+		u.SetInt64(int64(2*j - 1))
+		f.Mul(f, u.Add(u, s))
+		u.SetInt64(int64(2 * j))
+		f.Mul(f, u.Add(u, s))
+		f.Quo(f, u.SetInt64(int64((2*j+1)*(2*j+2))))
+	}
+	return o.Set(r), mag
+}
+
+// hurwitzDirect sets o to ζ(s, a) for s > 1 by summing the defining series,
+// and returns o along with the binary exponent of the result. It is efficient
+// only when s is large compared to o's precision.
+func hurwitzDirect(o, s, a *big.Float) (*big.Float, int) {
+	wp := o.Prec() + 16
+	ns := new(big.Float).SetPrec(wp).Neg(s)
+	sm1 := new(big.Float).SetPrec(wp).Sub(s, &gonep)
+	sum := new(big.Float).SetPrec(wp)
+	t := new(big.Float).SetPrec(wp)
+	for k := int64(0); ; k++ {
+		t.SetInt64(k)
+		t.Add(t, a)
+		p := zetaPow(t, ns)
+		sum.Add(sum, p)
+		// The rest of the series is at most p (1 + (k+a)/(s-1)).
+		t.Quo(t, sm1)
+		t.Add(t, &gonep)
+		t.Mul(t, p)
+		if t.MantExp(nil) < sum.MantExp(nil)-int(wp) {
+			break
+		}
+	}
+	return o.Set(sum), sum.MantExp(nil)
+}
+
+// zetaPowSum sets sum to Σ k**w for 1 ≤ k ≤ n to sum's precision and returns
+// sum. Since k**w is completely multiplicative in k, only the powers of primes
+// need to be computed in full; the rest are products of earlier powers.
+func zetaPowSum(sum, w *big.Float, n int64) *big.Float {
+	wp := sum.Prec()
+	sum.SetInt64(0)
+	// Any composite k ≤ n is the product of two factors no greater than n/2.
+	pows := make([]*big.Float, n/2+1)
+	t := new(big.Float).SetPrec(wp)
+	for k := int64(1); k <= n; k++ {
+		var p *big.Float
+		if f := smallestFactor(k); f < k {
+			p = new(big.Float).SetPrec(wp).Mul(pows[f], pows[k/f])
+		} else {
+			p = zetaPow(t.SetInt64(k), w)
+		}
+		if k < int64(len(pows)) {
+			pows[k] = p
+		}
+		sum.Add(sum, p)
+	}
+	return sum
+}
+
+// smallestFactor returns the smallest prime factor of k > 1, or 1 if k is 1.
+func smallestFactor(k int64) int64 {
+	if k%2 == 0 {
+		return 2
+	}
+	for f := int64(3); f*f <= k; f += 2 {
+		if k%f == 0 {
+			return f
+		}
+	}
+	return k
+}
+
+// zetaPow returns a new value x**w at x's precision, for x > 0.
+func zetaPow(x, w *big.Float) *big.Float {
+	if n, acc := w.Int64(); acc == big.Exact && -1<<20 <= n && n <= 1<<20 {
+		if n >= 0 {
+			return powInt(x, int(n))
+		}
+		r := powInt(x, int(-n))
+		return r.Quo(&gonep, r)
+	}
+	return Pow(new(big.Float).SetPrec(x.Prec()), x, w)
+}
+
+// zetaLadder returns ζ(s+j) for 0 ≤ j < count to precision wp, for finite
+// s ≥ 1/2 such that no s+j is 1. All of the values share one Euler-Maclaurin
+// sum as in hurwitzEM, since (k+1)**-(s+j+1) = k**-(s+j) / k, which makes the
+// whole ladder hardly more expensive than its first rung.
+func zetaLadder(s *big.Float, count int, wp uint) []*big.Float {
+	zs := make([]*big.Float, count)
+	if count == 0 {
+		return zs
+	}
+	// Past s+j > wp+2, ζ(s+j) = 1 to precision wp, so those rungs need not
+	// affect the choice of n.
+	sf, _ := s.Float64()
+	top := math.Min(sf+float64(count-1), float64(wp)+2)
+	n := int64(math.Ceil(float64(wp)/5 + top/4 + 1))
+	wp2 := wp + 16 + uint(bits.Len64(uint64(n)))
+
+	// cur[k-1] = k**-σ for 1 ≤ k < n, where σ = s+j.
+	ns := new(big.Float).Neg(s)
+	cur := make([]*big.Float, n-1)
+	t := new(big.Float).SetPrec(wp2)
+	for k := int64(1); k < n; k++ {
+		if f := smallestFactor(k); f < k {
+			cur[k-1] = new(big.Float).SetPrec(wp2).Mul(cur[f-1], cur[k/f-1])
+		} else {
+			cur[k-1] = zetaPow(t.SetInt64(k), ns)
+		}
+	}
+	y := new(big.Float).SetPrec(wp2).SetInt64(n)
+	ys := zetaPow(y, ns)
+	y2 := new(big.Float).SetPrec(wp2).Mul(y, y)
+
+	sig := new(big.Float).SetPrec(wp2 + s.Prec()).Set(s)
+	sum := new(big.Float).SetPrec(wp2)
+	r := new(big.Float).SetPrec(wp2)
+	f := new(big.Float).SetPrec(wp2)
+	pw := new(big.Float).SetPrec(wp2)
+	u := new(big.Float).SetPrec(wp2)
+	b := bernoulli(int(wp2/7) + 2)
+	active := len(cur)
+	for j := range zs {
+		if sig.Cmp(t.SetInt64(int64(wp)+2)) > 0 {
+			for ; j < count; j++ {
+				zs[j] = new(big.Float).SetPrec(wp).SetInt64(1)
+			}
+			break
+		}
+		sum.SetInt64(0)
+		for _, p := range cur[:active] {
+			sum.Add(sum, p)
+		}
+		// Terms below 2**-wp2 stay negligible for the rest of the ladder.
+		for active > 1 && cur[active-1].MantExp(nil) < -int(wp2) {
+			active--
+		}
+
+		r.Sub(sig, &gonep)
+		r.Quo(ys, r)
+		r.Mul(r, y)
+		mag := r.MantExp(nil)
+		if e := sum.MantExp(nil); e > mag {
+			mag = e
+		}
+		r.Add(r, sum)
+		r.Add(r, quicksh(t, ys, -1))
+		quicksh(f, sig, -1)
+		pw.Quo(ys, y)
+		for i := 1; ; i++ {
+			if i >= len(b) {
+				b = bernoulli(2 * i)
+			}
+			t.SetRat(b[i])
+			t.Mul(t, f)
+			t.Mul(t, pw)
+			r.Add(r, t)
+			if t.Sign() == 0 || t.MantExp(nil) < mag-int(wp2) {
+				break
+			}
+			pw.Quo(pw, y2)
+			u.SetInt64(int64(2*i - 1))
+			f.Mul(f, u.Add(u, sig))
+			u.SetInt64(int64(2 * i))
+			f.Mul(f, u.Add(u, sig))
+			f.Quo(f, u.SetInt64(int64((2*i+1)*(2*i+2))))
+		}
+		zs[j] = new(big.Float).SetPrec(wp).Set(r)
+
+		for k, p := range cur[:active] {
+			p.Quo(p, t.SetInt64(int64(k+1)))
+		}
+		ys.Quo(ys, y)
+		sig.Add(sig, &gonep)
+	}
+	return zs
+}
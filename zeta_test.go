@@ -0,0 +1,155 @@
+package bigfloat_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/zephyrtronium/bigfloat"
+)
+
+func TestZetaValues(t *testing.T) {
+	const prec = 300
+	wp := uint(prec + 64)
+	pi := bigfloat.Pi(new(big.Float).SetPrec(wp))
+	pi2 := new(big.Float).Mul(pi, pi)
+	for _, test := range []struct {
+		s    float64
+		want *big.Float
+	}{
+		{2, new(big.Float).Quo(pi2, big.NewFloat(6))},
+		{4, new(big.Float).Quo(new(big.Float).Mul(pi2, pi2), big.NewFloat(90))},
+		{3, parse(aperyStr, wp)},
+		{0.5, parse("-1.460354508809586812889499152515298012467229331012581490542886087825530529474500625276419375463356815", 340)},
+		{-0.5, parse("-0.2078862249773545660173067253970493022262", 140)},
+		{-1, new(big.Float).SetPrec(wp).Quo(big.NewFloat(-1), big.NewFloat(12))},
+		{-3, new(big.Float).SetPrec(wp).Quo(big.NewFloat(1), big.NewFloat(120))},
+		{-11, new(big.Float).SetPrec(wp).Quo(big.NewFloat(691), big.NewFloat(32760))},
+	} {
+		p := uint(prec)
+		if test.want.Prec() < p {
+			p = test.want.Prec() - 8
+		}
+		got := bigfloat.Zeta(new(big.Float).SetPrec(p), big.NewFloat(test.s))
+		if !closeTo(got, test.want, p-2) {
+			t.Errorf("Zeta(%g) =\ngot  %g;\nwant %g", test.s, got, test.want)
+		}
+	}
+}
+
+func TestZetaQuarters(t *testing.T) {
+	// ζ(s, 1/4) + ζ(s, 3/4) = (4**s - 2**s) ζ(s) relates the Euler-Maclaurin
+	// sums to the exact values at even integers and to the reflection formula.
+	const prec = 200
+	for _, s := range []float64{2, 3, 6, 0.5, 0.75, 1.5, 7.25, 40, -0.5, -2.5, -13.25, -4, -7} {
+		z := big.NewFloat(s)
+		// The two terms can nearly cancel for negative s, so compute them
+		// with extra precision.
+		got := bigfloat.HurwitzZeta(new(big.Float).SetPrec(prec+64), z, big.NewFloat(0.25))
+		got.Add(got, bigfloat.HurwitzZeta(new(big.Float).SetPrec(prec+64), z, big.NewFloat(0.75)))
+		want := bigfloat.Zeta(new(big.Float).SetPrec(prec+64), z)
+		f := bigfloat.Pow(new(big.Float), new(big.Float).SetPrec(prec+64).SetInt64(4), z)
+		f.Sub(f, bigfloat.Pow(new(big.Float), new(big.Float).SetPrec(prec+64).SetInt64(2), z))
+		want.Mul(want, f)
+		if !closeTo(got, want, prec) {
+			t.Errorf("ζ(%g, 1/4) + ζ(%g, 3/4) =\ngot  %g;\nwant %g", s, s, got, want)
+		}
+	}
+}
+
+func TestHurwitzZetaRecurrence(t *testing.T) {
+	// ζ(s, a) = ζ(s, a+1) + a**-s
+	for _, prec := range []uint{53, 200, 400} {
+		for _, s := range []float64{2, 3.5, 0.25, -1.5, -3, 1e-10, 100.5} {
+			for _, a := range []float64{0.0625, 0.375, 2.5, 17} {
+				z, x := big.NewFloat(s), big.NewFloat(a)
+				got := bigfloat.HurwitzZeta(new(big.Float).SetPrec(prec), z, x)
+				want := bigfloat.HurwitzZeta(new(big.Float).SetPrec(prec+64), z, big.NewFloat(a+1))
+				p := bigfloat.Pow(new(big.Float).SetPrec(prec+64), new(big.Float).SetPrec(prec+64).Set(x), new(big.Float).Neg(z))
+				want.Add(want, p)
+				if !closeTo(got, want, prec-4) {
+					t.Errorf("prec = %d: HurwitzZeta(%g, %g) =\ngot  %g;\nwant %g", prec, s, a, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestZetaPrecision(t *testing.T) {
+	nearOne := new(big.Float).SetPrec(100).SetInt64(1)
+	nearOne.Add(nearOne, new(big.Float).SetMantExp(big.NewFloat(1), -60))
+	for _, s := range []*big.Float{
+		big.NewFloat(0.5),
+		big.NewFloat(-13.25),
+		big.NewFloat(-201.5),
+		big.NewFloat(1e-20),
+		big.NewFloat(5000.5),
+		nearOne,
+	} {
+		want := bigfloat.Zeta(new(big.Float).SetPrec(600), s)
+		for _, prec := range []uint{24, 53, 100, 300} {
+			got := bigfloat.Zeta(new(big.Float).SetPrec(prec), s)
+			if !closeTo(got, want, prec-2) {
+				t.Errorf("prec = %d: Zeta(%g) =\ngot  %g;\nwant %g", prec, s, got, want)
+			}
+		}
+	}
+}
+
+func TestZetaSpecialValues(t *testing.T) {
+	for _, test := range []struct {
+		s, a float64
+		want float64
+	}{
+		{math.Inf(1), 1, 1},
+		{0, 1, -0.5},
+		{-2, 1, 0},
+		{-1e10, 1, 0},
+		{-4, 0.5, 0},
+		{0, 0.25, 0.25},
+		{math.Inf(1), 0.5, math.Inf(1)},
+		{math.Inf(1), 2, 0},
+		{-1, 0.25, 1.0 / 96},
+		{-2, 0.25, -1.0 / 64},
+	} {
+		x64, acc := bigfloat.HurwitzZeta(new(big.Float), big.NewFloat(test.s), big.NewFloat(test.a)).Float64()
+		if x64 != test.want || math.Signbit(x64) != math.Signbit(test.want) || acc != big.Exact {
+			t.Errorf("HurwitzZeta(%g, %g) =\n got %g (%s);\nwant %g (Exact)", test.s, test.a, x64, acc, test.want)
+		}
+	}
+	for _, test := range []struct {
+		s, a float64
+	}{
+		{1, 1},
+		{1, 0.5},
+		{1, 3},
+		{math.Inf(-1), 1},
+		{math.Inf(-1), 2},
+		{2, 0},
+		{2, -1},
+		{2, math.Inf(1)},
+	} {
+		expectNaN(t, fmt.Sprintf("HurwitzZeta(%g, %g)", test.s, test.a), func() {
+			bigfloat.HurwitzZeta(new(big.Float), big.NewFloat(test.s), big.NewFloat(test.a))
+		})
+	}
+	expectNaN(t, "Zeta(1)", func() { bigfloat.Zeta(new(big.Float), big.NewFloat(1)) })
+}
+
+// ---------- Benchmarks ----------
+
+func BenchmarkZeta(b *testing.B) {
+	for _, s := range []float64{3, 0.5} {
+		z := big.NewFloat(s)
+		for _, prec := range []uint{1e2, 1e3} {
+			o := new(big.Float).SetPrec(prec)
+			b.Run(fmt.Sprintf("%v/%v", s, prec), func(b *testing.B) {
+				b.ReportAllocs()
+				for n := 0; n < b.N; n++ {
+					bigfloat.Zeta(o, z)
+				}
+			})
+		}
+	}
+}